@@ -0,0 +1,469 @@
+package tiledb
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sync"
+)
+
+// QueryRange is a range [Start, End] on the dimension at index Dimension.
+// Start and End have the type of the dimension, or are []byte for
+// variable sized dimensions
+type QueryRange struct {
+	Dimension uint32
+	Start     interface{}
+	End       interface{}
+}
+
+// addQueryRanges adds ranges to a query. Ranges on the same dimension are
+// combined as a multi-range
+func addQueryRanges(query *Query, ranges []QueryRange) error {
+	for _, r := range ranges {
+		var err error
+		if reflect.TypeOf(r.Start).Kind() == reflect.Slice {
+			err = query.AddRangeVar(r.Dimension, r.Start, r.End)
+		} else {
+			err = query.AddRange(r.Dimension, r.Start, r.End)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Aggregate holds the aggregates of the values of an attribute. Count is the
+// number of values, which is the number of cells for attributes with a single
+// value per cell.
+type Aggregate struct {
+	Count uint64
+	// Sum is the sum of the values. For integer and datetime attributes it
+	// is IntegerSum rounded to the nearest float64.
+	Sum float64
+	// IntegerSum is the exact sum of the values of integer and datetime
+	// attributes, nil for floating point attributes
+	IntegerSum *big.Int
+	// Min and Max are rounded to float64, so integers above 2^53 lose
+	// precision
+	Min float64
+	Max float64
+	// Histogram holds the number of values per bin when histogram bins are
+	// requested. Bins are of equal width between HistogramMin and
+	// HistogramMax, values outside of the bounds are not counted.
+	Histogram    []uint64
+	HistogramMin float64
+	HistogramMax float64
+
+	// term holds the integer added to IntegerSum, avoiding an allocation
+	// per value
+	term big.Int
+}
+
+// Mean returns the mean of the values, NaN if there are none
+func (a *Aggregate) Mean() float64 {
+	if a.Count == 0 {
+		return math.NaN()
+	}
+	return a.Sum / float64(a.Count)
+}
+
+// add adds a value to the aggregates
+func (a *Aggregate) add(value float64) {
+	if a.Count == 0 || value < a.Min {
+		a.Min = value
+	}
+	if a.Count == 0 || value > a.Max {
+		a.Max = value
+	}
+	a.Count++
+	a.Sum += value
+
+	bins := len(a.Histogram)
+	if bins == 0 || value < a.HistogramMin || value > a.HistogramMax {
+		return
+	}
+	bin := bins - 1
+	if width := a.HistogramMax - a.HistogramMin; width > 0 && value < a.HistogramMax {
+		bin = int(float64(bins) * (value - a.HistogramMin) / width)
+		if bin >= bins {
+			bin = bins - 1
+		}
+	}
+	a.Histogram[bin]++
+}
+
+// addInt adds a signed integer value to the aggregates, keeping its exact
+// sum
+func (a *Aggregate) addInt(value int64) {
+	if a.IntegerSum == nil {
+		a.IntegerSum = new(big.Int)
+	}
+	a.IntegerSum.Add(a.IntegerSum, a.term.SetInt64(value))
+	a.add(float64(value))
+}
+
+// addUint adds an unsigned integer value to the aggregates, keeping its
+// exact sum
+func (a *Aggregate) addUint(value uint64) {
+	if a.IntegerSum == nil {
+		a.IntegerSum = new(big.Int)
+	}
+	a.IntegerSum.Add(a.IntegerSum, a.term.SetUint64(value))
+	a.add(float64(value))
+}
+
+// roundSum sets Sum to the rounded exact sum of integer values
+func (a *Aggregate) roundSum() {
+	if a.IntegerSum != nil {
+		a.Sum, _ = new(big.Float).SetInt(a.IntegerSum).Float64()
+	}
+}
+
+// merge adds the aggregates of b, computed with the same histogram bounds
+func (a *Aggregate) merge(b *Aggregate) {
+	if b.Count == 0 {
+		return
+	}
+	if a.Count == 0 || b.Min < a.Min {
+		a.Min = b.Min
+	}
+	if a.Count == 0 || b.Max > a.Max {
+		a.Max = b.Max
+	}
+	a.Count += b.Count
+	a.Sum += b.Sum
+	if b.IntegerSum != nil {
+		if a.IntegerSum == nil {
+			a.IntegerSum = new(big.Int)
+		}
+		a.IntegerSum.Add(a.IntegerSum, b.IntegerSum)
+		a.roundSum()
+	}
+	for i := range b.Histogram {
+		a.Histogram[i] += b.Histogram[i]
+	}
+}
+
+// addValues adds every element of a numeric slice to the aggregates.
+// Integers are summed exactly.
+func (a *Aggregate) addValues(data interface{}) error {
+	switch values := data.(type) {
+	case []int8:
+		for _, v := range values {
+			a.addInt(int64(v))
+		}
+	case []int16:
+		for _, v := range values {
+			a.addInt(int64(v))
+		}
+	case []int32:
+		for _, v := range values {
+			a.addInt(int64(v))
+		}
+	case []int64:
+		for _, v := range values {
+			a.addInt(v)
+		}
+	case []uint8:
+		for _, v := range values {
+			a.addUint(uint64(v))
+		}
+	case []uint16:
+		for _, v := range values {
+			a.addUint(uint64(v))
+		}
+	case []uint32:
+		for _, v := range values {
+			a.addUint(uint64(v))
+		}
+	case []uint64:
+		for _, v := range values {
+			a.addUint(v)
+		}
+	case []float32:
+		for _, v := range values {
+			a.add(float64(v))
+		}
+	case []float64:
+		for _, v := range values {
+			a.add(v)
+		}
+	default:
+		return fmt.Errorf("Can not aggregate values of type %T", data)
+	}
+	a.roundSum()
+	return nil
+}
+
+// AggregateOptions configures Array.Aggregate
+type AggregateOptions struct {
+	// Ranges restricts the cells aggregated, all cells are aggregated if it
+	// is empty. Ranges on the same dimension form a multi-range and should
+	// not overlap.
+	Ranges []QueryRange
+	// Parallel runs one query per range of the dimension with the most
+	// ranges concurrently and merges the results
+	Parallel bool
+	// BatchSize is the number of cells read per query, DefaultBatchSize if 0
+	BatchSize uint64
+	// HistogramBins is the number of histogram bins, no histogram is
+	// computed if 0
+	HistogramBins uint
+	// HistogramMin and HistogramMax are the bounds of the histogram. If they
+	// are equal the bounds are the minimum and maximum of each attribute,
+	// which requires an additional pass over the data.
+	HistogramMin float64
+	HistogramMax float64
+}
+
+// DefaultBatchSize is the number of cells read per query by helpers reading
+// whole arrays when no batch size is given
+const DefaultBatchSize = 10000
+
+/*
+Aggregator computes the aggregates of attributes over the batches of a read
+query. Numeric and datetime attributes are supported; datetimes are
+aggregated as their integer value.
+
+	aggregator := NewAggregator([]string{"a1"}, 0, 0, 0)
+	err = reader.ReadAll(aggregator.Add)
+	mean := aggregator.Results()["a1"].Mean()
+*/
+type Aggregator struct {
+	names   []string
+	results map[string]*Aggregate
+}
+
+// NewAggregator creates an aggregator for the given attributes. bins is the
+// number of histogram bins between histogramMin and histogramMax, 0 for no
+// histogram.
+func NewAggregator(names []string, bins uint, histogramMin, histogramMax float64) *Aggregator {
+	aggregator := Aggregator{names: names, results: make(map[string]*Aggregate)}
+	for _, name := range names {
+		aggregate := Aggregate{HistogramMin: histogramMin, HistogramMax: histogramMax}
+		if bins > 0 {
+			aggregate.Histogram = make([]uint64, bins)
+		}
+		aggregator.results[name] = &aggregate
+	}
+	return &aggregator
+}
+
+// Add adds the values of a batch to the aggregates
+func (g *Aggregator) Add(batch *Batch) error {
+	for _, name := range g.names {
+		field, err := batch.Field(name)
+		if err != nil {
+			return err
+		}
+		if field.Datatype.IsString() {
+			return fmt.Errorf("Can not aggregate string field %s", name)
+		}
+
+		data, err := batch.Data(name)
+		if err != nil {
+			return err
+		}
+
+		err = g.results[name].addValues(data)
+		if err != nil {
+			return fmt.Errorf("Error aggregating %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+// Results returns the aggregates by attribute name
+func (g *Aggregator) Results() map[string]*Aggregate {
+	return g.results
+}
+
+// newRangeReader creates a BatchReader over the given ranges, in row major
+// order for dense arrays and unordered for sparse arrays
+func (a *Array) newRangeReader(names []string, ranges []QueryRange, batchSize uint64) (*BatchReader, error) {
+	schema, err := a.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+	arrayType, err := schema.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := NewBatchReader(a.context, a, names, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := TILEDB_UNORDERED
+	if arrayType == TILEDB_DENSE {
+		layout = TILEDB_ROW_MAJOR
+	}
+	err = reader.Query().SetLayout(layout)
+	if err != nil {
+		reader.Free()
+		return nil, err
+	}
+
+	err = addQueryRanges(reader.Query(), ranges)
+	if err != nil {
+		reader.Free()
+		return nil, err
+	}
+	return reader, nil
+}
+
+// aggregate runs one read query over the given ranges
+func (a *Array) aggregate(attributes []string, ranges []QueryRange, batchSize uint64, bins uint, bounds map[string][2]float64) (*Aggregator, error) {
+	reader, err := a.newRangeReader(attributes, ranges, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Free()
+
+	aggregator := NewAggregator(attributes, bins, 0, 0)
+	for name, bound := range bounds {
+		aggregator.results[name].HistogramMin = bound[0]
+		aggregator.results[name].HistogramMax = bound[1]
+	}
+
+	err = reader.ReadAll(aggregator.Add)
+	if err != nil {
+		return nil, err
+	}
+	return aggregator, nil
+}
+
+// splitRanges splits ranges into one set of ranges per range of the
+// dimension with the most ranges
+func splitRanges(ranges []QueryRange) [][]QueryRange {
+	counts := make(map[uint32]int)
+	var split uint32
+	for _, r := range ranges {
+		counts[r.Dimension]++
+		if counts[r.Dimension] > counts[split] {
+			split = r.Dimension
+		}
+	}
+	if counts[split] <= 1 {
+		return [][]QueryRange{ranges}
+	}
+
+	others := make([]QueryRange, 0)
+	for _, r := range ranges {
+		if r.Dimension != split {
+			others = append(others, r)
+		}
+	}
+
+	parts := make([][]QueryRange, 0)
+	for _, r := range ranges {
+		if r.Dimension == split {
+			parts = append(parts, append([]QueryRange{r}, others...))
+		}
+	}
+	return parts
+}
+
+// aggregateRanges aggregates the ranges, running one query per part in
+// parallel if requested
+func (a *Array) aggregateRanges(attributes []string, options *AggregateOptions, bins uint, bounds map[string][2]float64) (*Aggregator, error) {
+	batchSize := options.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	if !options.Parallel {
+		return a.aggregate(attributes, options.Ranges, batchSize, bins, bounds)
+	}
+
+	parts := splitRanges(options.Ranges)
+	aggregators := make([]*Aggregator, len(parts))
+	errs := make([]error, len(parts))
+	var wg sync.WaitGroup
+	for i, part := range parts {
+		wg.Add(1)
+		go func(i int, part []QueryRange) {
+			defer wg.Done()
+			aggregators[i], errs[i] = a.aggregate(attributes, part, batchSize, bins, bounds)
+		}(i, part)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := aggregators[0]
+	for _, aggregator := range aggregators[1:] {
+		for name, aggregate := range aggregator.results {
+			result.results[name].merge(aggregate)
+		}
+	}
+	return result, nil
+}
+
+// histogramBounds returns the histogram bounds of each attribute, running a
+// first pass over the data if options sets no bounds
+func (a *Array) histogramBounds(attributes []string, options *AggregateOptions) (map[string][2]float64, error) {
+	if options.HistogramBins == 0 {
+		return nil, nil
+	}
+
+	bounds := make(map[string][2]float64)
+	if options.HistogramMin < options.HistogramMax {
+		for _, name := range attributes {
+			bounds[name] = [2]float64{options.HistogramMin, options.HistogramMax}
+		}
+		return bounds, nil
+	}
+
+	aggregator, err := a.aggregateRanges(attributes, options, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, aggregate := range aggregator.results {
+		bounds[name] = [2]float64{aggregate.Min, aggregate.Max}
+	}
+	return bounds, nil
+}
+
+/*
+Aggregate computes the count, sum, minimum, maximum, mean and optionally a
+histogram of numeric attributes. The array must be open in READ mode. The
+results are streamed through incomplete queries so memory use is bounded by
+the batch size.
+
+	results, err := array.Aggregate([]string{"a1"}, &AggregateOptions{
+		Ranges: []QueryRange{
+			{Dimension: 0, Start: int32(1), End: int32(10)},
+			{Dimension: 0, Start: int32(20), End: int32(30)},
+		},
+		Parallel: true,
+	})
+*/
+func (a *Array) Aggregate(attributes []string, options *AggregateOptions) (map[string]*Aggregate, error) {
+	if options == nil {
+		options = &AggregateOptions{}
+	}
+
+	if len(attributes) == 0 {
+		return nil, fmt.Errorf("Error aggregating array: no attributes given")
+	}
+
+	bounds, err := a.histogramBounds(attributes, options)
+	if err != nil {
+		return nil, fmt.Errorf("Error aggregating array: %s", err)
+	}
+
+	aggregator, err := a.aggregateRanges(attributes, options, options.HistogramBins, bounds)
+	if err != nil {
+		return nil, fmt.Errorf("Error aggregating array: %s", err)
+	}
+	return aggregator.Results(), nil
+}
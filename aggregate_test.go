@@ -0,0 +1,127 @@
+package tiledb
+
+import (
+	"math"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregator(t *testing.T) {
+	batch := &Batch{
+		Fields: []BatchField{
+			{Name: "a1", Datatype: TILEDB_FLOAT64, CellValNum: 2},
+			{Name: "a2", Datatype: TILEDB_STRING_ASCII, CellValNum: TILEDB_VAR_NUM},
+		},
+		NumCells: 2,
+		offsets:  map[string][]uint64{"a2": {0, 1}},
+		data: map[string]interface{}{
+			"a1": []float64{0.5, 4, 1, 2.5},
+			"a2": []byte("ab"),
+		},
+	}
+
+	aggregator := NewAggregator([]string{"a1"}, 4, 0, 4)
+	assert.Nil(t, aggregator.Add(batch))
+	aggregate := aggregator.Results()["a1"]
+	assert.Equal(t, uint64(4), aggregate.Count)
+	assert.Equal(t, 8.0, aggregate.Sum)
+	assert.Equal(t, 0.5, aggregate.Min)
+	assert.Equal(t, 4.0, aggregate.Max)
+	assert.Equal(t, 2.0, aggregate.Mean())
+	assert.Equal(t, []uint64{1, 1, 1, 1}, aggregate.Histogram)
+
+	// Strings can not be aggregated
+	assert.NotNil(t, NewAggregator([]string{"a2"}, 0, 0, 0).Add(batch))
+
+	empty := Aggregate{}
+	assert.True(t, math.IsNaN(empty.Mean()))
+	assert.Nil(t, aggregate.IntegerSum)
+}
+
+func TestAggregatorIntegerSum(t *testing.T) {
+	batch := &Batch{
+		Fields: []BatchField{
+			{Name: "a1", Datatype: TILEDB_INT64, CellValNum: 1},
+			{Name: "a2", Datatype: TILEDB_UINT64, CellValNum: 1},
+		},
+		NumCells: 3,
+		data: map[string]interface{}{
+			"a1": []int64{1 << 53, 1, 1},
+			"a2": []uint64{math.MaxUint64, math.MaxUint64, 2},
+		},
+	}
+
+	// Integers are summed exactly, beyond the precision of float64 and the
+	// range of 64 bits
+	aggregator := NewAggregator([]string{"a1", "a2"}, 0, 0, 0)
+	assert.Nil(t, aggregator.Add(batch))
+	a1 := aggregator.Results()["a1"]
+	assert.Equal(t, 0, big.NewInt(1<<53+2).Cmp(a1.IntegerSum))
+	assert.Equal(t, float64(1<<53+2), a1.Sum)
+
+	expected, _ := new(big.Int).SetString("36893488147419103232", 10)
+	a2 := aggregator.Results()["a2"]
+	assert.Equal(t, 0, expected.Cmp(a2.IntegerSum))
+
+	// Merged sums stay exact
+	other := NewAggregator([]string{"a1"}, 0, 0, 0)
+	assert.Nil(t, other.Add(batch))
+	a1.merge(other.Results()["a1"])
+	assert.Equal(t, 0, big.NewInt(1<<54+4).Cmp(a1.IntegerSum))
+	assert.Equal(t, float64(1<<54+4), a1.Sum)
+}
+
+func TestArrayAggregate(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_array_aggregate")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	// One query per range of rows, histogram bounds computed from the data
+	results, err := array.Aggregate([]string{"a1", "a3"}, &AggregateOptions{
+		Ranges: []QueryRange{
+			{Dimension: 0, Start: int32(1), End: int32(1)},
+			{Dimension: 0, Start: int32(2), End: int32(2)},
+		},
+		Parallel:      true,
+		BatchSize:     1,
+		HistogramBins: 2,
+	})
+	assert.Nil(t, err)
+
+	a1 := results["a1"]
+	assert.Equal(t, uint64(3), a1.Count)
+	assert.Equal(t, 6.0, a1.Sum)
+	assert.Equal(t, 1.0, a1.Min)
+	assert.Equal(t, 3.0, a1.Max)
+	assert.Equal(t, 2.0, a1.Mean())
+	assert.Equal(t, []uint64{1, 2}, a1.Histogram)
+
+	// Datetimes are aggregated as integers
+	assert.Equal(t, 18262.0, results["a3"].Min)
+
+	results, err = array.Aggregate([]string{"a1"}, &AggregateOptions{
+		Ranges: []QueryRange{{Dimension: 1, Start: int32(2), End: int32(2)}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), results["a1"].Count)
+	assert.Equal(t, 3.0, results["a1"].Sum)
+	assert.Nil(t, results["a1"].Histogram)
+
+	_, err = array.Aggregate([]string{"a2"}, nil)
+	assert.NotNil(t, err)
+}
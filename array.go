@@ -11,9 +11,11 @@ import "C"
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"reflect"
 	"runtime"
 	"strconv"
+	"time"
 	"unsafe"
 )
 
@@ -59,6 +61,7 @@ func NewArray(ctx *Context, uri string) (*Array, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb array: %s", array.context.LastError())
 	}
+	trackHandle("tiledb_array_t", unsafe.Pointer(array.tiledbArray))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&array, func(array *Array) {
@@ -72,6 +75,7 @@ func NewArray(ctx *Context, uri string) (*Array, error) {
 func (a *Array) Free() {
 	if a.tiledbArray != nil {
 		a.Close()
+		untrackHandle(unsafe.Pointer(a.tiledbArray))
 		C.tiledb_array_free(&a.tiledbArray)
 	}
 }
@@ -85,8 +89,19 @@ type. Also there may be many different Array objects created and opened with
 different query types. For instance, one may create and open an array object
 array_read for reads and another one array_write for writes, and interleave
 creation and submission of queries for both these array objects.
+
+If the context has a KeyProvider returning a key for the array, the array is
+opened with OpenWithKey.
 */
 func (a *Array) Open(queryType QueryType) error {
+	encryptionType, key, err := a.context.encryptionKey(a.uri)
+	if err != nil {
+		return fmt.Errorf("Error opening tiledb array for querying: %s", err)
+	}
+	if key != "" {
+		return a.OpenWithKey(queryType, encryptionType, key)
+	}
+
 	ret := C.tiledb_array_open(a.context.tiledbContext, a.tiledbArray, C.tiledb_query_type_t(queryType))
 	if ret != C.TILEDB_OK {
 		return fmt.Errorf("Error opening tiledb array for querying: %s", a.context.LastError())
@@ -122,6 +137,14 @@ function is useful to ensure consistency at a potential distributed
 setting, where machines need to operate on the same view of the array.
 */
 func (a *Array) OpenAt(queryType QueryType, timestamp uint64) error {
+	encryptionType, key, err := a.context.encryptionKey(a.uri)
+	if err != nil {
+		return fmt.Errorf("Error opening tiledb array at %d for querying: %s", timestamp, err)
+	}
+	if key != "" {
+		return a.OpenAtWithKey(queryType, encryptionType, key, timestamp)
+	}
+
 	ret := C.tiledb_array_open_at(a.context.tiledbContext, a.tiledbArray, C.tiledb_query_type_t(queryType), C.uint64_t(timestamp))
 	if ret != C.TILEDB_OK {
 		return fmt.Errorf("Error opening tiledb array at %d for querying: %s", timestamp, a.context.LastError())
@@ -163,8 +186,72 @@ func (a *Array) Reopen() error {
 	return nil
 }
 
-// Close a tiledb array, this is called on garbage collection automatically
+// timestampFromTime converts a time to a tiledb timestamp, in milliseconds
+// since 1970-01-01 00:00:00 +0000 (UTC)
+func timestampFromTime(t time.Time) uint64 {
+	return uint64(t.UnixNano() / int64(time.Millisecond))
+}
+
+// timeFromTimestamp converts a tiledb timestamp to a time
+func timeFromTimestamp(timestamp uint64) time.Time {
+	return time.Unix(0, int64(timestamp)*int64(time.Millisecond))
+}
+
+// OpenAtTime is similar to OpenAt, but takes the timestamp as a time.Time.
+// Timestamps have a millisecond precision, t is truncated to the
+// millisecond.
+func (a *Array) OpenAtTime(queryType QueryType, t time.Time) error {
+	return a.OpenAt(queryType, timestampFromTime(t))
+}
+
+/*
+OpenBetween opens the array with a view of the writes/updates that happened
+between start and end. The core in this version only supports opening an
+array at an end timestamp, which includes all writes since the creation of
+the array, so start must be the zero time.Time or the Unix epoch, otherwise
+an error is returned.
+*/
+func (a *Array) OpenBetween(queryType QueryType, start time.Time, end time.Time) error {
+	if !start.IsZero() && timestampFromTime(start) != 0 {
+		return fmt.Errorf("Error opening tiledb array between %s and %s: only opening from the creation of the array is supported", start, end)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("Error opening tiledb array between %s and %s: end is before start", start, end)
+	}
+	return a.OpenAtTime(queryType, end)
+}
+
+// ReopenAt reopens the array (the array must be already open) at a
+// timestamp, in milliseconds since 1970-01-01 00:00:00 +0000 (UTC)
+func (a *Array) ReopenAt(timestamp uint64) error {
+	ret := C.tiledb_array_reopen_at(a.context.tiledbContext, a.tiledbArray, C.uint64_t(timestamp))
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("Error reopening tiledb array at %d for querying: %s", timestamp, a.context.LastError())
+	}
+	return nil
+}
+
+// Timestamp returns the timestamp an open array is pinned to: the timestamp
+// passed when opening it at a timestamp, or the time it was opened
+// otherwise. Opening another Array at this timestamp gives the same view of
+// the array.
+func (a *Array) Timestamp() (time.Time, error) {
+	var timestamp C.uint64_t
+	ret := C.tiledb_array_get_timestamp(a.context.tiledbContext, a.tiledbArray, &timestamp)
+	if ret != C.TILEDB_OK {
+		return time.Time{}, fmt.Errorf("Error getting timestamp of tiledb array: %s", a.context.LastError())
+	}
+	return timeFromTimestamp(uint64(timestamp)), nil
+}
+
+// Close a tiledb array, this is called on garbage collection automatically.
+// Closing a closed or freed array is a no-op, so Array implements io.Closer.
+// Close does not free the array, which can be opened again, use Free or
+// Closer for that.
 func (a *Array) Close() error {
+	if a.tiledbArray == nil {
+		return nil
+	}
 	ret := C.tiledb_array_close(a.context.tiledbContext, a.tiledbArray)
 	if ret != C.TILEDB_OK {
 		return fmt.Errorf("Error closing tiledb array for querying: %s", a.context.LastError())
@@ -172,8 +259,37 @@ func (a *Array) Close() error {
 	return nil
 }
 
-// Create a new TileDB array given an input schema.
+// closerFunc adapts a function to io.Closer
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// Closer returns an io.Closer closing and freeing the array, returning the
+// error closing it. Closing twice is a no-op.
+//
+//	defer array.Closer().Close()
+func (a *Array) Closer() io.Closer {
+	return closerFunc(func() error {
+		err := a.Close()
+		a.Free()
+		return err
+	})
+}
+
+// Create a new TileDB array given an input schema. If the context has a
+// KeyProvider returning a key for the array, it is created with
+// CreateWithKey.
 func (a *Array) Create(arraySchema *ArraySchema) error {
+	encryptionType, key, err := a.context.encryptionKey(a.uri)
+	if err != nil {
+		return fmt.Errorf("Error creating tiledb array: %s", err)
+	}
+	if key != "" {
+		return a.CreateWithKey(arraySchema, encryptionType, key)
+	}
+
 	curi := C.CString(a.uri)
 	defer C.free(unsafe.Pointer(curi))
 	ret := C.tiledb_array_create(a.context.tiledbContext, curi, arraySchema.tiledbArraySchema)
@@ -204,6 +320,14 @@ func (a *Array) Consolidate(config *Config) error {
 		return fmt.Errorf("Config must not be nil for Consolidate")
 	}
 
+	encryptionType, key, err := a.context.encryptionKey(a.uri)
+	if err != nil {
+		return fmt.Errorf("Error consolidating tiledb array: %s", err)
+	}
+	if key != "" {
+		return a.ConsolidateWithKey(encryptionType, key, config)
+	}
+
 	curi := C.CString(a.uri)
 	defer C.free(unsafe.Pointer(curi))
 	ret := C.tiledb_array_consolidate(a.context.tiledbContext, curi, config.tiledbConfig)
@@ -241,6 +365,7 @@ func (a *Array) Schema() (*ArraySchema, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting schema for tiledb array: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_array_schema_t", unsafe.Pointer(arraySchema.tiledbArraySchema))
 	return &arraySchema, nil
 }
 
@@ -317,11 +442,13 @@ func (a *Array) NonEmptyDomain() ([]NonEmptyDomain, bool, error) {
 	if err != nil {
 		return nil, false, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, false, err
 	}
+	defer domain.Free()
 
 	ndims, err := domain.NDim()
 	if err != nil {
@@ -335,6 +462,7 @@ func (a *Array) NonEmptyDomain() ([]NonEmptyDomain, bool, error) {
 		if err != nil {
 			return nil, false, err
 		}
+		defer dimension.Free()
 
 		dimensionType, err := dimension.Type()
 		if err != nil {
@@ -385,11 +513,13 @@ func (a *Array) NonEmptyDomainVarFromName(dimName string) (*NonEmptyDomain, bool
 	if err != nil {
 		return nil, false, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, false, err
 	}
+	defer domain.Free()
 
 	hasDim, err := domain.HasDimension(dimName)
 	if err != nil {
@@ -404,6 +534,7 @@ func (a *Array) NonEmptyDomainVarFromName(dimName string) (*NonEmptyDomain, bool
 	if err != nil {
 		return nil, false, fmt.Errorf("Could not get dimension: %s", dimName)
 	}
+	defer dimension.Free()
 
 	dimType, err := dimension.Type()
 	if err != nil {
@@ -484,16 +615,19 @@ func (a *Array) NonEmptyDomainVarFromIndex(dimIdx uint) (*NonEmptyDomain, bool,
 	if err != nil {
 		return nil, false, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, false, err
 	}
+	defer domain.Free()
 
 	dimension, err := domain.DimensionFromIndex(dimIdx)
 	if err != nil {
 		return nil, false, fmt.Errorf("Could not get dimension having index: %d", dimIdx)
 	}
+	defer dimension.Free()
 
 	dimType, err := dimension.Type()
 	if err != nil {
@@ -570,11 +704,13 @@ func (a *Array) NonEmptyDomainFromName(dimName string) (*NonEmptyDomain, bool, e
 	if err != nil {
 		return nil, false, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, false, err
 	}
+	defer domain.Free()
 
 	hasDim, err := domain.HasDimension(dimName)
 	if err != nil {
@@ -589,6 +725,7 @@ func (a *Array) NonEmptyDomainFromName(dimName string) (*NonEmptyDomain, bool, e
 	if err != nil {
 		return nil, false, fmt.Errorf("Could not get dimension: %s", dimName)
 	}
+	defer dimension.Free()
 
 	dimensionType, err := dimension.Type()
 	if err != nil {
@@ -633,16 +770,19 @@ func (a *Array) NonEmptyDomainFromIndex(dimIdx uint) (*NonEmptyDomain, bool, err
 	if err != nil {
 		return nil, false, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, false, err
 	}
+	defer domain.Free()
 
 	dimension, err := domain.DimensionFromIndex(dimIdx)
 	if err != nil {
 		return nil, false, fmt.Errorf("Could not get dimension: %d", dimIdx)
 	}
+	defer dimension.Free()
 
 	dimensionType, err := dimension.Type()
 	if err != nil {
@@ -684,12 +824,14 @@ func (a *Array) MaxBufferSize(attributeName string, subarray interface{}) (uint6
 	if err != nil {
 		return 0, err
 	}
+	defer schema.Free()
 
 	// Get domain from schema
 	domain, err := schema.Domain()
 	if err != nil {
 		return 0, err
 	}
+	defer domain.Free()
 
 	// Get domain type to switch on
 	domainType, err := domain.Type()
@@ -750,12 +892,14 @@ func (a *Array) MaxBufferSizeVar(attributeName string, subarray interface{}) (ui
 	if err != nil {
 		return 0, 0, err
 	}
+	defer schema.Free()
 
 	// Get domain from schema
 	domain, err := schema.Domain()
 	if err != nil {
 		return 0, 0, err
 	}
+	defer domain.Free()
 
 	// Get domain type to switch on
 	domainType, err := domain.Type()
@@ -827,11 +971,13 @@ func (a *Array) MaxBufferElements(subarray interface{}) (map[string][2]uint64, e
 	if err != nil {
 		return nil, fmt.Errorf("Error getting MaxBufferElements for array: %s", err)
 	}
+	defer schema.Free()
 
 	attributes, err := schema.Attributes()
 	if err != nil {
 		return nil, fmt.Errorf("Error getting MaxBufferElements for array: %s", err)
 	}
+	defer freeAttributes(attributes)
 	// Loop through each attribute
 	for _, attribute := range attributes {
 
@@ -877,6 +1023,7 @@ func (a *Array) MaxBufferElements(subarray interface{}) (map[string][2]uint64, e
 	if err != nil {
 		return nil, fmt.Errorf("Could not get domain for MaxBufferElements: %s", err)
 	}
+	defer domain.Free()
 	domainType, err := domain.Type()
 	if err != nil {
 		return nil, fmt.Errorf("Could not get domainType for MaxBufferElements: %s", err)
@@ -1231,6 +1378,14 @@ func (a *Array) GetMetadataMapWithValueLimit(limit *uint) (map[string]*ArrayMeta
 // You must first finalize all queries to the array before consolidation can
 // begin (as consolidation temporarily acquires an exclusive lock on the array).
 func (a *Array) ConsolidateMetadata(config *Config) error {
+	encryptionType, key, err := a.context.encryptionKey(a.uri)
+	if err != nil {
+		return fmt.Errorf("Error consolidating array metadata: %s", err)
+	}
+	if key != "" {
+		return a.ConsolidateMetadataWithKey(encryptionType, key, config)
+	}
+
 	curi := C.CString(a.uri)
 	defer C.free(unsafe.Pointer(curi))
 
@@ -0,0 +1,560 @@
+package tiledb
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// copyChunkSize is the number of bytes read and written at once when copying
+// files
+const copyChunkSize = 8 << 20
+
+// CopyProgress reports the progress of CopyArray
+type CopyProgress struct {
+	// BytesCopied and BytesTotal count the bytes of the files of the array
+	// when it is copied verbatim
+	BytesCopied uint64
+	BytesTotal  uint64
+	// CellsCopied counts the cells written when the array is rewritten
+	CellsCopied uint64
+}
+
+// CopyOptions configures CopyArray
+type CopyOptions struct {
+	// Schema, if set, is the schema of the new array. The cells are read
+	// from the source array and written to the new array, which must have
+	// attributes and dimensions of the same names and types.
+	Schema *ArraySchema
+	// SourceEncryptionType and SourceKey open an encrypted source array
+	SourceEncryptionType EncryptionType
+	SourceKey            string
+	// EncryptionType and Key create an encrypted new array
+	EncryptionType EncryptionType
+	Key            string
+	// BatchSize is the number of cells read and written at once when the
+	// array is rewritten, DefaultBatchSize if 0
+	BatchSize uint64
+	// Progress, if set, is called after each file chunk or batch of cells
+	// copied
+	Progress func(progress CopyProgress)
+}
+
+// openArray opens the array at uri, with an encryption key if set
+func openArray(ctx *Context, uri string, queryType QueryType, encryptionType EncryptionType, key string) (*Array, error) {
+	array, err := NewArray(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if key != "" {
+		err = array.OpenWithKey(queryType, encryptionType, key)
+	} else {
+		err = array.Open(queryType)
+	}
+	if err != nil {
+		array.Free()
+		return nil, err
+	}
+	return array, nil
+}
+
+/*
+CopyArray copies the array at srcURI to dstURI, which may be on another
+backend (e.g. from local disk to S3). dstURI must not exist.
+
+Without options the files of the array are copied verbatim through the VFS,
+preserving fragments, metadata and time travel. When a new schema or a
+different encryption key is given, the cells and metadata are instead read
+from the source array and rewritten into a new array, which lets filters,
+tiling and encryption be changed:
+
+	err = CopyArray(ctx, "data/array", "s3://bucket/array", &CopyOptions{
+		EncryptionType: TILEDB_AES_256_GCM,
+		Key:            key,
+		Progress: func(progress CopyProgress) {
+			log.Printf("%d cells copied", progress.CellsCopied)
+		},
+	})
+*/
+func CopyArray(ctx *Context, srcURI string, dstURI string, options *CopyOptions) error {
+	var opts CopyOptions
+	if options != nil {
+		opts = *options
+	}
+
+	if opts.Schema == nil && opts.EncryptionType == opts.SourceEncryptionType && opts.Key == opts.SourceKey {
+		err := copyTree(ctx, srcURI, dstURI, opts.Progress)
+		if err != nil {
+			return fmt.Errorf("Error copying array %s to %s: %s", srcURI, dstURI, err)
+		}
+		return nil
+	}
+
+	err := rewriteArray(ctx, srcURI, dstURI, opts)
+	if err != nil {
+		return fmt.Errorf("Error copying array %s to %s: %s", srcURI, dstURI, err)
+	}
+	return nil
+}
+
+// joinURI appends the base name of a URI listed by VFS.List to a directory
+func joinURI(dir string, uri string) string {
+	return strings.TrimSuffix(dir, "/") + "/" + path.Base(strings.TrimSuffix(uri, "/"))
+}
+
+// copyTree copies a directory recursively through the VFS. A partial copy
+// is removed on error.
+func copyTree(ctx *Context, src string, dst string, fn func(progress CopyProgress)) (err error) {
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+	defer config.Free()
+
+	vfs, err := NewVFS(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer vfs.Free()
+
+	isDir, err := vfs.IsDir(src)
+	if err != nil {
+		return err
+	}
+	if !isDir {
+		return fmt.Errorf("%s is not a directory", src)
+	}
+	isDir, err = vfs.IsDir(dst)
+	if err != nil {
+		return err
+	}
+	if isDir {
+		return fmt.Errorf("%s already exists", dst)
+	}
+	defer func() {
+		if err != nil {
+			vfs.RemoveDir(dst)
+		}
+	}()
+
+	var progress CopyProgress
+	progress.BytesTotal, err = vfs.DirSize(src)
+	if err != nil {
+		return err
+	}
+
+	var copyDir func(src string, dst string) error
+	copyDir = func(src string, dst string) error {
+		err := vfs.CreateDir(dst)
+		if err != nil {
+			return err
+		}
+
+		dirs, files, err := vfs.List(src)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			err = copyFile(vfs, file, joinURI(dst, file), &progress, fn)
+			if err != nil {
+				return err
+			}
+		}
+		for _, dir := range dirs {
+			err = copyDir(dir, joinURI(dst, dir))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return copyDir(src, dst)
+}
+
+// copyFile copies a file through the VFS in chunks
+func copyFile(vfs *VFS, src string, dst string, progress *CopyProgress, fn func(progress CopyProgress)) error {
+	size, err := vfs.FileSize(src)
+	if err != nil {
+		return err
+	}
+	if size == 0 {
+		return vfs.Touch(dst)
+	}
+
+	in, err := vfs.Open(src, TILEDB_VFS_READ)
+	if err != nil {
+		return err
+	}
+	defer in.Free()
+	defer vfs.Close(in)
+
+	out, err := vfs.Open(dst, TILEDB_VFS_WRITE)
+	if err != nil {
+		return err
+	}
+	defer out.Free()
+	closed := false
+	defer func() {
+		if !closed {
+			vfs.Close(out)
+		}
+	}()
+
+	for offset := uint64(0); offset < size; offset += copyChunkSize {
+		n := size - offset
+		if n > copyChunkSize {
+			n = copyChunkSize
+		}
+
+		chunk, err := vfs.Read(in, offset, n)
+		if err != nil {
+			return err
+		}
+		err = vfs.Write(out, chunk)
+		if err != nil {
+			return err
+		}
+
+		progress.BytesCopied += n
+		if fn != nil {
+			fn(*progress)
+		}
+	}
+
+	// Closing the file flushes it
+	closed = true
+	return vfs.Close(out)
+}
+
+// rewriteArray creates the array at dstURI and copies the cells and
+// metadata of the array at srcURI into it. The new array is removed if the
+// copy fails.
+func rewriteArray(ctx *Context, srcURI string, dstURI string, opts CopyOptions) (err error) {
+	src, err := openArray(ctx, srcURI, TILEDB_READ, opts.SourceEncryptionType, opts.SourceKey)
+	if err != nil {
+		return err
+	}
+	defer src.Free()
+
+	schema := opts.Schema
+	if schema == nil {
+		schema, err = src.Schema()
+		if err != nil {
+			return err
+		}
+		defer schema.Free()
+	}
+
+	dst, err := NewArray(ctx, dstURI)
+	if err != nil {
+		return err
+	}
+	defer dst.Free()
+
+	if opts.Key != "" {
+		err = dst.CreateWithKey(schema, opts.EncryptionType, opts.Key)
+	} else {
+		err = dst.Create(schema)
+	}
+	if err != nil {
+		return err
+	}
+	opened := false
+	defer func() {
+		if err == nil {
+			return
+		}
+		if opened {
+			dst.Close()
+		}
+		removeDir(ctx, dstURI)
+	}()
+
+	if opts.Key != "" {
+		err = dst.OpenWithKey(TILEDB_WRITE, opts.EncryptionType, opts.Key)
+	} else {
+		err = dst.Open(TILEDB_WRITE)
+	}
+	if err != nil {
+		return err
+	}
+	opened = true
+
+	transform, err := copyTransform(schema)
+	if err != nil {
+		return err
+	}
+
+	var progress CopyProgress
+	err = rewriteCells(ctx, src, dst, opts.BatchSize, transform, func(cells uint64) {
+		progress.CellsCopied += cells
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = copyMetadata(src, dst)
+	if err != nil {
+		return err
+	}
+	opened = false
+	return dst.Close()
+}
+
+// removeDir removes the directory at uri through the VFS
+func removeDir(ctx *Context, uri string) error {
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+	defer config.Free()
+
+	vfs, err := NewVFS(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer vfs.Free()
+
+	return vfs.RemoveDir(uri)
+}
+
+// copyMetadata copies all metadata items of an array opened in READ mode to
+// an array opened in WRITE mode
+func copyMetadata(src *Array, dst *Array) error {
+	entries, err := src.MetadataEntries()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err = dst.PutMetadataEntry(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchTransform converts a batch read from a source array into the buffers
+// (and offsets of variable sized fields) written to a destination array
+type batchTransform func(batch *Batch) (map[string]interface{}, map[string][]uint64, error)
+
+// copyTransform returns the transform writing the fields of schema from
+// batches holding fields of the same names and types. Dimensions are not
+// written to dense arrays.
+func copyTransform(schema *ArraySchema) (batchTransform, error) {
+	arrayType, err := schema.Type()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := schema.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(batch *Batch) (map[string]interface{}, map[string][]uint64, error) {
+		data := make(map[string]interface{})
+		offsets := make(map[string][]uint64)
+		for _, field := range fields {
+			if field.IsDimension && arrayType == TILEDB_DENSE {
+				continue
+			}
+
+			source, err := batch.Field(field.Name)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s is not an attribute or dimension of the source array", field.Name)
+			}
+			if source.Datatype != field.Datatype || source.CellValNum != field.CellValNum {
+				return nil, nil, fmt.Errorf("%s has a different datatype or number of values per cell in the source array", field.Name)
+			}
+
+			data[field.Name] = batch.data[field.Name]
+			if field.IsVar() {
+				offsets[field.Name] = batch.offsets[field.Name]
+			}
+		}
+		return data, offsets, nil
+	}, nil
+}
+
+// appendBatch appends copies of the cells of batch to result
+func appendBatch(result *Batch, batch *Batch) {
+	for _, field := range batch.Fields {
+		values := reflect.ValueOf(batch.data[field.Name])
+		if result.data[field.Name] == nil {
+			result.data[field.Name] = reflect.MakeSlice(values.Type(), 0, values.Len()).Interface()
+		}
+		data := reflect.ValueOf(result.data[field.Name])
+
+		if field.IsVar() {
+			base := uint64(data.Len()) * field.Datatype.Size()
+			for _, offset := range batch.offsets[field.Name] {
+				result.offsets[field.Name] = append(result.offsets[field.Name], base+offset)
+			}
+		}
+		result.data[field.Name] = reflect.AppendSlice(data, values).Interface()
+	}
+	result.NumCells += batch.NumCells
+}
+
+// subarrayFromRanges converts one range per dimension of the same type to a
+// subarray [start, end, ...]
+func subarrayFromRanges(ranges []QueryRange) interface{} {
+	subarray := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(ranges[0].Start)), 0, 2*len(ranges))
+	for _, r := range ranges {
+		subarray = reflect.Append(subarray, reflect.ValueOf(r.Start), reflect.ValueOf(r.End))
+	}
+	return subarray.Interface()
+}
+
+// rewriteCells reads all cells of src and writes the buffers returned by
+// transform to dst, one fragment per batch. Sparse arrays are read in
+// batches of unordered cells. Dense arrays are split along tile boundaries
+// into subarrays of about batchSize cells, each read in full and written to
+// the same subarray of dst if it is dense. progress is called with the
+// number of cells of each fragment written.
+func rewriteCells(ctx *Context, src *Array, dst *Array, batchSize uint64, transform batchTransform, progress func(cells uint64)) error {
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	srcSchema, err := src.Schema()
+	if err != nil {
+		return err
+	}
+	defer srcSchema.Free()
+	srcType, err := srcSchema.Type()
+	if err != nil {
+		return err
+	}
+	dstSchema, err := dst.Schema()
+	if err != nil {
+		return err
+	}
+	defer dstSchema.Free()
+	dstType, err := dstSchema.Type()
+	if err != nil {
+		return err
+	}
+	if srcType == TILEDB_SPARSE && dstType == TILEDB_DENSE {
+		return fmt.Errorf("A sparse array can not be rewritten into a dense array")
+	}
+
+	// Coordinates of dense arrays are only read when writing them to a
+	// sparse array
+	fields, err := srcSchema.Fields()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !field.IsDimension || dstType == TILEDB_SPARSE {
+			names = append(names, field.Name)
+		}
+	}
+
+	write := func(batch *Batch, subarray interface{}) error {
+		data, offsets, err := transform(batch)
+		if err != nil {
+			return err
+		}
+		if dstType == TILEDB_SPARSE {
+			subarray = nil
+		}
+		err = writeFragment(ctx, dst, fragment{subarray: subarray, data: data, offsets: offsets})
+		if err != nil {
+			return err
+		}
+		progress(batch.NumCells)
+		return nil
+	}
+
+	if srcType == TILEDB_SPARSE {
+		reader, err := NewBatchReader(ctx, src, names, batchSize)
+		if err != nil {
+			return err
+		}
+		defer reader.Free()
+
+		err = reader.Query().SetLayout(TILEDB_UNORDERED)
+		if err != nil {
+			return err
+		}
+		return reader.ReadAll(func(batch *Batch) error {
+			return write(batch, nil)
+		})
+	}
+
+	// Number of cells of the non empty domain
+	whole, err := src.partitionSubarray(nil, 1, TILEDB_ROW_MAJOR)
+	if err != nil {
+		return err
+	}
+	if len(whole) == 0 {
+		return nil
+	}
+	cells := uint64(1)
+	for _, r := range whole[0] {
+		start, err := integerValue(reflect.ValueOf(r.Start))
+		if err != nil {
+			return err
+		}
+		end, err := integerValue(reflect.ValueOf(r.End))
+		if err != nil {
+			return err
+		}
+		size, err := rangeCells(start, end)
+		if err != nil {
+			return err
+		}
+		cells, err = mulCells(cells, size)
+		if err != nil {
+			return err
+		}
+	}
+
+	partitions, err := src.partitionSubarray(nil, int((cells-1)/batchSize+1), TILEDB_ROW_MAJOR)
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		reader, err := NewBatchReader(ctx, src, names, batchSize)
+		if err != nil {
+			return err
+		}
+
+		err = reader.Query().SetLayout(TILEDB_ROW_MAJOR)
+		if err == nil {
+			err = addQueryRanges(reader.Query(), partition)
+		}
+
+		result := Batch{
+			Fields:  reader.Fields(),
+			offsets: make(map[string][]uint64),
+			data:    make(map[string]interface{}),
+		}
+		if err == nil {
+			err = reader.ReadAll(func(batch *Batch) error {
+				appendBatch(&result, batch)
+				return nil
+			})
+		}
+		reader.Free()
+		if err != nil {
+			return err
+		}
+
+		if result.NumCells > 0 {
+			err = write(&result, subarrayFromRanges(partition))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
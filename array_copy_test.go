@@ -0,0 +1,143 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readCopyTestArray reads a1 and a2 of an array created by
+// createBatchTestArray in row major order
+func readCopyTestArray(t *testing.T, context *Context, array *Array) ([]int32, []string) {
+	reader, err := NewBatchReader(context, array, []string{"a1", "a2"}, DefaultBatchSize)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+
+	a1 := make([]int32, 0)
+	a2 := make([]string, 0)
+	assert.Nil(t, reader.ReadAll(func(batch *Batch) error {
+		for i := uint64(0); i < batch.NumCells; i++ {
+			value, err := batch.Cell("a1", i)
+			assert.Nil(t, err)
+			a1 = append(a1, value.(int32))
+
+			str, err := batch.Cell("a2", i)
+			assert.Nil(t, err)
+			a2 = append(a2, str.(string))
+		}
+		return nil
+	}))
+	return a1, a2
+}
+
+func TestCopyArray(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_copy_array")
+	tmpCopyPath := path.Join(os.TempDir(), "tiledb_test_copy_array_copy")
+	tmpRewritePath := path.Join(os.TempDir(), "tiledb_test_copy_array_rewrite")
+	for _, p := range []string{tmpArrayPath, tmpCopyPath, tmpRewritePath} {
+		defer os.RemoveAll(p)
+		if _, err = os.Stat(p); err == nil {
+			os.RemoveAll(p)
+		}
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, array.PutMetadata("source", "sensor"))
+	assert.Nil(t, array.Close())
+
+	// Verbatim copy
+	var bytesProgress CopyProgress
+	err = CopyArray(context, tmpArrayPath, tmpCopyPath, &CopyOptions{
+		Progress: func(progress CopyProgress) {
+			bytesProgress = progress
+		},
+	})
+	assert.Nil(t, err)
+	assert.NotZero(t, bytesProgress.BytesTotal)
+	assert.Equal(t, bytesProgress.BytesTotal, bytesProgress.BytesCopied)
+
+	// The destination must not exist
+	assert.NotNil(t, CopyArray(context, tmpArrayPath, tmpCopyPath, nil))
+
+	copied, err := NewArray(context, tmpCopyPath)
+	assert.Nil(t, err)
+	assert.Nil(t, copied.Open(TILEDB_READ))
+	a1, a2 := readCopyTestArray(t, context, copied)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, a2)
+	_, _, value, err := copied.GetMetadata("source")
+	assert.Nil(t, err)
+	assert.Equal(t, "sensor", value)
+	assert.Nil(t, copied.Close())
+
+	// Rewrite into an encrypted array, one cell per fragment
+	key := "0123456789abcdeF0123456789abcdeF"
+	var cellsProgress CopyProgress
+	err = CopyArray(context, tmpArrayPath, tmpRewritePath, &CopyOptions{
+		EncryptionType: TILEDB_AES_256_GCM,
+		Key:            key,
+		BatchSize:      1,
+		Progress: func(progress CopyProgress) {
+			cellsProgress = progress
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), cellsProgress.CellsCopied)
+
+	rewritten, err := NewArray(context, tmpRewritePath)
+	assert.Nil(t, err)
+	// The key is required
+	assert.NotNil(t, rewritten.Open(TILEDB_READ))
+	assert.Nil(t, rewritten.OpenWithKey(TILEDB_READ, TILEDB_AES_256_GCM, key))
+	a1, a2 = readCopyTestArray(t, context, rewritten)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, a2)
+	_, _, value, err = rewritten.GetMetadata("source")
+	assert.Nil(t, err)
+	assert.Equal(t, "sensor", value)
+	assert.Nil(t, rewritten.Close())
+}
+
+func TestCopyArrayFailureRemovesDestination(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_copy_array_failure")
+	tmpCopyPath := path.Join(os.TempDir(), "tiledb_test_copy_array_failure_copy")
+	for _, p := range []string{tmpArrayPath, tmpCopyPath} {
+		defer os.RemoveAll(p)
+		if _, err = os.Stat(p); err == nil {
+			os.RemoveAll(p)
+		}
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	// a1 is an int32 attribute of the source array
+	rows, err := NewDimension(context, "rows", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	cols, err := NewDimension(context, "cols", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(rows, cols))
+	schema, err := NewArraySchema(context, TILEDB_SPARSE)
+	assert.Nil(t, err)
+	assert.Nil(t, schema.SetDomain(domain))
+	a1, err := NewAttribute(context, "a1", TILEDB_FLOAT64)
+	assert.Nil(t, err)
+	assert.Nil(t, schema.AddAttributes(a1))
+
+	err = CopyArray(context, tmpArrayPath, tmpCopyPath, &CopyOptions{Schema: schema})
+	assert.NotNil(t, err)
+	_, err = os.Stat(tmpCopyPath)
+	assert.True(t, os.IsNotExist(err))
+}
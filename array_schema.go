@@ -86,6 +86,7 @@ func (a *ArraySchema) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return fmt.Errorf("Error unmarshaling json for array schema: %s", a.context.LastError())
 	}
+	defer buffer.Free()
 	err = buffer.SetBuffer(bytesWithNullTerminator)
 	if err != nil {
 		return fmt.Errorf("Error unmarshaling json for array schema: %s", a.context.LastError())
@@ -101,9 +102,11 @@ func (a *ArraySchema) UnmarshalJSON(b []byte) error {
 
 	// Replace the C schema object with the deserialized one.
 	if a.tiledbArraySchema != nil {
+		untrackHandle(unsafe.Pointer(a.tiledbArraySchema))
 		C.tiledb_array_schema_free(&a.tiledbArraySchema)
 	}
 	a.tiledbArraySchema = newCSchema
+	trackHandle("tiledb_array_schema_t", unsafe.Pointer(a.tiledbArraySchema))
 
 	return nil
 }
@@ -115,6 +118,7 @@ func NewArraySchema(ctx *Context, arrayType ArrayType) (*ArraySchema, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb arraySchema: %s", arraySchema.context.LastError())
 	}
+	trackHandle("tiledb_array_schema_t", unsafe.Pointer(arraySchema.tiledbArraySchema))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&arraySchema, func(arraySchema *ArraySchema) {
@@ -127,10 +131,18 @@ func NewArraySchema(ctx *Context, arrayType ArrayType) (*ArraySchema, error) {
 // Free tiledb_array_schema_t that was allocated on heap in c
 func (a *ArraySchema) Free() {
 	if a.tiledbArraySchema != nil {
+		untrackHandle(unsafe.Pointer(a.tiledbArraySchema))
 		C.tiledb_array_schema_free(&a.tiledbArraySchema)
 	}
 }
 
+// Close frees the array schema, implementing io.Closer. Closing twice is a
+// no-op.
+func (a *ArraySchema) Close() error {
+	a.Free()
+	return nil
+}
+
 // AddAttributes add one or more attributes to the array
 func (a *ArraySchema) AddAttributes(attributes ...*Attribute) error {
 	for _, attribute := range attributes {
@@ -163,6 +175,7 @@ func (a *ArraySchema) AttributeFromIndex(index uint) (*Attribute, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting attribute %d for tiledb arraySchema: %s", index, a.context.LastError())
 	}
+	trackHandle("tiledb_attribute_t", unsafe.Pointer(attr.tiledbAttribute))
 	return &attr, nil
 }
 
@@ -177,6 +190,7 @@ func (a *ArraySchema) AttributeFromName(attrName string) (*Attribute, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting attribute %s for tiledb arraySchema: %s", attrName, a.context.LastError())
 	}
+	trackHandle("tiledb_attribute_t", unsafe.Pointer(attr.tiledbAttribute))
 	return &attr, nil
 }
 
@@ -199,6 +213,98 @@ func (a *ArraySchema) Attributes() ([]*Attribute, error) {
 	return attributes, nil
 }
 
+// dimensionField returns the description of a dimension as a batch field
+func dimensionField(dimension *Dimension) (BatchField, error) {
+	name, err := dimension.Name()
+	if err != nil {
+		return BatchField{}, err
+	}
+
+	datatype, err := dimension.Type()
+	if err != nil {
+		return BatchField{}, err
+	}
+
+	cellValNum, err := dimension.CellValNum()
+	if err != nil {
+		return BatchField{}, err
+	}
+
+	return BatchField{Name: name, Datatype: datatype, CellValNum: cellValNum, IsDimension: true}, nil
+}
+
+// attributeField returns the description of an attribute as a batch field
+func attributeField(attribute *Attribute) (BatchField, error) {
+	name, err := attribute.Name()
+	if err != nil {
+		return BatchField{}, err
+	}
+
+	datatype, err := attribute.Type()
+	if err != nil {
+		return BatchField{}, err
+	}
+
+	cellValNum, err := attribute.CellValNum()
+	if err != nil {
+		return BatchField{}, err
+	}
+
+	return BatchField{Name: name, Datatype: datatype, CellValNum: cellValNum}, nil
+}
+
+// freeAttributes frees the attributes returned by ArraySchema.Attributes
+func freeAttributes(attributes []*Attribute) {
+	for _, attribute := range attributes {
+		attribute.Free()
+	}
+}
+
+// Fields returns the dimensions followed by the attributes of the schema
+func (a *ArraySchema) Fields() ([]BatchField, error) {
+	domain, err := a.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]BatchField, 0)
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		dimension, err := domain.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return nil, err
+		}
+
+		field, err := dimensionField(dimension)
+		dimension.Free()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	attributes, err := a.Attributes()
+	if err != nil {
+		return nil, err
+	}
+	defer freeAttributes(attributes)
+
+	for _, attribute := range attributes {
+		field, err := attributeField(attribute)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
 // SetDomain sets the array domain
 func (a *ArraySchema) SetDomain(domain *Domain) error {
 	ret := C.tiledb_array_schema_set_domain(a.context.tiledbContext, a.tiledbArraySchema, domain.tiledbDomain)
@@ -215,6 +321,7 @@ func (a *ArraySchema) Domain() (*Domain, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error setting domain for tiledb arraySchema: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_domain_t", unsafe.Pointer(domain.tiledbDomain))
 	return &domain, nil
 }
 
@@ -291,6 +398,7 @@ func (a *ArraySchema) CoordsFilterList() (*FilterList, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting coordinates filter list for tiledb arraySchema: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_filter_list_t", unsafe.Pointer(filterList.tiledbFilterList))
 	return &filterList, nil
 }
 
@@ -312,6 +420,7 @@ func (a *ArraySchema) OffsetsFilterList() (*FilterList, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting offsets filter list for tiledb arraySchema: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_filter_list_t", unsafe.Pointer(filterList.tiledbFilterList))
 	return &filterList, nil
 }
 
@@ -324,8 +433,17 @@ func (a *ArraySchema) Check() error {
 	return nil
 }
 
-// LoadArraySchema reads a directory for a ArraySchema
+// LoadArraySchema reads a directory for a ArraySchema, with the key of the
+// KeyProvider of the context if it returns one
 func LoadArraySchema(context *Context, path string) (*ArraySchema, error) {
+	encryptionType, key, err := context.encryptionKey(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error in loading arraySchema from %s: %s", path, err)
+	}
+	if key != "" {
+		return LoadArraySchemaWithKey(context, path, encryptionType, key)
+	}
+
 	cpath := C.CString(path)
 	defer C.free(unsafe.Pointer(cpath))
 	a := ArraySchema{context: context}
@@ -333,6 +451,7 @@ func LoadArraySchema(context *Context, path string) (*ArraySchema, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error in loading arraySchema from %s: %s", path, a.context.LastError())
 	}
+	trackHandle("tiledb_array_schema_t", unsafe.Pointer(a.tiledbArraySchema))
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&a, func(arraySchema *ArraySchema) {
 		arraySchema.Free()
@@ -351,6 +470,7 @@ func LoadArraySchemaWithKey(context *Context, path string, encryptionType Encryp
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error in loading arraySchema with key from %s: %s", path, a.context.LastError())
 	}
+	trackHandle("tiledb_array_schema_t", unsafe.Pointer(a.tiledbArraySchema))
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&a, func(arraySchema *ArraySchema) {
 		arraySchema.Free()
@@ -315,3 +315,41 @@ func TestArrayEncryption(t *testing.T) {
 
 	array.Free()
 }
+
+func TestArrayOpenAtTime(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_array_open_at_time")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	defer array.Free()
+
+	// Timestamps are truncated to the millisecond
+	openedAt := time.Now()
+	assert.Nil(t, array.OpenAtTime(TILEDB_READ, openedAt))
+	timestamp, err := array.Timestamp()
+	assert.Nil(t, err)
+	assert.Equal(t, openedAt.Truncate(time.Millisecond).UnixNano(), timestamp.UnixNano())
+
+	// Before the array was written
+	assert.Nil(t, array.ReopenAt(1))
+	nonEmptyDomain, isEmpty, err := array.NonEmptyDomainFromIndex(0)
+	assert.Nil(t, err)
+	assert.True(t, isEmpty)
+	assert.Nil(t, nonEmptyDomain)
+	assert.Nil(t, array.Close())
+
+	assert.NotNil(t, array.OpenBetween(TILEDB_READ, openedAt.Add(-time.Hour), openedAt))
+	assert.Nil(t, array.OpenBetween(TILEDB_READ, time.Time{}, openedAt))
+	timestamp, err = array.Timestamp()
+	assert.Nil(t, err)
+	assert.Equal(t, openedAt.Truncate(time.Millisecond).UnixNano(), timestamp.UnixNano())
+	assert.Nil(t, array.Close())
+}
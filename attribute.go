@@ -39,6 +39,7 @@ func NewAttribute(context *Context, name string, datatype Datatype) (*Attribute,
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb attribute: %s", context.LastError())
 	}
+	trackHandle("tiledb_attribute_t", unsafe.Pointer(attribute.tiledbAttribute))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&attribute, func(attribute *Attribute) {
@@ -51,10 +52,17 @@ func NewAttribute(context *Context, name string, datatype Datatype) (*Attribute,
 // Free tiledb_attribute_t that was allocated on heap in c
 func (a *Attribute) Free() {
 	if a.tiledbAttribute != nil {
+		untrackHandle(unsafe.Pointer(a.tiledbAttribute))
 		C.tiledb_attribute_free(&a.tiledbAttribute)
 	}
 }
 
+// Close frees the attribute, implementing io.Closer. Closing twice is a no-op.
+func (a *Attribute) Close() error {
+	a.Free()
+	return nil
+}
+
 // SetFilterList sets the attribute filterList
 func (a *Attribute) SetFilterList(filterlist *FilterList) error {
 	ret := C.tiledb_attribute_set_filter_list(a.context.tiledbContext, a.tiledbAttribute, filterlist.tiledbFilterList)
@@ -71,6 +79,7 @@ func (a *Attribute) FilterList() (*FilterList, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting tiledb attribute filter list: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_filter_list_t", unsafe.Pointer(filterList.tiledbFilterList))
 
 	return &filterList, nil
 }
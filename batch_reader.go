@@ -0,0 +1,393 @@
+package tiledb
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// defaultVarCellElements is the initial number of elements reserved per cell
+// for the data buffer of variable sized attributes or dimensions
+const defaultVarCellElements = 16
+
+// BatchField describes an attribute or dimension read by a BatchReader
+type BatchField struct {
+	Name        string
+	Datatype    Datatype
+	CellValNum  uint
+	IsDimension bool
+}
+
+// IsVar returns true if the field is variable sized
+func (f BatchField) IsVar() bool {
+	return f.CellValNum == TILEDB_VAR_NUM
+}
+
+// Batch contains the cells returned by one submission of a read query.
+// The slices of a batch are backed by the buffers of the BatchReader and are
+// only valid until the next call to BatchReader.Next
+type Batch struct {
+	Fields   []BatchField
+	NumCells uint64
+	offsets  map[string][]uint64
+	data     map[string]interface{}
+}
+
+// Field returns the description of a field by name
+func (b *Batch) Field(name string) (BatchField, error) {
+	for _, field := range b.Fields {
+		if field.Name == name {
+			return field, nil
+		}
+	}
+	return BatchField{}, fmt.Errorf("Field %s is not part of batch", name)
+}
+
+// Data returns the typed data slice of a field, trimmed to the number of
+// elements returned by the query
+func (b *Batch) Data(name string) (interface{}, error) {
+	data, ok := b.data[name]
+	if !ok {
+		return nil, fmt.Errorf("Field %s is not part of batch", name)
+	}
+	return data, nil
+}
+
+// Offsets returns the offsets (in bytes) of a variable sized field, trimmed
+// to the number of cells returned by the query
+func (b *Batch) Offsets(name string) ([]uint64, error) {
+	offsets, ok := b.offsets[name]
+	if !ok {
+		return nil, fmt.Errorf("Field %s is not a variable sized field of batch", name)
+	}
+	return offsets, nil
+}
+
+// cellRange returns the range of elements of the data slice of a field
+// holding the values of cell i
+func (b *Batch) cellRange(field BatchField, i uint64) (int, int) {
+	if field.IsVar() {
+		offsets := b.offsets[field.Name]
+		typeSize := field.Datatype.Size()
+		start := offsets[i] / typeSize
+		end := uint64(reflect.ValueOf(b.data[field.Name]).Len())
+		if i+1 < uint64(len(offsets)) {
+			end = offsets[i+1] / typeSize
+		}
+		return int(start), int(end)
+	}
+	start := i * uint64(field.CellValNum)
+	return int(start), int(start + uint64(field.CellValNum))
+}
+
+// Cell returns the value of a field for the cell at index i of the batch.
+// Single valued fields return a scalar, multi valued and variable sized
+// fields return a slice. Fields of type TILEDB_CHAR, TILEDB_STRING_ASCII and
+// TILEDB_STRING_UTF8 return a string
+func (b *Batch) Cell(name string, i uint64) (interface{}, error) {
+	if i >= b.NumCells {
+		return nil, fmt.Errorf("Cell index %d out of range, batch has %d cells", i, b.NumCells)
+	}
+
+	field, err := b.Field(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data := reflect.ValueOf(b.data[name])
+	if !field.IsVar() && field.CellValNum == 1 && !field.Datatype.IsString() {
+		return data.Index(int(i)).Interface(), nil
+	}
+	start, end := b.cellRange(field, i)
+	value := data.Slice(start, end)
+
+	if field.Datatype.IsString() {
+		return string(value.Interface().([]uint8)), nil
+	}
+
+	// Copy the slice so the value outlives the batch
+	cell := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+	reflect.Copy(cell, value)
+	return cell.Interface(), nil
+}
+
+// Select returns a new batch holding copies of the cells at the given
+// indexes, in order. names restricts the fields of the new batch, all fields
+// are kept if it is empty. The new batch remains valid after the next call
+// to BatchReader.Next
+func (b *Batch) Select(names []string, indexes []uint64) (*Batch, error) {
+	fields := b.Fields
+	if len(names) > 0 {
+		fields = make([]BatchField, 0, len(names))
+		for _, name := range names {
+			field, err := b.Field(name)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	for _, i := range indexes {
+		if i >= b.NumCells {
+			return nil, fmt.Errorf("Cell index %d out of range, batch has %d cells", i, b.NumCells)
+		}
+	}
+
+	selected := Batch{
+		Fields:   fields,
+		NumCells: uint64(len(indexes)),
+		offsets:  make(map[string][]uint64),
+		data:     make(map[string]interface{}),
+	}
+	for _, field := range fields {
+		data := reflect.ValueOf(b.data[field.Name])
+		values := reflect.MakeSlice(data.Type(), 0, 0)
+		newOffsets := make([]uint64, 0, len(indexes))
+		for _, i := range indexes {
+			newOffsets = append(newOffsets, uint64(values.Len())*field.Datatype.Size())
+			start, end := b.cellRange(field, i)
+			values = reflect.AppendSlice(values, data.Slice(start, end))
+		}
+		if field.IsVar() {
+			selected.offsets[field.Name] = newOffsets
+		}
+		selected.data[field.Name] = values.Interface()
+	}
+
+	return &selected, nil
+}
+
+/*
+BatchReader streams the results of a read query in batches. The query is
+resubmitted while its status is TILEDB_INCOMPLETE, so memory use is bounded
+by the size of the buffers regardless of the size of the subarray.
+
+The underlying query is available through Query() and should be configured
+(subarray, ranges, layout) before the first call to Next:
+
+	reader, err := NewBatchReader(ctx, array, []string{"rows", "a1"}, 1024)
+	err = reader.Query().AddRange(0, int32(1), int32(100))
+	for {
+	  batch, err := reader.Next()
+	  if err == io.EOF {
+	    break
+	  }
+	  // Consume batch
+	}
+*/
+type BatchReader struct {
+	context   *Context
+	array     *Array
+	query     *Query
+	fields    []BatchField
+	batchSize uint64
+	varSize   uint64
+	offsets   map[string][]uint64
+	data      map[string]interface{}
+	done      bool
+}
+
+// NewBatchReader creates a BatchReader for an array opened in READ mode.
+// names lists the attributes and dimensions to read; if it is empty all
+// dimensions and attributes are read. batchSize is the number of cells each
+// buffer is initially sized for.
+func NewBatchReader(ctx *Context, array *Array, names []string, batchSize uint64) (*BatchReader, error) {
+	if batchSize == 0 {
+		return nil, fmt.Errorf("Error creating batch reader: batch size must be greater than zero")
+	}
+
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating batch reader: %s", err)
+	}
+	defer schema.Free()
+
+	allFields, err := schema.Fields()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating batch reader: %s", err)
+	}
+
+	fields := allFields
+	if len(names) > 0 {
+		fields = make([]BatchField, 0, len(names))
+		for _, name := range names {
+			found := false
+			for _, field := range allFields {
+				if field.Name == name {
+					fields = append(fields, field)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("Error creating batch reader: %s is not an attribute or dimension of the array", name)
+			}
+		}
+	}
+
+	query, err := NewQuery(ctx, array)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating batch reader: %s", err)
+	}
+
+	reader := BatchReader{
+		context:   ctx,
+		array:     array,
+		query:     query,
+		fields:    fields,
+		batchSize: batchSize,
+		varSize:   batchSize * defaultVarCellElements,
+	}
+
+	err = reader.setBuffers()
+	if err != nil {
+		query.Free()
+		return nil, err
+	}
+
+	return &reader, nil
+}
+
+// Query returns the query used by the reader, so that the subarray, ranges
+// and layout can be set before reading
+func (r *BatchReader) Query() *Query {
+	return r.query
+}
+
+// Fields returns the fields read by the reader
+func (r *BatchReader) Fields() []BatchField {
+	return r.fields
+}
+
+// setBuffers allocates the buffers of all fields and sets them on the query
+func (r *BatchReader) setBuffers() error {
+	r.offsets = make(map[string][]uint64)
+	r.data = make(map[string]interface{})
+	for _, field := range r.fields {
+		if field.IsVar() {
+			data, _, err := field.Datatype.MakeSlice(r.varSize)
+			if err != nil {
+				return fmt.Errorf("Error allocating buffer for %s: %s", field.Name, err)
+			}
+			offsets := make([]uint64, r.batchSize)
+			_, _, err = r.query.SetBufferVar(field.Name, offsets, data)
+			if err != nil {
+				return fmt.Errorf("Error setting buffer for %s: %s", field.Name, err)
+			}
+			r.offsets[field.Name] = offsets
+			r.data[field.Name] = data
+		} else {
+			data, _, err := field.Datatype.MakeSlice(r.batchSize * uint64(field.CellValNum))
+			if err != nil {
+				return fmt.Errorf("Error allocating buffer for %s: %s", field.Name, err)
+			}
+			_, err = r.query.SetBuffer(field.Name, data)
+			if err != nil {
+				return fmt.Errorf("Error setting buffer for %s: %s", field.Name, err)
+			}
+			r.data[field.Name] = data
+		}
+	}
+	return nil
+}
+
+// Next submits the query and returns the next batch of results. io.EOF is
+// returned once the query has completed and all results have been returned.
+// If the buffers are too small to hold a single result they are doubled in
+// size and the query is resubmitted.
+func (r *BatchReader) Next() (*Batch, error) {
+	for {
+		if r.done {
+			return nil, io.EOF
+		}
+
+		err := r.query.Submit()
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := r.query.Status()
+		if err != nil {
+			return nil, err
+		}
+
+		switch status {
+		case TILEDB_COMPLETED:
+			r.done = true
+		case TILEDB_INCOMPLETE:
+		default:
+			return nil, fmt.Errorf("Error reading batch: unexpected query status %d", status)
+		}
+
+		elements, err := r.query.ResultBufferElements()
+		if err != nil {
+			return nil, err
+		}
+
+		batch := Batch{
+			Fields:  r.fields,
+			offsets: make(map[string][]uint64),
+			data:    make(map[string]interface{}),
+		}
+		for idx, field := range r.fields {
+			result := elements[field.Name]
+			var numCells uint64
+			if field.IsVar() {
+				numCells = result[0]
+				batch.offsets[field.Name] = r.offsets[field.Name][:result[0]]
+			} else {
+				numCells = result[1] / uint64(field.CellValNum)
+			}
+			batch.data[field.Name] = reflect.ValueOf(r.data[field.Name]).Slice(0, int(result[1])).Interface()
+			if idx == 0 {
+				batch.NumCells = numCells
+			}
+		}
+
+		if batch.NumCells > 0 {
+			return &batch, nil
+		}
+
+		if status == TILEDB_INCOMPLETE {
+			// No result fit in the buffers, grow them and resubmit
+			r.batchSize *= 2
+			r.varSize *= 2
+			err = r.setBuffers()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// ReadAll calls fn for every batch until the query is complete
+func (r *BatchReader) ReadAll(fn func(batch *Batch) error) error {
+	for {
+		batch, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = fn(batch)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Free releases the query used by the reader
+func (r *BatchReader) Free() {
+	if r.query != nil {
+		r.query.Free()
+	}
+}
+
+// Close releases the query used by the reader, implementing io.Closer
+func (r *BatchReader) Close() error {
+	r.Free()
+	return nil
+}
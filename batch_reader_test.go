@@ -0,0 +1,162 @@
+package tiledb
+
+import (
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createBatchTestArray creates a 4x4 sparse array with an int32 attribute
+// "a1", a variable sized string attribute "a2" and a datetime attribute "a3"
+// and writes 3 cells to it
+func createBatchTestArray(t *testing.T, context *Context, tmpArrayPath string) {
+	rows, err := NewDimension(context, "rows", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	cols, err := NewDimension(context, "cols", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(rows, cols))
+
+	arraySchema, err := NewArraySchema(context, TILEDB_SPARSE)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.SetDomain(domain))
+	assert.Nil(t, arraySchema.SetCellOrder(TILEDB_ROW_MAJOR))
+	assert.Nil(t, arraySchema.SetTileOrder(TILEDB_ROW_MAJOR))
+
+	a1, err := NewAttribute(context, "a1", TILEDB_INT32)
+	assert.Nil(t, err)
+	a2, err := NewAttribute(context, "a2", TILEDB_STRING_UTF8)
+	assert.Nil(t, err)
+	assert.Nil(t, a2.SetCellValNum(TILEDB_VAR_NUM))
+	a3, err := NewAttribute(context, "a3", TILEDB_DATETIME_DAY)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.AddAttributes(a1, a2, a3))
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	query, err := NewQuery(context, array)
+	assert.Nil(t, err)
+	assert.Nil(t, query.SetLayout(TILEDB_UNORDERED))
+
+	_, err = query.SetBuffer("rows", []int32{1, 2, 2})
+	assert.Nil(t, err)
+	_, err = query.SetBuffer("cols", []int32{1, 1, 2})
+	assert.Nil(t, err)
+	_, err = query.SetBuffer("a1", []int32{1, 2, 3})
+	assert.Nil(t, err)
+	_, _, err = query.SetBufferVar("a2", []uint64{0, 1, 3}, []byte("abbccc"))
+	assert.Nil(t, err)
+	// 2020-01-01, 2020-01-02, 2020-01-03 as days since epoch
+	_, err = query.SetBuffer("a3", []int64{18262, 18263, 18264})
+	assert.Nil(t, err)
+
+	assert.Nil(t, query.Submit())
+	assert.Nil(t, array.Close())
+}
+
+func TestBatchReader(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_batch_reader")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	// Unknown fields are rejected
+	_, err = NewBatchReader(context, array, []string{"nope"}, 1)
+	assert.NotNil(t, err)
+
+	// A batch size of one forces a resubmission per cell
+	reader, err := NewBatchReader(context, array, []string{"rows", "cols", "a1", "a2"}, 1)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+
+	rows := make([]int32, 0)
+	a1 := make([]int32, 0)
+	a2 := make([]string, 0)
+	for {
+		batch, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+
+		for i := uint64(0); i < batch.NumCells; i++ {
+			row, err := batch.Cell("rows", i)
+			assert.Nil(t, err)
+			rows = append(rows, row.(int32))
+
+			value, err := batch.Cell("a1", i)
+			assert.Nil(t, err)
+			a1 = append(a1, value.(int32))
+
+			str, err := batch.Cell("a2", i)
+			assert.Nil(t, err)
+			a2 = append(a2, str.(string))
+		}
+	}
+
+	assert.Equal(t, []int32{1, 2, 2}, rows)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, a2)
+
+	// Reading past the end keeps returning io.EOF
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestBatchReaderRange(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_batch_reader_range")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	// All fields are read when no names are passed
+	reader, err := NewBatchReader(context, array, nil, 16)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Equal(t, 5, len(reader.Fields()))
+	assert.Nil(t, reader.Query().AddRange(0, int32(2), int32(2)))
+
+	var cells uint64
+	err = reader.ReadAll(func(batch *Batch) error {
+		cells += batch.NumCells
+		data, err := batch.Data("a1")
+		assert.Nil(t, err)
+		assert.Equal(t, []int32{2, 3}, data)
+
+		offsets, err := batch.Offsets("a2")
+		assert.Nil(t, err)
+		assert.Equal(t, []uint64{0, 2}, offsets)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), cells)
+}
@@ -0,0 +1,648 @@
+package tiledb
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DimensionBin bins the values of a dimension into buckets of fixed width
+type DimensionBin struct {
+	// Name of the dimension
+	Name string
+	// Width of the bins in the units of the dimension. For datetime
+	// dimensions it is a number of datetime units, e.g. days for
+	// TILEDB_DATETIME_DAY. Integer and datetime dimensions take a whole
+	// number.
+	Width float64
+	// Duration is the width of the bins of datetime dimensions with a fixed
+	// unit (weeks or finer). It takes precedence over Width.
+	Duration time.Duration
+}
+
+// BinResult holds the aggregates of the cells falling in one bin
+type BinResult struct {
+	// Start holds the lower bound of the bin along each binned dimension, as
+	// a time.Time for datetime dimensions and in the type of the dimension
+	// otherwise. The first bin starts at the lower bound of the domain.
+	Start      []interface{}
+	Aggregates map[string]*Aggregate
+	// values holds the lower bounds in the type of the dimensions, with
+	// datetimes as int64
+	values []interface{}
+	index  []int64
+}
+
+// datetimeUnit returns the duration of one unit of a datetime datatype as a
+// fraction num/den of nanoseconds. YEAR and MONTH have no fixed duration.
+func datetimeUnit(datatype Datatype) (int64, int64, error) {
+	switch datatype {
+	case TILEDB_DATETIME_WEEK:
+		return int64(7 * 24 * time.Hour), 1, nil
+	case TILEDB_DATETIME_DAY:
+		return int64(24 * time.Hour), 1, nil
+	case TILEDB_DATETIME_HR:
+		return int64(time.Hour), 1, nil
+	case TILEDB_DATETIME_MIN:
+		return int64(time.Minute), 1, nil
+	case TILEDB_DATETIME_SEC:
+		return int64(time.Second), 1, nil
+	case TILEDB_DATETIME_MS:
+		return int64(time.Millisecond), 1, nil
+	case TILEDB_DATETIME_US:
+		return int64(time.Microsecond), 1, nil
+	case TILEDB_DATETIME_NS:
+		return 1, 1, nil
+	case TILEDB_DATETIME_PS:
+		return 1, 1000, nil
+	case TILEDB_DATETIME_FS:
+		return 1, 1000 * 1000, nil
+	case TILEDB_DATETIME_AS:
+		return 1, 1000 * 1000 * 1000, nil
+	default:
+		return 0, 0, fmt.Errorf("Datatype %s has no fixed duration", datatype.String())
+	}
+}
+
+// binner computes the bin of the values of one dimension
+type binner struct {
+	name     string
+	datatype Datatype
+	width    float64
+	// units is the width of the bins of datetime and integer dimensions
+	units int64
+	// elemType is the Go type of the values of the dimension
+	elemType reflect.Type
+	// lower is the lower bound of the domain of the dimension, bins starting
+	// below it start at lower. It is not set when the domain is unbounded.
+	lower reflect.Value
+}
+
+// newBinner creates the binner of a dimension with the given datatype and
+// lower bound of its domain, which is not set for an unbounded domain
+func newBinner(bin DimensionBin, datatype Datatype, lower reflect.Value) (*binner, error) {
+	b := binner{name: bin.Name, datatype: datatype, width: bin.Width, lower: lower}
+
+	if datatype.IsString() {
+		return nil, fmt.Errorf("Can not bin string dimension %s", bin.Name)
+	}
+
+	slice, _, err := datatype.MakeSlice(1)
+	if err != nil {
+		return nil, err
+	}
+	b.elemType = reflect.TypeOf(slice).Elem()
+
+	if datatype.IsDatetime() {
+		if bin.Duration > 0 {
+			num, den, err := datetimeUnit(datatype)
+			if err != nil {
+				return nil, fmt.Errorf("Can not bin dimension %s by duration: %s", bin.Name, err)
+			}
+			nanos := int64(bin.Duration) * den
+			if nanos%num != 0 {
+				return nil, fmt.Errorf("Duration %s is not a multiple of the unit of dimension %s", bin.Duration, bin.Name)
+			}
+			b.units = nanos / num
+		} else {
+			b.units = int64(bin.Width)
+			if float64(b.units) != bin.Width {
+				return nil, fmt.Errorf("Width of datetime dimension %s must be a whole number of units", bin.Name)
+			}
+		}
+		if b.units <= 0 {
+			return nil, fmt.Errorf("Width of dimension %s must be greater than zero", bin.Name)
+		}
+		return &b, nil
+	}
+
+	if !(bin.Width > 0) {
+		return nil, fmt.Errorf("Width of dimension %s must be greater than zero", bin.Name)
+	}
+	if b.elemType.Kind() != reflect.Float32 && b.elemType.Kind() != reflect.Float64 {
+		b.units = int64(bin.Width)
+		if float64(b.units) != bin.Width {
+			return nil, fmt.Errorf("Width of integer dimension %s must be a whole number", bin.Name)
+		}
+	}
+	return &b, nil
+}
+
+// index returns the index of the bin of a value, bin 0 starting at 0.
+// Indexes of unsigned dimensions are offset by math.MinInt64 so that they
+// keep the order of the values.
+func (b *binner) index(value interface{}) int64 {
+	if b.datatype.IsDatetime() {
+		return floorDiv(value.(int64), b.units)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return floorDiv(v.Int(), b.units)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()/uint64(b.units) ^ 1<<63)
+	default:
+		return int64(math.Floor(v.Float() / b.width))
+	}
+}
+
+// floorDiv divides a by b > 0, rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	index := a / b
+	if a%b != 0 && a < 0 {
+		index--
+	}
+	return index
+}
+
+// start returns the lower bound of a bin in the type of the dimension and as
+// returned in BinResult.Start
+func (b *binner) start(index int64) (interface{}, interface{}) {
+	var value reflect.Value
+	switch kind := b.elemType.Kind(); {
+	case kind == reflect.Uint || kind == reflect.Uint8 || kind == reflect.Uint16 ||
+		kind == reflect.Uint32 || kind == reflect.Uint64:
+		start := (uint64(index) ^ 1<<63) * uint64(b.units)
+		if b.lower.IsValid() && start < b.lower.Uint() {
+			start = b.lower.Uint()
+		}
+		value = reflect.ValueOf(start)
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		start := float64(index) * b.width
+		if b.lower.IsValid() && start < b.lower.Float() {
+			start = b.lower.Float()
+		}
+		value = reflect.ValueOf(start)
+	default:
+		start := index * b.units
+		if start/b.units != index {
+			// Only the first bin of a domain starting near math.MinInt64 can
+			// start below it
+			start = math.MinInt64
+		}
+		if b.lower.IsValid() && start < b.lower.Int() {
+			start = b.lower.Int()
+		}
+		if b.datatype.IsDatetime() {
+			return start, GetTimeFromTimestamp(b.datatype, start)
+		}
+		value = reflect.ValueOf(start)
+	}
+
+	start := value.Convert(b.elemType).Interface()
+	return start, start
+}
+
+// binAggregator aggregates attributes per bin
+type binAggregator struct {
+	binners       []*binner
+	attributes    []string
+	histogramBins uint
+	bounds        map[string][2]float64
+	results       map[string]*BinResult
+}
+
+func (g *binAggregator) add(batch *Batch) error {
+	fields := make([]BatchField, len(g.attributes))
+	data := make([]reflect.Value, len(g.attributes))
+	for j, name := range g.attributes {
+		field, err := batch.Field(name)
+		if err != nil {
+			return err
+		}
+		if field.Datatype.IsString() {
+			return fmt.Errorf("Can not aggregate string field %s", name)
+		}
+		fields[j] = field
+		data[j] = reflect.ValueOf(batch.data[name])
+	}
+
+	index := make([]int64, len(g.binners))
+	for i := uint64(0); i < batch.NumCells; i++ {
+		for d, b := range g.binners {
+			index[d] = b.index(reflect.ValueOf(batch.data[b.name]).Index(int(i)).Interface())
+		}
+
+		key := fmt.Sprint(index)
+		result, ok := g.results[key]
+		if !ok {
+			result = g.newResult(index)
+			g.results[key] = result
+		}
+
+		for j, field := range fields {
+			start, end := batch.cellRange(field, i)
+			err := result.Aggregates[field.Name].addValues(data[j].Slice(start, end).Interface())
+			if err != nil {
+				return fmt.Errorf("Error aggregating %s: %s", field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *binAggregator) newResult(index []int64) *BinResult {
+	result := BinResult{
+		Start:  make([]interface{}, len(g.binners)),
+		values: make([]interface{}, len(g.binners)),
+		index:  append([]int64{}, index...),
+	}
+	for d, b := range g.binners {
+		result.values[d], result.Start[d] = b.start(index[d])
+	}
+
+	aggregator := NewAggregator(g.attributes, g.histogramBins, 0, 0)
+	for name, bound := range g.bounds {
+		aggregator.results[name].HistogramMin = bound[0]
+		aggregator.results[name].HistogramMax = bound[1]
+	}
+	result.Aggregates = aggregator.Results()
+	return &result
+}
+
+// merge adds the results of other to the results of g
+func (g *binAggregator) merge(other *binAggregator) {
+	for key, result := range other.results {
+		existing, ok := g.results[key]
+		if !ok {
+			g.results[key] = result
+			continue
+		}
+		for name, aggregate := range result.Aggregates {
+			existing.Aggregates[name].merge(aggregate)
+		}
+	}
+}
+
+// binRanges runs one read query over the given ranges
+func (a *Array) binRanges(g *binAggregator, ranges []QueryRange, batchSize uint64) error {
+	names := make([]string, 0, len(g.binners)+len(g.attributes))
+	for _, b := range g.binners {
+		names = append(names, b.name)
+	}
+	names = append(names, g.attributes...)
+
+	reader, err := a.newRangeReader(names, ranges, batchSize)
+	if err != nil {
+		return err
+	}
+	defer reader.Free()
+
+	return reader.ReadAll(g.add)
+}
+
+// newDimensionBinner creates the binner of a dimension of domain
+func newDimensionBinner(domain *Domain, bin DimensionBin, datatype Datatype) (*binner, error) {
+	dimension, err := domain.DimensionFromName(bin.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer dimension.Free()
+
+	lower, _, err := dimensionTiling(dimension, datatype)
+	if err != nil {
+		return nil, err
+	}
+	return newBinner(bin, datatype, lower)
+}
+
+/*
+BinBy groups the cells of the array by bins of fixed width along one or more
+dimensions and aggregates attributes per bin. The array must be open in READ
+mode. options restricts the cells read and configures histograms and
+parallelism as for Aggregate. Results are sorted by bin.
+
+	// Mean of a1 per minute along the datetime dimension "time"
+	results, err := array.BinBy([]DimensionBin{{Name: "time", Duration: time.Minute}}, []string{"a1"}, nil)
+	for _, result := range results {
+		fmt.Println(result.Start[0].(time.Time), result.Aggregates["a1"].Mean())
+	}
+*/
+func (a *Array) BinBy(bins []DimensionBin, attributes []string, options *AggregateOptions) ([]BinResult, error) {
+	if options == nil {
+		options = &AggregateOptions{}
+	}
+
+	if len(bins) == 0 || len(attributes) == 0 {
+		return nil, fmt.Errorf("Error binning array: bins and attributes are required")
+	}
+
+	schema, err := a.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("Error binning array: %s", err)
+	}
+	defer schema.Free()
+	fields, err := schema.Fields()
+	if err != nil {
+		return nil, fmt.Errorf("Error binning array: %s", err)
+	}
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, fmt.Errorf("Error binning array: %s", err)
+	}
+	defer domain.Free()
+
+	binners := make([]*binner, 0, len(bins))
+	for _, bin := range bins {
+		var datatype Datatype
+		found := false
+		for _, field := range fields {
+			if field.IsDimension && field.Name == bin.Name {
+				datatype = field.Datatype
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Error binning array: %s is not a dimension of the array", bin.Name)
+		}
+
+		b, err := newDimensionBinner(domain, bin, datatype)
+		if err != nil {
+			return nil, fmt.Errorf("Error binning array: %s", err)
+		}
+		binners = append(binners, b)
+	}
+
+	bounds, err := a.histogramBounds(attributes, options)
+	if err != nil {
+		return nil, fmt.Errorf("Error binning array: %s", err)
+	}
+
+	batchSize := options.BatchSize
+	if batchSize == 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	parts := [][]QueryRange{options.Ranges}
+	if options.Parallel {
+		parts = splitRanges(options.Ranges)
+	}
+
+	aggregators := make([]*binAggregator, len(parts))
+	errs := make([]error, len(parts))
+	var wg sync.WaitGroup
+	for i, part := range parts {
+		aggregators[i] = &binAggregator{
+			binners:       binners,
+			attributes:    attributes,
+			histogramBins: options.HistogramBins,
+			bounds:        bounds,
+			results:       make(map[string]*BinResult),
+		}
+		wg.Add(1)
+		go func(i int, part []QueryRange) {
+			defer wg.Done()
+			errs[i] = a.binRanges(aggregators[i], part, batchSize)
+		}(i, part)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("Error binning array: %s", err)
+		}
+	}
+
+	for _, aggregator := range aggregators[1:] {
+		aggregators[0].merge(aggregator)
+	}
+
+	results := make([]BinResult, 0, len(aggregators[0].results))
+	for _, result := range aggregators[0].results {
+		results = append(results, *result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		for d := range results[i].index {
+			if results[i].index[d] != results[j].index[d] {
+				return results[i].index[d] < results[j].index[d]
+			}
+		}
+		return false
+	})
+	return results, nil
+}
+
+/*
+Downsample bins the array as BinBy and writes the results to a new sparse
+array at uri. The new array has the binned dimensions, holding the lower
+bound of each bin, and for each attribute the FLOAT64 attributes
+<attribute>_sum, <attribute>_min, <attribute>_max and <attribute>_mean and the
+UINT64 attribute <attribute>_count. Binned datetime dimensions are written
+as INT64 dimensions in the units of the source dimension.
+*/
+func (a *Array) Downsample(uri string, bins []DimensionBin, attributes []string, options *AggregateOptions) error {
+	results, err := a.BinBy(bins, attributes, options)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("Error downsampling array: no cells to aggregate")
+	}
+
+	schema, err := a.Schema()
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+	domain, err := schema.Domain()
+	if err != nil {
+		return err
+	}
+	defer domain.Free()
+
+	downsampledDomain, err := NewDomain(a.context)
+	if err != nil {
+		return err
+	}
+	defer downsampledDomain.Free()
+
+	for d, bin := range bins {
+		dimension, err := downsampledDimension(a.context, domain, bin, results, d)
+		if err != nil {
+			return fmt.Errorf("Error downsampling array: %s", err)
+		}
+		defer dimension.Free()
+
+		err = downsampledDomain.AddDimensions(dimension)
+		if err != nil {
+			return err
+		}
+	}
+
+	downsampledSchema, err := NewArraySchema(a.context, TILEDB_SPARSE)
+	if err != nil {
+		return err
+	}
+	defer downsampledSchema.Free()
+
+	err = downsampledSchema.SetDomain(downsampledDomain)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range attributes {
+		for _, suffix := range []string{"_count", "_sum", "_min", "_max", "_mean"} {
+			datatype := TILEDB_FLOAT64
+			if suffix == "_count" {
+				datatype = TILEDB_UINT64
+			}
+			attribute, err := NewAttribute(a.context, name+suffix, datatype)
+			if err != nil {
+				return err
+			}
+			defer attribute.Free()
+
+			err = downsampledSchema.AddAttributes(attribute)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = downsampledSchema.Check()
+	if err != nil {
+		return fmt.Errorf("Error downsampling array: %s", err)
+	}
+
+	downsampled, err := NewArray(a.context, uri)
+	if err != nil {
+		return err
+	}
+	defer downsampled.Free()
+
+	err = downsampled.Create(downsampledSchema)
+	if err != nil {
+		return fmt.Errorf("Error downsampling array: %s", err)
+	}
+
+	err = downsampled.Open(TILEDB_WRITE)
+	if err != nil {
+		return err
+	}
+
+	query, err := NewQuery(a.context, downsampled)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	err = query.SetLayout(TILEDB_UNORDERED)
+	if err != nil {
+		return err
+	}
+
+	for d, bin := range bins {
+		values := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(results[0].values[d])), 0, len(results))
+		for _, result := range results {
+			values = reflect.Append(values, reflect.ValueOf(result.values[d]))
+		}
+		_, err = query.SetBuffer(bin.Name, values.Interface())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range attributes {
+		count := make([]uint64, len(results))
+		sum := make([]float64, len(results))
+		min := make([]float64, len(results))
+		max := make([]float64, len(results))
+		mean := make([]float64, len(results))
+		for i, result := range results {
+			aggregate := result.Aggregates[name]
+			count[i] = aggregate.Count
+			sum[i] = aggregate.Sum
+			min[i] = aggregate.Min
+			max[i] = aggregate.Max
+			mean[i] = aggregate.Mean()
+		}
+
+		buffers := map[string]interface{}{
+			name + "_count": count,
+			name + "_sum":   sum,
+			name + "_min":   min,
+			name + "_max":   max,
+			name + "_mean":  mean,
+		}
+		for bufferName, buffer := range buffers {
+			_, err = query.SetBuffer(bufferName, buffer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = query.Submit()
+	if err != nil {
+		return fmt.Errorf("Error downsampling array: %s", err)
+	}
+
+	return downsampled.Close()
+}
+
+// downsampledDimension creates the dimension of the downsampled array for
+// the d-th binned dimension, with the bin width as tile extent
+func downsampledDimension(context *Context, domain *Domain, bin DimensionBin, results []BinResult, d int) (*Dimension, error) {
+	source, err := domain.DimensionFromName(bin.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Free()
+	datatype, err := source.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	if datatype.IsDatetime() {
+		b, err := newBinner(bin, datatype, reflect.Value{})
+		if err != nil {
+			return nil, err
+		}
+		lower := results[0].values[d].(int64)
+		upper := lower
+		for _, result := range results {
+			value := result.values[d].(int64)
+			if value < lower {
+				lower = value
+			}
+			if value > upper {
+				upper = value
+			}
+		}
+		return NewDimension(context, bin.Name, []int64{lower, upper + b.units - 1}, b.units)
+	}
+
+	sourceDomain, err := source.Domain()
+	if err != nil {
+		return nil, err
+	}
+
+	toFloat := func(v reflect.Value) float64 {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return float64(v.Uint())
+		default:
+			return v.Float()
+		}
+	}
+
+	// Bins start within the source domain, the tile extent is the bin
+	// width, capped by the size of the domain
+	bounds := reflect.ValueOf(sourceDomain)
+	lower, upper := bounds.Index(0), bounds.Index(1)
+	size := toFloat(upper) - toFloat(lower)
+	width := math.Min(bin.Width, size)
+	if lower.Kind() != reflect.Float32 && lower.Kind() != reflect.Float64 {
+		width = math.Min(bin.Width, size+1)
+	}
+	extent := reflect.ValueOf(width).Convert(lower.Type()).Interface()
+
+	return NewDimension(context, bin.Name, sourceDomain, extent)
+}
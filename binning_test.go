@@ -0,0 +1,127 @@
+package tiledb
+
+import (
+	"math"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatetimeBinner(t *testing.T) {
+	b, err := newBinner(DimensionBin{Name: "time", Duration: 2 * time.Hour}, TILEDB_DATETIME_MIN, reflect.Value{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), b.index(int64(119)))
+	assert.Equal(t, int64(1), b.index(int64(120)))
+	assert.Equal(t, int64(-1), b.index(int64(-1)))
+
+	value, start := b.start(-1)
+	assert.Equal(t, int64(-120), value)
+	assert.Equal(t, time.Date(1969, 12, 31, 22, 0, 0, 0, time.UTC), start)
+
+	// Durations must be a multiple of the unit
+	_, err = newBinner(DimensionBin{Name: "time", Duration: 90 * time.Second}, TILEDB_DATETIME_MIN, reflect.Value{})
+	assert.NotNil(t, err)
+
+	// Years have no fixed duration, but can be binned by number of units
+	_, err = newBinner(DimensionBin{Name: "time", Duration: time.Hour}, TILEDB_DATETIME_YEAR, reflect.Value{})
+	assert.NotNil(t, err)
+	_, err = newBinner(DimensionBin{Name: "time", Width: 10}, TILEDB_DATETIME_YEAR, reflect.Value{})
+	assert.Nil(t, err)
+}
+
+func TestIntegerBinner(t *testing.T) {
+	// Bins starting below the domain start at its lower bound
+	b, err := newBinner(DimensionBin{Name: "x", Width: 100}, TILEDB_INT8, reflect.ValueOf(int8(-128)))
+	assert.Nil(t, err)
+	index := b.index(int8(-128))
+	assert.Equal(t, int64(-2), index)
+	value, start := b.start(index)
+	assert.Equal(t, int8(-128), value)
+	assert.Equal(t, int8(-128), start)
+	value, _ = b.start(b.index(int8(127)))
+	assert.Equal(t, int8(100), value)
+
+	b, err = newBinner(DimensionBin{Name: "x", Width: 3}, TILEDB_INT64, reflect.ValueOf(int64(math.MinInt64)))
+	assert.Nil(t, err)
+	value, _ = b.start(b.index(int64(math.MinInt64)))
+	assert.Equal(t, int64(math.MinInt64), value)
+	value, _ = b.start(b.index(int64(math.MaxInt64)))
+	assert.Equal(t, int64(math.MaxInt64-1), value)
+
+	// Unsigned bins keep their order and precision above math.MaxInt64
+	b, err = newBinner(DimensionBin{Name: "x", Width: 10}, TILEDB_UINT64, reflect.ValueOf(uint64(0)))
+	assert.Nil(t, err)
+	assert.True(t, b.index(uint64(5)) < b.index(uint64(math.MaxUint64)))
+	value, _ = b.start(b.index(uint64(math.MaxUint64)))
+	assert.Equal(t, uint64(math.MaxUint64-5), value)
+
+	// Integer dimensions are binned by whole numbers
+	_, err = newBinner(DimensionBin{Name: "x", Width: 2.5}, TILEDB_INT32, reflect.Value{})
+	assert.NotNil(t, err)
+}
+
+func TestArrayBinByAndDownsample(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_array_bin_by")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	tmpDownsampledPath := path.Join(os.TempDir(), "tiledb_test_array_downsampled")
+	defer os.RemoveAll(tmpDownsampledPath)
+	if _, err = os.Stat(tmpDownsampledPath); err == nil {
+		os.RemoveAll(tmpDownsampledPath)
+	}
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	bins := []DimensionBin{{Name: "rows", Width: 2}}
+	results, err := array.BinBy(bins, []string{"a1"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(results))
+	// The first bin starts at the lower bound of the domain
+	assert.Equal(t, []interface{}{int32(1)}, results[0].Start)
+	assert.Equal(t, uint64(1), results[0].Aggregates["a1"].Count)
+	assert.Equal(t, []interface{}{int32(2)}, results[1].Start)
+	assert.Equal(t, 5.0, results[1].Aggregates["a1"].Sum)
+	assert.Equal(t, 2.5, results[1].Aggregates["a1"].Mean())
+
+	// Only dimensions can be binned
+	_, err = array.BinBy([]DimensionBin{{Name: "a3", Width: 1}}, []string{"a1"}, nil)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, array.Downsample(tmpDownsampledPath, bins, []string{"a1"}, nil))
+
+	downsampled, err := NewArray(context, tmpDownsampledPath)
+	assert.Nil(t, err)
+	assert.Nil(t, downsampled.Open(TILEDB_READ))
+	defer downsampled.Close()
+
+	reader, err := NewBatchReader(context, downsampled, []string{"rows", "a1_count", "a1_mean"}, 16)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+
+	batch, err := reader.Next()
+	assert.Nil(t, err)
+	rows, err := batch.Data("rows")
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 2}, rows)
+	count, err := batch.Data("a1_count")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2}, count)
+	mean, err := batch.Data("a1_mean")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 2.5}, mean)
+}
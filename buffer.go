@@ -27,6 +27,7 @@ func NewBuffer(context *Context) (*Buffer, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb buffer: %s", buffer.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&buffer, func(buffer *Buffer) {
@@ -39,10 +40,17 @@ func NewBuffer(context *Context) (*Buffer, error) {
 // Free c-alloc'ed data types
 func (b *Buffer) Free() {
 	if b.tiledbBuffer != nil {
+		untrackHandle(unsafe.Pointer(b.tiledbBuffer))
 		C.tiledb_buffer_free(&b.tiledbBuffer)
 	}
 }
 
+// Close frees the buffer, implementing io.Closer. Closing twice is a no-op.
+func (b *Buffer) Close() error {
+	b.Free()
+	return nil
+}
+
 // SetType sets buffer datatype
 func (b *Buffer) SetType(datatype Datatype) error {
 	ret := C.tiledb_buffer_set_type(b.context.tiledbContext, b.tiledbBuffer, C.tiledb_datatype_t(datatype))
@@ -10,6 +10,7 @@ import "C"
 import (
 	"fmt"
 	"runtime"
+	"unsafe"
 )
 
 // BufferList A list of TileDB BufferList objects
@@ -26,6 +27,7 @@ func NewBufferList(context *Context) (*BufferList, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb buffer list: %s", bufferList.context.LastError())
 	}
+	trackHandle("tiledb_buffer_list_t", unsafe.Pointer(bufferList.tiledbBufferList))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&bufferList, func(bufferList *BufferList) {
@@ -38,10 +40,18 @@ func NewBufferList(context *Context) (*BufferList, error) {
 // Free c-alloc'ed data types
 func (b *BufferList) Free() {
 	if b.tiledbBufferList != nil {
+		untrackHandle(unsafe.Pointer(b.tiledbBufferList))
 		C.tiledb_buffer_list_free(&b.tiledbBufferList)
 	}
 }
 
+// Close frees the buffer list, implementing io.Closer. Closing twice is a
+// no-op.
+func (b *BufferList) Close() error {
+	b.Free()
+	return nil
+}
+
 // NumBuffers returns number of buffers in the list
 func (b *BufferList) NumBuffers() (uint, error) {
 	var numBuffers C.uint64_t
@@ -66,6 +76,7 @@ func (b *BufferList) GetBuffer(bufferIndex uint) (*Buffer, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting tiledb buffer index %d from buffer list: %s", bufferIndex, b.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -95,6 +106,7 @@ func (b *BufferList) Flatten() (*Buffer, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting tiledb bufferList num buffers: %s", b.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -0,0 +1,70 @@
+package tiledb
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// callbacks holds the Go functions called back by the tiledb listing
+// functions, by id. The id, in C memory, is passed to tiledb as the data of
+// the callback since Go pointers can not be kept by C code.
+var callbacks = struct {
+	sync.Mutex
+	next  uint64
+	funcs map[uint64]interface{}
+}{funcs: make(map[uint64]interface{})}
+
+// registerCallback registers fn and returns the callback data identifying
+// it, which must be released with unregisterCallback
+func registerCallback(fn interface{}) unsafe.Pointer {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+
+	callbacks.next++
+	callbacks.funcs[callbacks.next] = fn
+
+	data := C.malloc(C.sizeof_uint64_t)
+	*(*uint64)(data) = callbacks.next
+	return data
+}
+
+// unregisterCallback releases the callback data returned by registerCallback
+func unregisterCallback(data unsafe.Pointer) {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+
+	delete(callbacks.funcs, *(*uint64)(data))
+	C.free(data)
+}
+
+// lookupCallback returns the function registered with the callback data
+func lookupCallback(data unsafe.Pointer) interface{} {
+	callbacks.Lock()
+	defer callbacks.Unlock()
+
+	return callbacks.funcs[*(*uint64)(data)]
+}
+
+//export vfsLsCallback
+func vfsLsCallback(path *C.char, data unsafe.Pointer) C.int32_t {
+	fn := lookupCallback(data).(func(path string) bool)
+	if fn(C.GoString(path)) {
+		return 1
+	}
+	return 0
+}
+
+//export objectCallback
+func objectCallback(path *C.char, objectType C.int32_t, data unsafe.Pointer) C.int32_t {
+	fn := lookupCallback(data).(func(path string, objectType ObjectType) bool)
+	if fn(C.GoString(path), ObjectType(objectType)) {
+		return 1
+	}
+	return 0
+}
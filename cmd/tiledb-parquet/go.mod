@@ -0,0 +1,14 @@
+module github.com/TileDB-Inc/TileDB-Go/cmd/tiledb-parquet
+
+go 1.13
+
+require (
+	github.com/TileDB-Inc/TileDB-Go v0.0.0-00010101000000-000000000000
+	github.com/TileDB-Inc/TileDB-Go/tiledbparquet v0.0.0-00010101000000-000000000000
+	github.com/xitongsys/parquet-go-source v0.0.0-20200817004010-026bad9b25d0
+)
+
+replace (
+	github.com/TileDB-Inc/TileDB-Go => ../../
+	github.com/TileDB-Inc/TileDB-Go/tiledbparquet => ../../tiledbparquet
+)
@@ -0,0 +1,248 @@
+/*
+Command tiledb-parquet converts Parquet files to TileDB arrays and back.
+
+Usage:
+
+	tiledb-parquet import [-sparse -dims col1,col2] [-batch n] file.parquet array_uri
+	tiledb-parquet export [-fields f1,f2] [-subarray lo1,hi1,lo2,hi2] [-batch n] array_uri file.parquet
+
+Import creates a new array from the schema of the Parquet file. Without
+-sparse the array is dense with a single row index dimension. Export writes
+the cells of the given subarray (the whole array by default) in row major
+order.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/TileDB-Inc/TileDB-Go/tiledbparquet"
+	"github.com/xitongsys/parquet-go-source/local"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: tiledb-parquet import [-sparse -dims col1,col2] [-batch n] file.parquet array_uri")
+	fmt.Fprintln(os.Stderr, "       tiledb-parquet export [-fields f1,f2] [-subarray lo1,hi1,...] [-batch n] array_uri file.parquet")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// splitList splits a comma separated flag value, returning nil when empty
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	sparse := flags.Bool("sparse", false, "create a sparse array")
+	dims := flags.String("dims", "", "comma separated columns used as dimensions of a sparse array")
+	batch := flags.Int64("batch", tiledbparquet.DefaultBatchSize, "number of rows written per query")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		usage()
+	}
+
+	options := &tiledbparquet.ImportOptions{
+		ArrayType:  tiledb.TILEDB_DENSE,
+		Dimensions: splitList(*dims),
+		BatchSize:  *batch,
+	}
+	if *sparse {
+		options.ArrayType = tiledb.TILEDB_SPARSE
+	}
+
+	file, err := local.NewLocalFileReader(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx, err := tiledb.NewContext(nil)
+	if err != nil {
+		return err
+	}
+
+	return tiledbparquet.Import(ctx, file, flags.Arg(1), options)
+}
+
+// parseValue parses a string as a scalar of the given datatype
+func parseValue(datatype tiledb.Datatype, value string) (interface{}, error) {
+	slice, _, err := datatype.MakeSlice(1)
+	if err != nil {
+		return nil, err
+	}
+
+	element := reflect.ValueOf(slice).Index(0)
+	switch element.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, element.Type().Bits())
+		if err != nil {
+			return nil, err
+		}
+		element.SetInt(v)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, element.Type().Bits())
+		if err != nil {
+			return nil, err
+		}
+		element.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, element.Type().Bits())
+		if err != nil {
+			return nil, err
+		}
+		element.SetFloat(v)
+	default:
+		return nil, fmt.Errorf("Unsupported datatype %s", datatype)
+	}
+	return element.Interface(), nil
+}
+
+// addSubarray adds one range per dimension from a list of lower and upper
+// bounds
+func addSubarray(array *tiledb.Array, query *tiledb.Query, bounds []string) error {
+	schema, err := array.Schema()
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return err
+	}
+
+	if uint(len(bounds)) != 2*nDim {
+		return fmt.Errorf("Subarray needs %d values, got %d", 2*nDim, len(bounds))
+	}
+
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		dimension, err := domain.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return err
+		}
+		defer dimension.Free()
+
+		datatype, err := dimension.Type()
+		if err != nil {
+			return err
+		}
+
+		lower, upper := bounds[2*dimIdx], bounds[2*dimIdx+1]
+		if datatype.IsString() {
+			err = query.AddRangeVar(uint32(dimIdx), []byte(lower), []byte(upper))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		start, err := parseValue(datatype, lower)
+		if err != nil {
+			return err
+		}
+		end, err := parseValue(datatype, upper)
+		if err != nil {
+			return err
+		}
+		err = query.AddRange(uint32(dimIdx), start, end)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	fields := flags.String("fields", "", "comma separated dimensions and attributes to export, all by default")
+	subarray := flags.String("subarray", "", "comma separated lower and upper bound of each dimension")
+	batch := flags.Uint64("batch", tiledbparquet.DefaultBatchSize, "number of cells read per query")
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		usage()
+	}
+
+	ctx, err := tiledb.NewContext(nil)
+	if err != nil {
+		return err
+	}
+
+	array, err := tiledb.NewArray(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer array.Free()
+
+	err = array.Open(tiledb.TILEDB_READ)
+	if err != nil {
+		return err
+	}
+	defer array.Close()
+
+	reader, err := tiledb.NewBatchReader(ctx, array, splitList(*fields), *batch)
+	if err != nil {
+		return err
+	}
+	defer reader.Free()
+
+	err = reader.Query().SetLayout(tiledb.TILEDB_ROW_MAJOR)
+	if err != nil {
+		return err
+	}
+
+	if *subarray != "" {
+		err = addSubarray(array, reader.Query(), splitList(*subarray))
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	err = tiledbparquet.Export(file, reader)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,380 @@
+package tiledb
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// ConditionOp is the comparison operator of a Condition
+type ConditionOp int8
+
+const (
+	// CONDITION_EQ matches cells equal to the value
+	CONDITION_EQ ConditionOp = iota
+	// CONDITION_NE matches cells different from the value
+	CONDITION_NE
+	// CONDITION_LT matches cells less than the value
+	CONDITION_LT
+	// CONDITION_LE matches cells less than or equal to the value
+	CONDITION_LE
+	// CONDITION_GT matches cells greater than the value
+	CONDITION_GT
+	// CONDITION_GE matches cells greater than or equal to the value
+	CONDITION_GE
+	// CONDITION_IN matches cells equal to one of the values of a slice
+	CONDITION_IN
+)
+
+// Condition is a predicate on the values of the attributes and dimensions of
+// a cell, evaluated in Go on the results of a read query
+type Condition interface {
+	// Fields returns the names of the attributes and dimensions the
+	// condition depends on
+	Fields() []string
+	// Match returns true if cell i of the batch satisfies the condition
+	Match(batch *Batch, i uint64) (bool, error)
+}
+
+// CompareValues compares a cell value with another value, returning -1, 0
+// or 1. Numbers of any type are compared by value, strings with strings.
+// NaN is ordered after all other numbers and equal to NaN, so the result is
+// a total order suitable for sorting. Conditions never match NaN values.
+func CompareValues(cell interface{}, value interface{}) (int, error) {
+	cmp := func(less, greater bool) int {
+		if less {
+			return -1
+		}
+		if greater {
+			return 1
+		}
+		return 0
+	}
+
+	if cellString, ok := cell.(string); ok {
+		valueString, ok := value.(string)
+		if !ok {
+			return 0, fmt.Errorf("Can not compare string with %T", value)
+		}
+		return cmp(cellString < valueString, cellString > valueString), nil
+	}
+
+	a, b := reflect.ValueOf(cell), reflect.ValueOf(value)
+	isInt := func(v reflect.Value) bool {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return true
+		}
+		return false
+	}
+	isUint := func(v reflect.Value) bool {
+		switch v.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		}
+		return false
+	}
+	isFloat := func(v reflect.Value) bool {
+		return v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+	}
+
+	switch {
+	case isInt(a) && isInt(b):
+		return cmp(a.Int() < b.Int(), a.Int() > b.Int()), nil
+	case isUint(a) && isUint(b):
+		return cmp(a.Uint() < b.Uint(), a.Uint() > b.Uint()), nil
+	case isInt(a) && isUint(b):
+		if a.Int() < 0 {
+			return -1, nil
+		}
+		return cmp(uint64(a.Int()) < b.Uint(), uint64(a.Int()) > b.Uint()), nil
+	case isUint(a) && isInt(b):
+		if b.Int() < 0 {
+			return 1, nil
+		}
+		return cmp(a.Uint() < uint64(b.Int()), a.Uint() > uint64(b.Int())), nil
+	case (isInt(a) || isUint(a) || isFloat(a)) && (isInt(b) || isUint(b) || isFloat(b)):
+		toFloat := func(v reflect.Value) float64 {
+			switch {
+			case isInt(v):
+				return float64(v.Int())
+			case isUint(v):
+				return float64(v.Uint())
+			default:
+				return v.Float()
+			}
+		}
+		af, bf := toFloat(a), toFloat(b)
+		if math.IsNaN(af) || math.IsNaN(bf) {
+			return cmp(!math.IsNaN(af), !math.IsNaN(bf)), nil
+		}
+		return cmp(af < bf, af > bf), nil
+	default:
+		return 0, fmt.Errorf("Can not compare %T with %T", cell, value)
+	}
+}
+
+// isNaN returns true if a value is a floating point NaN
+func isNaN(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	return (v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64) && math.IsNaN(v.Float())
+}
+
+// comparison is a Condition comparing one field with a value
+type comparison struct {
+	name  string
+	op    ConditionOp
+	value interface{}
+}
+
+/*
+NewCondition creates a condition comparing an attribute or dimension with a
+value. For CONDITION_IN value must be a slice of candidate values. Numeric
+values of any type can be compared with numeric fields, strings with
+fields of type TILEDB_CHAR, TILEDB_STRING_ASCII or TILEDB_STRING_UTF8.
+Conditions on fields with more than one numeric value per cell are not
+supported.
+*/
+func NewCondition(name string, op ConditionOp, value interface{}) (Condition, error) {
+	if op < CONDITION_EQ || op > CONDITION_IN {
+		return nil, fmt.Errorf("Unknown condition operator %d", op)
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("Condition on %s must have a value", name)
+	}
+
+	if op == CONDITION_IN {
+		kind := reflect.TypeOf(value).Kind()
+		if kind != reflect.Slice && kind != reflect.Array {
+			return nil, fmt.Errorf("Condition IN on %s expects a slice of values, got %T", name, value)
+		}
+	}
+
+	return &comparison{name: name, op: op, value: value}, nil
+}
+
+func (c *comparison) Fields() []string {
+	return []string{c.name}
+}
+
+func (c *comparison) Match(batch *Batch, i uint64) (bool, error) {
+	cell, err := batch.Cell(c.name, i)
+	if err != nil {
+		return false, err
+	}
+
+	if c.op == CONDITION_IN {
+		values := reflect.ValueOf(c.value)
+		for j := 0; j < values.Len(); j++ {
+			value := values.Index(j).Interface()
+			result, err := CompareValues(cell, value)
+			if err != nil {
+				return false, fmt.Errorf("Error evaluating condition on %s: %s", c.name, err)
+			}
+			if result == 0 && !isNaN(cell) && !isNaN(value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	result, err := CompareValues(cell, c.value)
+	if err != nil {
+		return false, fmt.Errorf("Error evaluating condition on %s: %s", c.name, err)
+	}
+
+	// As in IEEE 754, NaN is different from all values and unordered
+	if isNaN(cell) || isNaN(c.value) {
+		return c.op == CONDITION_NE, nil
+	}
+
+	switch c.op {
+	case CONDITION_EQ:
+		return result == 0, nil
+	case CONDITION_NE:
+		return result != 0, nil
+	case CONDITION_LT:
+		return result < 0, nil
+	case CONDITION_LE:
+		return result <= 0, nil
+	case CONDITION_GT:
+		return result > 0, nil
+	default:
+		return result >= 0, nil
+	}
+}
+
+// combination is a Condition joining conditions with AND or OR
+type combination struct {
+	conditions []Condition
+	and        bool
+}
+
+// And returns a condition matching cells matching all conditions
+func And(conditions ...Condition) Condition {
+	return &combination{conditions: conditions, and: true}
+}
+
+// Or returns a condition matching cells matching any of the conditions
+func Or(conditions ...Condition) Condition {
+	return &combination{conditions: conditions, and: false}
+}
+
+func (c *combination) Fields() []string {
+	fields := make([]string, 0)
+	for _, condition := range c.conditions {
+		for _, name := range condition.Fields() {
+			found := false
+			for _, field := range fields {
+				found = found || field == name
+			}
+			if !found {
+				fields = append(fields, name)
+			}
+		}
+	}
+	return fields
+}
+
+func (c *combination) Match(batch *Batch, i uint64) (bool, error) {
+	for _, condition := range c.conditions {
+		match, err := condition.Match(batch, i)
+		if err != nil {
+			return false, err
+		}
+		// Short circuit on the first false for AND, first true for OR
+		if match != c.and {
+			return match, nil
+		}
+	}
+	return c.and, nil
+}
+
+/*
+FilteredReader streams the cells of a read query matching a Condition.
+Dimension ranges set on Query() prune the cells read by TileDB, the
+condition is then applied batch by batch in Go. The batches returned only
+hold matching cells, with their coordinates.
+*/
+type FilteredReader struct {
+	reader    *BatchReader
+	condition Condition
+	names     []string
+}
+
+// NewFilteredReader creates a FilteredReader for an array opened in READ
+// mode. The batches returned hold all dimensions followed by the attributes
+// listed in names (all attributes if names is empty). batchSize is the
+// number of cells read per query.
+func NewFilteredReader(ctx *Context, array *Array, names []string, condition Condition, batchSize uint64) (*FilteredReader, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	fields, err := schema.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	// Output fields: dimensions, then the requested attributes
+	output := make([]string, 0)
+	for _, field := range fields {
+		if field.IsDimension || len(names) == 0 {
+			output = append(output, field.Name)
+		}
+	}
+	for _, name := range names {
+		found := false
+		for _, outputName := range output {
+			found = found || outputName == name
+		}
+		if !found {
+			output = append(output, name)
+		}
+	}
+
+	// Fields only used by the condition are read but not returned
+	read := append([]string{}, output...)
+	for _, name := range condition.Fields() {
+		found := false
+		for _, readName := range read {
+			found = found || readName == name
+		}
+		if !found {
+			read = append(read, name)
+		}
+	}
+
+	reader, err := NewBatchReader(ctx, array, read, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FilteredReader{reader: reader, condition: condition, names: output}, nil
+}
+
+// Query returns the query used by the reader, so that the subarray, ranges
+// and layout can be set before reading
+func (r *FilteredReader) Query() *Query {
+	return r.reader.Query()
+}
+
+// Next returns the next batch of matching cells. Batches without matching
+// cells are skipped, io.EOF is returned once the query has completed.
+func (r *FilteredReader) Next() (*Batch, error) {
+	for {
+		batch, err := r.reader.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		indexes := make([]uint64, 0)
+		for i := uint64(0); i < batch.NumCells; i++ {
+			match, err := r.condition.Match(batch, i)
+			if err != nil {
+				return nil, err
+			}
+			if match {
+				indexes = append(indexes, i)
+			}
+		}
+
+		if len(indexes) > 0 {
+			return batch.Select(r.names, indexes)
+		}
+	}
+}
+
+// ReadAll calls fn for every batch of matching cells until the query is
+// complete
+func (r *FilteredReader) ReadAll(fn func(batch *Batch) error) error {
+	for {
+		batch, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = fn(batch)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Free releases the query used by the reader
+func (r *FilteredReader) Free() {
+	r.reader.Free()
+}
+
+// Close releases the query used by the reader, implementing io.Closer
+func (r *FilteredReader) Close() error {
+	r.Free()
+	return nil
+}
@@ -0,0 +1,178 @@
+package tiledb
+
+import (
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionMatch(t *testing.T) {
+	batch := &Batch{
+		Fields: []BatchField{
+			{Name: "a1", Datatype: TILEDB_INT32, CellValNum: 1},
+			{Name: "a2", Datatype: TILEDB_STRING_UTF8, CellValNum: TILEDB_VAR_NUM},
+		},
+		NumCells: 3,
+		offsets:  map[string][]uint64{"a2": {0, 1, 3}},
+		data: map[string]interface{}{
+			"a1": []int32{1, 2, 3},
+			"a2": []byte("abbccc"),
+		},
+	}
+
+	match := func(condition Condition) []uint64 {
+		indexes := make([]uint64, 0)
+		for i := uint64(0); i < batch.NumCells; i++ {
+			ok, err := condition.Match(batch, i)
+			assert.Nil(t, err)
+			if ok {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+
+	gt, err := NewCondition("a1", CONDITION_GT, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2}, match(gt))
+
+	le, err := NewCondition("a1", CONDITION_LE, 2.5)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{0, 1}, match(le))
+
+	in, err := NewCondition("a2", CONDITION_IN, []string{"a", "ccc"})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{0, 2}, match(in))
+
+	assert.Equal(t, []uint64{2}, match(And(gt, in)))
+	assert.Equal(t, []uint64{0, 1, 2}, match(Or(le, in)))
+	assert.Equal(t, []string{"a1", "a2"}, Or(gt, le, in).Fields())
+
+	// IN expects a slice
+	_, err = NewCondition("a1", CONDITION_IN, 1)
+	assert.NotNil(t, err)
+
+	// Strings can not be compared with numbers
+	eq, err := NewCondition("a2", CONDITION_EQ, 1)
+	assert.Nil(t, err)
+	_, err = eq.Match(batch, 0)
+	assert.NotNil(t, err)
+
+	// Selected cells are copied
+	selected, err := batch.Select([]string{"a2"}, []uint64{0, 2})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), selected.NumCells)
+	offsets, err := selected.Offsets("a2")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{0, 1}, offsets)
+	data, err := selected.Data("a2")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("accc"), data)
+	_, err = selected.Data("a1")
+	assert.NotNil(t, err)
+}
+
+func TestConditionNaN(t *testing.T) {
+	nan := math.NaN()
+	batch := &Batch{
+		Fields:   []BatchField{{Name: "a1", Datatype: TILEDB_FLOAT64, CellValNum: 1}},
+		NumCells: 2,
+		data:     map[string]interface{}{"a1": []float64{1, nan}},
+	}
+
+	match := func(op ConditionOp, value interface{}) []uint64 {
+		condition, err := NewCondition("a1", op, value)
+		assert.Nil(t, err)
+		indexes := make([]uint64, 0)
+		for i := uint64(0); i < batch.NumCells; i++ {
+			ok, err := condition.Match(batch, i)
+			assert.Nil(t, err)
+			if ok {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+
+	// NaN cells only match NE
+	assert.Equal(t, []uint64{0}, match(CONDITION_EQ, 1))
+	assert.Equal(t, []uint64{0}, match(CONDITION_LE, 1))
+	assert.Equal(t, []uint64{0}, match(CONDITION_GE, 1))
+	assert.Equal(t, []uint64{}, match(CONDITION_GT, 1))
+	assert.Equal(t, []uint64{1}, match(CONDITION_NE, 1))
+	assert.Equal(t, []uint64{0}, match(CONDITION_IN, []float64{1, nan}))
+
+	// NaN values match no cell, not even NaN cells
+	assert.Equal(t, []uint64{}, match(CONDITION_EQ, nan))
+	assert.Equal(t, []uint64{}, match(CONDITION_GE, nan))
+	assert.Equal(t, []uint64{0, 1}, match(CONDITION_NE, nan))
+
+	// NaN is ordered last for sorting
+	c, err := CompareValues(nan, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, c)
+	c, err = CompareValues(float32(1), nan)
+	assert.Nil(t, err)
+	assert.Equal(t, -1, c)
+	c, err = CompareValues(nan, nan)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, c)
+}
+
+func TestFilteredReader(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_filtered_reader")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	ne, err := NewCondition("a1", CONDITION_NE, int32(2))
+	assert.Nil(t, err)
+	in, err := NewCondition("a2", CONDITION_IN, []string{"bb", "ccc"})
+	assert.Nil(t, err)
+
+	// a2 is only read to evaluate the condition
+	reader, err := NewFilteredReader(context, array, []string{"a1"}, And(ne, in), 1)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+
+	rows := make([]int32, 0)
+	cols := make([]int32, 0)
+	a1 := make([]int32, 0)
+	err = reader.ReadAll(func(batch *Batch) error {
+		assert.Equal(t, 3, len(batch.Fields))
+		_, err := batch.Field("a2")
+		assert.NotNil(t, err)
+
+		for i := uint64(0); i < batch.NumCells; i++ {
+			row, err := batch.Cell("rows", i)
+			assert.Nil(t, err)
+			rows = append(rows, row.(int32))
+			col, err := batch.Cell("cols", i)
+			assert.Nil(t, err)
+			cols = append(cols, col.(int32))
+			value, err := batch.Cell("a1", i)
+			assert.Nil(t, err)
+			a1 = append(a1, value.(int32))
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []int32{2}, rows)
+	assert.Equal(t, []int32{2}, cols)
+	assert.Equal(t, []int32{3}, a1)
+}
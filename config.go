@@ -31,6 +31,7 @@ func NewConfig() (*Config, error) {
 		defer C.tiledb_error_free(&err)
 		return nil, fmt.Errorf("Error creating tiledb config: %s", C.GoString(msg))
 	}
+	trackHandle("tiledb_config_t", unsafe.Pointer(config.tiledbConfig))
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&config, func(config *Config) {
 		config.Free()
@@ -134,6 +135,7 @@ func LoadConfig(uri string) (*Config, error) {
 		defer C.tiledb_error_free(&err)
 		return nil, fmt.Errorf("Error loading tiledb config: %s", C.GoString(msg))
 	}
+	trackHandle("tiledb_config_t", unsafe.Pointer(config.tiledbConfig))
 
 	curi := C.CString(uri)
 	defer C.free(unsafe.Pointer(curi))
@@ -157,6 +159,13 @@ func LoadConfig(uri string) (*Config, error) {
 // Free tiledb_config_t that was allocated on heap in c
 func (c *Config) Free() {
 	if c.tiledbConfig != nil {
+		untrackHandle(unsafe.Pointer(c.tiledbConfig))
 		C.tiledb_config_free(&c.tiledbConfig)
 	}
 }
+
+// Close frees the config, implementing io.Closer. Closing twice is a no-op.
+func (c *Config) Close() error {
+	c.Free()
+	return nil
+}
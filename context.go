@@ -20,6 +20,7 @@ import (
 // the default error handler throws a TileDBError with a specific message.
 type Context struct {
 	tiledbContext *C.tiledb_ctx_t
+	keyProvider   KeyProvider
 }
 
 // NewContext creates a TileDB context with the given configuration
@@ -39,6 +40,7 @@ func NewContext(config *Config) (*Context, error) {
 		defer C.tiledb_error_free(&err)
 		return nil, fmt.Errorf("Error creating tiledb context: %s", C.GoString(msg))
 	}
+	trackHandle("tiledb_ctx_t", unsafe.Pointer(context.tiledbContext))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&context, func(context *Context) {
@@ -53,10 +55,18 @@ func NewContext(config *Config) (*Context, error) {
 // Free tiledb_ctx_t that was allocated on heap in c
 func (c *Context) Free() {
 	if c.tiledbContext != nil {
+		untrackHandle(unsafe.Pointer(c.tiledbContext))
 		C.tiledb_ctx_free(&c.tiledbContext)
 	}
 }
 
+// Close frees the context, implementing io.Closer. Objects created with
+// the context must not be used afterwards. Closing twice is a no-op.
+func (c *Context) Close() error {
+	c.Free()
+	return nil
+}
+
 // Config retrieves a copy of the config from context
 func (c *Context) Config() (*Config, error) {
 	config := &Config{}
@@ -67,6 +77,7 @@ func (c *Context) Config() (*Config, error) {
 	} else if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Unknown error in GetConfig")
 	}
+	trackHandle("tiledb_config_t", unsafe.Pointer(config.tiledbConfig))
 
 	return config, nil
 }
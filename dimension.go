@@ -170,6 +170,7 @@ func NewDimension(context *Context, name string, domain interface{}, extent inte
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb dimension: %s", context.LastError())
 	}
+	trackHandle("tiledb_dimension_t", unsafe.Pointer(dimension.tiledbDimension))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&dimension, func(dimension *Dimension) {
@@ -194,6 +195,7 @@ func NewStringDimension(context *Context, name string) (*Dimension, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb dimension: %s", context.LastError())
 	}
+	trackHandle("tiledb_dimension_t", unsafe.Pointer(dimension.tiledbDimension))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&dimension, func(dimension *Dimension) {
@@ -206,10 +208,17 @@ func NewStringDimension(context *Context, name string) (*Dimension, error) {
 // Free tiledb_dimension_t that was allocated on heap in c
 func (d *Dimension) Free() {
 	if d.tiledbDimension != nil {
+		untrackHandle(unsafe.Pointer(d.tiledbDimension))
 		C.tiledb_dimension_free(&d.tiledbDimension)
 	}
 }
 
+// Close frees the dimension, implementing io.Closer. Closing twice is a no-op.
+func (d *Dimension) Close() error {
+	d.Free()
+	return nil
+}
+
 // SetCellValNum Sets the number of values per cell for a dimension.
 // If this is not used, the default is `1`.
 // This is inferred from the type parameter of the NewDimension
@@ -23,5 +23,22 @@ Quickstart
 See quickstart_dense_test.go and quickstart_sparse_test.go for examples. Also
 checkout the official tiledb quickstart docs
 https://docs.tiledb.io/en/latest/quickstart.html
+
+Freeing objects
+
+Finalizers only run once the garbage collector does, so objects should be
+freed explicitly. Objects holding c data structures implement io.Closer, Close
+freeing them like Free and closing twice being a no-op, with two exceptions:
+
+Array.Close closes the array, which can be opened again, without freeing it.
+Array.Closer returns an io.Closer closing and freeing the array.
+
+VFS.Close closes a file, so VFS does not implement io.Closer. VFS.Closer
+returns an io.Closer freeing the VFS.
+
+	defer array.Closer().Close()
+	defer vfs.Closer().Close()
+
+A Scope frees all objects created through it at once, closing arrays first.
 */
 package tiledb
@@ -31,6 +31,7 @@ func NewDomain(ctx *Context) (*Domain, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb domain: %s", domain.context.LastError())
 	}
+	trackHandle("tiledb_domain_t", unsafe.Pointer(domain.tiledbDomain))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&domain, func(domain *Domain) {
@@ -43,10 +44,17 @@ func NewDomain(ctx *Context) (*Domain, error) {
 // Free tiledb_domain_t that was allocated on heap in c
 func (d *Domain) Free() {
 	if d.tiledbDomain != nil {
+		untrackHandle(unsafe.Pointer(d.tiledbDomain))
 		C.tiledb_domain_free(&d.tiledbDomain)
 	}
 }
 
+// Close frees the domain, implementing io.Closer. Closing twice is a no-op.
+func (d *Domain) Close() error {
+	d.Free()
+	return nil
+}
+
 // Type returns a domains type deduced from dimensions
 func (d *Domain) Type() (Datatype, error) {
 	var datatype C.tiledb_datatype_t
@@ -75,6 +83,7 @@ func (d *Domain) DimensionFromIndex(index uint) (*Dimension, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting tiledb dimension by index for domain: %s", d.context.LastError())
 	}
+	trackHandle("tiledb_dimension_t", unsafe.Pointer(dim))
 	return &Dimension{tiledbDimension: dim, context: d.context}, nil
 }
 
@@ -87,6 +96,7 @@ func (d *Domain) DimensionFromName(name string) (*Dimension, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error getting tiledb dimension by name for domain: %s", d.context.LastError())
 	}
+	trackHandle("tiledb_dimension_t", unsafe.Pointer(dim))
 	return &Dimension{tiledbDimension: dim, context: d.context}, nil
 }
 
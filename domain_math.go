@@ -0,0 +1,448 @@
+package tiledb
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// integerDimension holds the domain and tile extent of an integer dimension.
+// Values of unsigned dimensions are offset by math.MinInt64, which maps the
+// whole uint64 range to int64 and keeps the order and differences of values.
+type integerDimension struct {
+	name     string
+	datatype Datatype
+	unsigned bool
+	lower    int64
+	upper    int64
+	extent   int64
+}
+
+// value converts a value of the dimension to int64
+func (d integerDimension) value(value reflect.Value) (int64, error) {
+	if !d.unsigned {
+		return integerValue(value)
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 {
+			return 0, fmt.Errorf("Value %d is out of the domain of unsigned dimension %s", value.Int(), d.name)
+		}
+		return int64(uint64(value.Int()) ^ 1<<63), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(value.Uint() ^ 1<<63), nil
+	default:
+		return 0, fmt.Errorf("Expected an integer, got %s", value.Type())
+	}
+}
+
+// typed converts an int64 value of the dimension back to its own value, a
+// uint64 for unsigned dimensions
+func (d integerDimension) typed(value int64) interface{} {
+	if d.unsigned {
+		return uint64(value) ^ 1<<63
+	}
+	return value
+}
+
+// tileNum returns the number of tiles along the dimension
+func (d integerDimension) tileNum() uint64 {
+	return uint64(d.upper-d.lower)/uint64(d.extent) + 1
+}
+
+// integerValue converts an integer dimension value to int64, failing for
+// unsigned values above math.MaxInt64
+func integerValue(value reflect.Value) (int64, error) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("Value %d is out of the supported range of integer dimensions", value.Uint())
+		}
+		return int64(value.Uint()), nil
+	default:
+		return 0, fmt.Errorf("Expected an integer, got %s", value.Type())
+	}
+}
+
+// rangeCells returns the number of cells of the range [start, end], failing
+// if it does not fit in a uint64
+func rangeCells(start, end int64) (uint64, error) {
+	// end >= start, so the difference wraps to the right unsigned value
+	width := uint64(end - start)
+	if width == math.MaxUint64 {
+		return 0, fmt.Errorf("Range holds more than %d cells", uint64(math.MaxUint64))
+	}
+	return width + 1, nil
+}
+
+// mulCells multiplies two numbers of cells, failing on overflow
+func mulCells(a, b uint64) (uint64, error) {
+	if a != 0 && b > math.MaxUint64/a {
+		return 0, fmt.Errorf("Number of cells overflows: %d x %d", a, b)
+	}
+	return a * b, nil
+}
+
+// integerDimensions returns the domains and extents of all dimensions,
+// failing if a dimension is not of an integer type
+func (d *Domain) integerDimensions() ([]integerDimension, error) {
+	nDim, err := d.NDim()
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions := make([]integerDimension, nDim)
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		dimension, err := d.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return nil, err
+		}
+		defer dimension.Free()
+		name, err := dimension.Name()
+		if err != nil {
+			return nil, err
+		}
+		datatype, err := dimension.Type()
+		if err != nil {
+			return nil, err
+		}
+
+		switch datatype {
+		case TILEDB_INT8, TILEDB_INT16, TILEDB_INT32, TILEDB_INT64,
+			TILEDB_UINT8, TILEDB_UINT16, TILEDB_UINT32, TILEDB_UINT64:
+		default:
+			return nil, fmt.Errorf("Dimension %s of type %s is not an integer dimension", name, datatype.String())
+		}
+
+		dimensionDomain, err := dimension.Domain()
+		if err != nil {
+			return nil, err
+		}
+		extent, err := dimension.Extent()
+		if err != nil {
+			return nil, err
+		}
+
+		dim := integerDimension{name: name, datatype: datatype, unsigned: datatype == TILEDB_UINT64}
+		bounds := reflect.ValueOf(dimensionDomain)
+		dim.lower, err = dim.value(bounds.Index(0))
+		if err != nil {
+			return nil, fmt.Errorf("Domain of dimension %s: %s", name, err)
+		}
+		dim.upper, err = dim.value(bounds.Index(1))
+		if err != nil {
+			return nil, fmt.Errorf("Domain of dimension %s: %s", name, err)
+		}
+		dim.extent, err = integerValue(reflect.ValueOf(extent))
+		if err != nil {
+			return nil, fmt.Errorf("Extent of dimension %s: %s", name, err)
+		}
+		dimensions[dimIdx] = dim
+	}
+	return dimensions, nil
+}
+
+// integerSlice converts a slice of integers of any type holding perDimension
+// values for each dimension to int64
+func integerSlice(dimensions []integerDimension, values interface{}, perDimension int) ([]int64, error) {
+	if values == nil || reflect.TypeOf(values).Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Expected a slice of integers, got %T", values)
+	}
+
+	v := reflect.ValueOf(values)
+	length := perDimension * len(dimensions)
+	if v.Len() != length {
+		return nil, fmt.Errorf("Expected %d values, got %d", length, v.Len())
+	}
+
+	result := make([]int64, length)
+	for i := range result {
+		value, err := dimensions[i/perDimension].value(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+// typedSlice converts values holding perDimension values for each dimension
+// to a slice of the type of the dimensions, or []int64 if the dimensions have
+// different types
+func typedSlice(dimensions []integerDimension, values []int64, perDimension int) (interface{}, error) {
+	datatype := dimensions[0].datatype
+	for _, dimension := range dimensions {
+		if dimension.datatype != datatype {
+			datatype = TILEDB_INT64
+		}
+	}
+
+	slice, _, err := datatype.MakeSlice(uint64(len(values)))
+	if err != nil {
+		return nil, err
+	}
+	result := reflect.ValueOf(slice)
+	for i, value := range values {
+		dimension := dimensions[i/perDimension]
+		typed := reflect.ValueOf(dimension.typed(value))
+		if datatype == TILEDB_INT64 && dimension.unsigned && typed.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("Value %d of dimension %s does not fit in an int64, the dimensions have different types", typed.Uint(), dimension.name)
+		}
+		result.Index(i).Set(typed.Convert(result.Type().Elem()))
+	}
+	return slice, nil
+}
+
+// subarrayBounds validates a subarray [start, end, ...] against the domain.
+// A nil subarray is the whole domain.
+func subarrayBounds(dimensions []integerDimension, subarray interface{}) ([]int64, error) {
+	if subarray == nil {
+		bounds := make([]int64, 0, 2*len(dimensions))
+		for _, dimension := range dimensions {
+			bounds = append(bounds, dimension.lower, dimension.upper)
+		}
+		return bounds, nil
+	}
+
+	bounds, err := integerSlice(dimensions, subarray, 2)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid subarray: %s", err)
+	}
+	for d, dimension := range dimensions {
+		start, end := bounds[2*d], bounds[2*d+1]
+		if start > end || start < dimension.lower || end > dimension.upper {
+			return nil, fmt.Errorf("Invalid subarray: range [%v, %v] is not within the domain [%v, %v] of dimension %s",
+				dimension.typed(start), dimension.typed(end), dimension.typed(dimension.lower), dimension.typed(dimension.upper), dimension.name)
+		}
+	}
+	return bounds, nil
+}
+
+// layoutOrder returns the dimension indexes from the slowest to the fastest
+// varying in the layout
+func layoutOrder(nDim int, layout Layout) ([]int, error) {
+	order := make([]int, nDim)
+	switch layout {
+	case TILEDB_ROW_MAJOR:
+		for d := range order {
+			order[d] = d
+		}
+	case TILEDB_COL_MAJOR:
+		for d := range order {
+			order[d] = nDim - 1 - d
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported layout %d, expected row or column major", layout)
+	}
+	return order, nil
+}
+
+// CellNum returns the number of cells of a subarray [start, end, ...] of an
+// integer domain, or of the whole domain if subarray is nil
+func (d *Domain) CellNum(subarray interface{}) (uint64, error) {
+	dimensions, err := d.integerDimensions()
+	if err != nil {
+		return 0, err
+	}
+	bounds, err := subarrayBounds(dimensions, subarray)
+	if err != nil {
+		return 0, err
+	}
+
+	cells := uint64(1)
+	for dimIdx := range dimensions {
+		size, err := rangeCells(bounds[2*dimIdx], bounds[2*dimIdx+1])
+		if err != nil {
+			return 0, err
+		}
+		cells, err = mulCells(cells, size)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return cells, nil
+}
+
+// TileFromCoordinates returns the index of the tile along each dimension
+// containing the cell at the given coordinates
+func (d *Domain) TileFromCoordinates(coordinates interface{}) ([]uint64, error) {
+	dimensions, err := d.integerDimensions()
+	if err != nil {
+		return nil, err
+	}
+	values, err := integerSlice(dimensions, coordinates, 1)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid coordinates: %s", err)
+	}
+
+	tile := make([]uint64, len(dimensions))
+	for dimIdx, dimension := range dimensions {
+		value := values[dimIdx]
+		if value < dimension.lower || value > dimension.upper {
+			return nil, fmt.Errorf("Coordinate %v is not within the domain of dimension %s", dimension.typed(value), dimension.name)
+		}
+		tile[dimIdx] = uint64(value-dimension.lower) / uint64(dimension.extent)
+	}
+	return tile, nil
+}
+
+// TileBounds returns the subarray [start, end, ...] covered by a tile, given
+// by its index along each dimension. Tiles are clamped to the domain. The
+// subarray is a slice of the type of the dimensions, []int64 if they have
+// different types, in which case uint64 values must fit in an int64.
+func (d *Domain) TileBounds(tile []uint64) (interface{}, error) {
+	dimensions, err := d.integerDimensions()
+	if err != nil {
+		return nil, err
+	}
+	if len(tile) != len(dimensions) {
+		return nil, fmt.Errorf("Expected a tile index for each of the %d dimensions, got %d", len(dimensions), len(tile))
+	}
+
+	bounds := make([]int64, 0, 2*len(dimensions))
+	for dimIdx, dimension := range dimensions {
+		if tile[dimIdx] >= dimension.tileNum() {
+			return nil, fmt.Errorf("Tile index %d out of range for dimension %s", tile[dimIdx], dimension.name)
+		}
+		// The offset of the tile is within the domain, computed unsigned as
+		// it may exceed math.MaxInt64
+		start := int64(uint64(dimension.lower) + tile[dimIdx]*uint64(dimension.extent))
+		end := start + dimension.extent - 1
+		if end > dimension.upper || end < start {
+			end = dimension.upper
+		}
+		bounds = append(bounds, start, end)
+	}
+	return typedSlice(dimensions, bounds, 2)
+}
+
+// Tiles returns the indexes of the tiles intersecting a subarray
+// [start, end, ...], or all tiles if subarray is nil, enumerated in the
+// given layout (TILEDB_ROW_MAJOR or TILEDB_COL_MAJOR)
+func (d *Domain) Tiles(subarray interface{}, layout Layout) ([][]uint64, error) {
+	dimensions, err := d.integerDimensions()
+	if err != nil {
+		return nil, err
+	}
+	bounds, err := subarrayBounds(dimensions, subarray)
+	if err != nil {
+		return nil, err
+	}
+	order, err := layoutOrder(len(dimensions), layout)
+	if err != nil {
+		return nil, err
+	}
+
+	first := make([]uint64, len(dimensions))
+	last := make([]uint64, len(dimensions))
+	for dimIdx, dimension := range dimensions {
+		first[dimIdx] = uint64(bounds[2*dimIdx]-dimension.lower) / uint64(dimension.extent)
+		last[dimIdx] = uint64(bounds[2*dimIdx+1]-dimension.lower) / uint64(dimension.extent)
+	}
+
+	tiles := make([][]uint64, 0)
+	tile := append([]uint64{}, first...)
+	for {
+		tiles = append(tiles, append([]uint64{}, tile...))
+
+		// Increment the fastest varying dimension, carrying over
+		k := len(order) - 1
+		for ; k >= 0; k-- {
+			dimIdx := order[k]
+			if tile[dimIdx] < last[dimIdx] {
+				tile[dimIdx]++
+				break
+			}
+			tile[dimIdx] = first[dimIdx]
+		}
+		if k < 0 {
+			return tiles, nil
+		}
+	}
+}
+
+// LinearIndex returns the position of the cell at the given coordinates
+// among the cells of a subarray [start, end, ...] (the whole domain if
+// subarray is nil) ordered in the given layout
+func (d *Domain) LinearIndex(coordinates interface{}, subarray interface{}, layout Layout) (uint64, error) {
+	dimensions, err := d.integerDimensions()
+	if err != nil {
+		return 0, err
+	}
+	bounds, err := subarrayBounds(dimensions, subarray)
+	if err != nil {
+		return 0, err
+	}
+	values, err := integerSlice(dimensions, coordinates, 1)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid coordinates: %s", err)
+	}
+	order, err := layoutOrder(len(dimensions), layout)
+	if err != nil {
+		return 0, err
+	}
+
+	var index uint64
+	for _, dimIdx := range order {
+		start, end := bounds[2*dimIdx], bounds[2*dimIdx+1]
+		value := values[dimIdx]
+		if value < start || value > end {
+			dimension := dimensions[dimIdx]
+			return 0, fmt.Errorf("Coordinate %v is not within the subarray range [%v, %v] of dimension %s",
+				dimension.typed(value), dimension.typed(start), dimension.typed(end), dimension.name)
+		}
+		size, err := rangeCells(start, end)
+		if err != nil {
+			return 0, err
+		}
+		index, err = mulCells(index, size)
+		if err != nil {
+			return 0, err
+		}
+		offset := uint64(value - start)
+		if index+offset < index {
+			return 0, fmt.Errorf("Linear index of coordinates %v overflows", coordinates)
+		}
+		index += offset
+	}
+	return index, nil
+}
+
+// CoordinatesFromLinearIndex returns the coordinates of the cell at a
+// position among the cells of a subarray [start, end, ...] (the whole domain
+// if subarray is nil) ordered in the given layout. The coordinates are a
+// slice of the type of the dimensions, []int64 if they have different types,
+// in which case uint64 values must fit in an int64.
+func (d *Domain) CoordinatesFromLinearIndex(index uint64, subarray interface{}, layout Layout) (interface{}, error) {
+	dimensions, err := d.integerDimensions()
+	if err != nil {
+		return nil, err
+	}
+	bounds, err := subarrayBounds(dimensions, subarray)
+	if err != nil {
+		return nil, err
+	}
+	order, err := layoutOrder(len(dimensions), layout)
+	if err != nil {
+		return nil, err
+	}
+
+	coordinates := make([]int64, len(dimensions))
+	remaining := index
+	for k := len(order) - 1; k >= 0; k-- {
+		dimIdx := order[k]
+		size, err := rangeCells(bounds[2*dimIdx], bounds[2*dimIdx+1])
+		if err != nil {
+			return nil, err
+		}
+		coordinates[dimIdx] = bounds[2*dimIdx] + int64(remaining%size)
+		remaining /= size
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("Linear index %d out of range", index)
+	}
+	return typedSlice(dimensions, coordinates, 1)
+}
@@ -0,0 +1,149 @@
+package tiledb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainTileMath(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	// 4x3 domain with 2x2 tiles, the last tile of cols is truncated
+	rows, err := NewDimension(context, "rows", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	cols, err := NewDimension(context, "cols", []int32{1, 3}, int32(2))
+	assert.Nil(t, err)
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(rows, cols))
+
+	cells, err := domain.CellNum(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(12), cells)
+	cells, err = domain.CellNum([]int32{1, 2, 2, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), cells)
+	_, err = domain.CellNum([]int32{0, 2, 2, 3})
+	assert.NotNil(t, err)
+
+	tile, err := domain.TileFromCoordinates([]int32{3, 3})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 1}, tile)
+
+	bounds, err := domain.TileBounds(tile)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{3, 4, 3, 3}, bounds)
+	_, err = domain.TileBounds([]uint64{2, 0})
+	assert.NotNil(t, err)
+
+	tiles, err := domain.Tiles([]int32{2, 3, 1, 3}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]uint64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}, tiles)
+	tiles, err = domain.Tiles([]int32{2, 3, 1, 3}, TILEDB_COL_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]uint64{{0, 0}, {1, 0}, {0, 1}, {1, 1}}, tiles)
+	tiles, err = domain.Tiles([]int32{1, 1, 1, 1}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]uint64{{0, 0}}, tiles)
+
+	index, err := domain.LinearIndex([]int32{2, 3}, nil, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), index)
+	index, err = domain.LinearIndex([]int32{2, 3}, nil, TILEDB_COL_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(9), index)
+	index, err = domain.LinearIndex([]int32{2, 3}, []int32{2, 3, 2, 3}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), index)
+
+	coordinates, err := domain.CoordinatesFromLinearIndex(5, nil, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{2, 3}, coordinates)
+	coordinates, err = domain.CoordinatesFromLinearIndex(9, nil, TILEDB_COL_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{2, 3}, coordinates)
+	_, err = domain.CoordinatesFromLinearIndex(12, nil, TILEDB_ROW_MAJOR)
+	assert.NotNil(t, err)
+
+	// Only integer domains are supported
+	x, err := NewDimension(context, "x", []float64{0, 1}, float64(0.5))
+	assert.Nil(t, err)
+	floatDomain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, floatDomain.AddDimensions(x))
+	_, err = floatDomain.CellNum(nil)
+	assert.NotNil(t, err)
+}
+
+func TestDomainMathOverflow(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	// The domain holds 2^96 cells, more than a uint64 counts
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	for _, name := range []string{"x", "y", "z"} {
+		dimension, err := NewDimension(context, name, []int64{0, 1<<32 - 1}, int64(1<<16))
+		assert.Nil(t, err)
+		assert.Nil(t, domain.AddDimensions(dimension))
+	}
+	_, err = domain.CellNum(nil)
+	assert.NotNil(t, err)
+	_, err = domain.LinearIndex([]int64{1<<32 - 1, 1<<32 - 1, 1<<32 - 1}, nil, TILEDB_ROW_MAJOR)
+	assert.NotNil(t, err)
+	cells, err := domain.CellNum([]int64{0, 1<<32 - 1, 0, 1<<31 - 1, 0, 0})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1<<63), cells)
+
+	// uint64 values above math.MaxInt64 are rejected instead of wrapping
+	_, err = integerValue(reflect.ValueOf(uint64(math.MaxUint64)))
+	assert.NotNil(t, err)
+	_, err = mulCells(math.MaxUint64/2, 3)
+	assert.NotNil(t, err)
+	_, err = rangeCells(math.MinInt64, math.MaxInt64)
+	assert.NotNil(t, err)
+}
+
+func TestDomainMathUint64(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	// The domain is above math.MaxInt64
+	x, err := NewDimension(context, "x", []uint64{1 << 63, 1<<63 + 99}, uint64(10))
+	assert.Nil(t, err)
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(x))
+
+	cells, err := domain.CellNum(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), cells)
+
+	tile, err := domain.TileFromCoordinates([]uint64{1<<63 + 25})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{2}, tile)
+	bounds, err := domain.TileBounds(tile)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1<<63 + 20, 1<<63 + 29}, bounds)
+
+	index, err := domain.LinearIndex([]uint64{1<<63 + 25}, []uint64{1<<63 + 20, 1<<63 + 29}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), index)
+	coordinates, err := domain.CoordinatesFromLinearIndex(25, nil, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1<<63 + 25}, coordinates)
+
+	tiles, err := domain.Tiles([]uint64{1<<63 + 15, 1<<63 + 35}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]uint64{{1}, {2}, {3}}, tiles)
+
+	// Negative values are out of the domain of unsigned dimensions
+	_, err = domain.TileFromCoordinates([]int64{-1})
+	assert.NotNil(t, err)
+	_, err = domain.TileFromCoordinates([]uint64{5})
+	assert.NotNil(t, err)
+}
@@ -111,7 +111,12 @@ func (d Datatype) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON interface for unmarshaling from json
 func (d *Datatype) UnmarshalJSON(bytes []byte) error {
-	return d.FromString(string(bytes))
+	var s string
+	err := json.Unmarshal(bytes, &s)
+	if err != nil {
+		return err
+	}
+	return d.FromString(s)
 }
 
 // FromString converts from a datatype string to enum
@@ -180,6 +185,28 @@ func (d Datatype) ReflectKind() reflect.Kind {
 	}
 }
 
+// IsDatetime returns true if the datatype is one of the TILEDB_DATETIME types
+func (d Datatype) IsDatetime() bool {
+	switch d {
+	case TILEDB_DATETIME_YEAR, TILEDB_DATETIME_MONTH, TILEDB_DATETIME_WEEK, TILEDB_DATETIME_DAY, TILEDB_DATETIME_HR, TILEDB_DATETIME_MIN, TILEDB_DATETIME_SEC, TILEDB_DATETIME_MS, TILEDB_DATETIME_US, TILEDB_DATETIME_NS, TILEDB_DATETIME_PS, TILEDB_DATETIME_FS, TILEDB_DATETIME_AS:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsString returns true if the datatype stores 8-bit characters that are
+// represented as a go string (TILEDB_CHAR, TILEDB_STRING_ASCII and
+// TILEDB_STRING_UTF8)
+func (d Datatype) IsString() bool {
+	switch d {
+	case TILEDB_CHAR, TILEDB_STRING_ASCII, TILEDB_STRING_UTF8:
+		return true
+	default:
+		return false
+	}
+}
+
 // Size returns the datatype size in bytes
 func (d Datatype) Size() uint64 {
 	return uint64(C.tiledb_datatype_size(C.tiledb_datatype_t(d)))
@@ -308,6 +335,30 @@ const (
 	TILEDB_UNORDERED Layout = C.TILEDB_UNORDERED
 )
 
+// ObjectType is the type of a tiledb object
+type ObjectType int8
+
+const (
+	// TILEDB_INVALID Invalid object (not a tiledb object)
+	TILEDB_INVALID ObjectType = C.TILEDB_INVALID
+	// TILEDB_GROUP Group object
+	TILEDB_GROUP ObjectType = C.TILEDB_GROUP
+	// TILEDB_ARRAY Array object
+	TILEDB_ARRAY ObjectType = C.TILEDB_ARRAY
+)
+
+// String returns the name of the object type
+func (o ObjectType) String() string {
+	switch o {
+	case TILEDB_GROUP:
+		return "group"
+	case TILEDB_ARRAY:
+		return "array"
+	default:
+		return "invalid"
+	}
+}
+
 // QueryStatus status of a query
 type QueryStatus int8
 
@@ -359,6 +410,16 @@ const (
 	TILEDB_VFS_APPEND VFSMode = C.TILEDB_VFS_APPEND
 )
 
+// WalkOrder is the order in which objects are visited by a walk
+type WalkOrder int8
+
+const (
+	// TILEDB_PREORDER Visit a group before its members
+	TILEDB_PREORDER WalkOrder = C.TILEDB_PREORDER
+	// TILEDB_POSTORDER Visit a group after its members
+	TILEDB_POSTORDER WalkOrder = C.TILEDB_POSTORDER
+)
+
 // TILEDB_VAR_NUM indicates variable sized attributes for cell values
 var TILEDB_VAR_NUM = uint(C.TILEDB_VAR_NUM)
 
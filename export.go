@@ -0,0 +1,194 @@
+package tiledb
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportOptions controls how cells are formatted by ExportCSV and
+// ExportJSONLines
+type ExportOptions struct {
+	// Header writes a first row with the field names (CSV only)
+	Header bool
+	// TimeLayout is the layout used to format datetime values, defaults to
+	// time.RFC3339Nano
+	TimeLayout string
+	// Separator is used to join the values of multi valued and variable
+	// sized numeric cells in CSV, defaults to ";"
+	Separator string
+}
+
+func (o *ExportOptions) timeLayout() string {
+	if o == nil || o.TimeLayout == "" {
+		return time.RFC3339Nano
+	}
+	return o.TimeLayout
+}
+
+func (o *ExportOptions) separator() string {
+	if o == nil || o.Separator == "" {
+		return ";"
+	}
+	return o.Separator
+}
+
+// formatScalar formats a single numeric value of a field
+func formatScalar(datatype Datatype, value reflect.Value, timeLayout string) string {
+	if datatype.IsDatetime() {
+		return GetTimeFromTimestamp(datatype, value.Int()).Format(timeLayout)
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	default:
+		return fmt.Sprintf("%v", value.Interface())
+	}
+}
+
+// jsonValue converts a cell value to a value that marshals to json, with
+// datetime values formatted as strings
+func jsonValue(datatype Datatype, cell interface{}, timeLayout string) interface{} {
+	if bytes, ok := cell.([]uint8); ok {
+		// Avoid the base64 encoding of []byte by encoding/json
+		values := make([]uint16, len(bytes))
+		for i, b := range bytes {
+			values[i] = uint16(b)
+		}
+		return values
+	}
+
+	if !datatype.IsDatetime() {
+		return cell
+	}
+
+	value := reflect.ValueOf(cell)
+	if value.Kind() != reflect.Slice {
+		return formatScalar(datatype, value, timeLayout)
+	}
+
+	times := make([]string, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		times[i] = formatScalar(datatype, value.Index(i), timeLayout)
+	}
+	return times
+}
+
+/*
+ExportCSV writes all cells returned by the reader to w in CSV format, one
+row per cell and one column per field of the reader. Results are streamed
+batch by batch so the subarray being exported does not need to fit in memory.
+Strings are written as is, datetimes are formatted with
+ExportOptions.TimeLayout and multi valued cells are joined with
+ExportOptions.Separator. options may be nil.
+*/
+func ExportCSV(w io.Writer, reader *BatchReader, options *ExportOptions) error {
+	writer := csv.NewWriter(w)
+	fields := reader.Fields()
+
+	if options != nil && options.Header {
+		header := make([]string, len(fields))
+		for i, field := range fields {
+			header[i] = field.Name
+		}
+		err := writer.Write(header)
+		if err != nil {
+			return fmt.Errorf("Error writing csv header: %s", err)
+		}
+	}
+
+	timeLayout := options.timeLayout()
+	separator := options.separator()
+	record := make([]string, len(fields))
+	err := reader.ReadAll(func(batch *Batch) error {
+		for i := uint64(0); i < batch.NumCells; i++ {
+			for j, field := range fields {
+				cell, err := batch.Cell(field.Name, i)
+				if err != nil {
+					return err
+				}
+
+				if str, ok := cell.(string); ok {
+					record[j] = str
+					continue
+				}
+
+				value := reflect.ValueOf(cell)
+				if value.Kind() != reflect.Slice {
+					record[j] = formatScalar(field.Datatype, value, timeLayout)
+					continue
+				}
+
+				values := make([]string, value.Len())
+				for k := 0; k < value.Len(); k++ {
+					values[k] = formatScalar(field.Datatype, value.Index(k), timeLayout)
+				}
+				record[j] = strings.Join(values, separator)
+			}
+
+			err := writer.Write(record)
+			if err != nil {
+				return fmt.Errorf("Error writing csv record: %s", err)
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return fmt.Errorf("Error exporting csv: %s", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+/*
+ExportJSONLines writes all cells returned by the reader to w as newline
+delimited json, one object per cell keyed by field name. Results are
+streamed batch by batch so the subarray being exported does not need to fit
+in memory. Datetimes are formatted with ExportOptions.TimeLayout.
+options may be nil.
+*/
+func ExportJSONLines(w io.Writer, reader *BatchReader, options *ExportOptions) error {
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+	fields := reader.Fields()
+	timeLayout := options.timeLayout()
+
+	err := reader.ReadAll(func(batch *Batch) error {
+		for i := uint64(0); i < batch.NumCells; i++ {
+			row := make(map[string]interface{}, len(fields))
+			for _, field := range fields {
+				cell, err := batch.Cell(field.Name, i)
+				if err != nil {
+					return err
+				}
+				row[field.Name] = jsonValue(field.Datatype, cell, timeLayout)
+			}
+
+			err := encoder.Encode(row)
+			if err != nil {
+				return fmt.Errorf("Error writing json record: %s", err)
+			}
+		}
+		return writer.Flush()
+	})
+	if err != nil {
+		return fmt.Errorf("Error exporting json lines: %s", err)
+	}
+
+	return writer.Flush()
+}
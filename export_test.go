@@ -0,0 +1,71 @@
+package tiledb
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportCSV(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_export_csv")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	reader, err := NewBatchReader(context, array, nil, 2)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+
+	var buffer bytes.Buffer
+	err = ExportCSV(&buffer, reader, &ExportOptions{Header: true, TimeLayout: "2006-01-02"})
+	assert.Nil(t, err)
+	assert.Equal(t, "rows,cols,a1,a2,a3\n"+
+		"1,1,1,a,2020-01-01\n"+
+		"2,1,2,bb,2020-01-02\n"+
+		"2,2,3,ccc,2020-01-03\n", buffer.String())
+}
+
+func TestExportJSONLines(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_export_json")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	reader, err := NewBatchReader(context, array, []string{"rows", "cols", "a2", "a3"}, 16)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+	assert.Nil(t, reader.Query().AddRange(0, int32(2), int32(2)))
+
+	var buffer bytes.Buffer
+	err = ExportJSONLines(&buffer, reader, nil)
+	assert.Nil(t, err)
+	assert.Equal(t,
+		`{"a2":"bb","a3":"2020-01-02T00:00:00Z","cols":1,"rows":2}`+"\n"+
+			`{"a2":"ccc","a3":"2020-01-03T00:00:00Z","cols":2,"rows":2}`+"\n",
+		buffer.String())
+}
@@ -28,6 +28,7 @@ func NewFilter(context *Context, filterType FilterType) (*Filter, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb filter: %s", filter.context.LastError())
 	}
+	trackHandle("tiledb_filter_t", unsafe.Pointer(filter.tiledbFilter))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&filter, func(filter *Filter) {
@@ -40,10 +41,17 @@ func NewFilter(context *Context, filterType FilterType) (*Filter, error) {
 // Free c-alloc'ed data types
 func (f *Filter) Free() {
 	if f.tiledbFilter != nil {
+		untrackHandle(unsafe.Pointer(f.tiledbFilter))
 		C.tiledb_filter_free(&f.tiledbFilter)
 	}
 }
 
+// Close frees the filter, implementing io.Closer. Closing twice is a no-op.
+func (f *Filter) Close() error {
+	f.Free()
+	return nil
+}
+
 // Type returns the filter type
 func (f *Filter) Type() (FilterType, error) {
 	var filterType C.tiledb_filter_type_t
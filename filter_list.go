@@ -11,6 +11,7 @@ import "C"
 import (
 	"fmt"
 	"runtime"
+	"unsafe"
 )
 
 // FilterList represents
@@ -27,6 +28,7 @@ func NewFilterList(context *Context) (*FilterList, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb FilterList: %s", filterList.context.LastError())
 	}
+	trackHandle("tiledb_filter_list_t", unsafe.Pointer(filterList.tiledbFilterList))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&filterList, func(filterList *FilterList) {
@@ -39,10 +41,18 @@ func NewFilterList(context *Context) (*FilterList, error) {
 // Free c-alloc'ed data types
 func (f *FilterList) Free() {
 	if f.tiledbFilterList != nil {
+		untrackHandle(unsafe.Pointer(f.tiledbFilterList))
 		C.tiledb_filter_list_free(&f.tiledbFilterList)
 	}
 }
 
+// Close frees the filter list, implementing io.Closer. Closing twice is a
+// no-op.
+func (f *FilterList) Close() error {
+	f.Free()
+	return nil
+}
+
 // AddFilter appends a filter to a filter list. Data is processed through
 // each filter in the order the filters were added.
 func (f *FilterList) AddFilter(filter *Filter) error {
@@ -89,6 +99,7 @@ func (f *FilterList) FilterFromIndex(index uint32) (*Filter, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error fetching filter for index %d from tiledb FilterList: %s", index, f.context.LastError())
 	}
+	trackHandle("tiledb_filter_t", unsafe.Pointer(filter.tiledbFilter))
 	return &filter, nil
 }
 
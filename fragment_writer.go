@@ -0,0 +1,282 @@
+package tiledb
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// FragmentWriterOptions configures a FragmentWriter
+type FragmentWriterOptions struct {
+	// Workers is the number of concurrent write queries, runtime.NumCPU()
+	// if 0
+	Workers int
+	// Consolidate, if set, is the config used to consolidate the fragments
+	// of the array once all writes have completed
+	Consolidate *Config
+}
+
+// fragment is one write query, writing a single fragment
+type fragment struct {
+	subarray interface{}
+	data     map[string]interface{}
+	offsets  map[string][]uint64
+}
+
+/*
+FragmentWriter writes cells to an array with concurrent write queries. Each
+write is handed to a pool of workers and written as its own fragment, sparse
+writes in unordered layout and dense writes to their subarray in row major
+layout. Dense subarrays must not overlap.
+
+	writer, err := NewFragmentWriter(ctx, uri, &FragmentWriterOptions{Consolidate: config})
+	for _, chunk := range chunks {
+		err = writer.WriteSparse(map[string]interface{}{"rows": chunk.rows, "a1": chunk.a1}, nil)
+	}
+	err = writer.Close()
+
+Writes may be queued from several goroutines. A write concurrent with Close
+is either written before Close returns or fails. The slices passed to a
+write must not be modified until Close returns.
+*/
+type FragmentWriter struct {
+	context   *Context
+	uri       string
+	arrayType ArrayType
+	options   FragmentWriterOptions
+
+	fragments chan fragment
+	wg        sync.WaitGroup
+	mutex     sync.Mutex
+	err       error
+	subarrays []interface{}
+	// sending is held for reading while a fragment is queued and for
+	// writing while closing, so fragments are never sent once closed
+	sending sync.RWMutex
+	closed  bool
+}
+
+// NewFragmentWriter creates a FragmentWriter for the array at uri and starts
+// its workers. Each worker opens the array in WRITE mode.
+func NewFragmentWriter(ctx *Context, uri string, options *FragmentWriterOptions) (*FragmentWriter, error) {
+	writer := FragmentWriter{context: ctx, uri: uri}
+	if options != nil {
+		writer.options = *options
+	}
+	if writer.options.Workers <= 0 {
+		writer.options.Workers = runtime.NumCPU()
+	}
+
+	schema, err := LoadArraySchema(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating fragment writer: %s", err)
+	}
+	defer schema.Free()
+
+	writer.arrayType, err = schema.Type()
+	if err != nil {
+		return nil, fmt.Errorf("Error creating fragment writer: %s", err)
+	}
+
+	arrays := make([]*Array, writer.options.Workers)
+	for i := range arrays {
+		arrays[i], err = NewArray(ctx, uri)
+		if err == nil {
+			err = arrays[i].Open(TILEDB_WRITE)
+		}
+		if err != nil {
+			for _, array := range arrays[:i+1] {
+				if array != nil {
+					array.Free()
+				}
+			}
+			return nil, fmt.Errorf("Error creating fragment writer: %s", err)
+		}
+	}
+
+	writer.fragments = make(chan fragment, writer.options.Workers)
+	for _, array := range arrays {
+		writer.wg.Add(1)
+		go writer.work(array)
+	}
+
+	return &writer, nil
+}
+
+// work writes fragments until the writer is closed
+func (w *FragmentWriter) work(array *Array) {
+	defer w.wg.Done()
+	defer array.Free()
+
+	for f := range w.fragments {
+		// Skip the remaining fragments after an error
+		if w.Err() != nil {
+			continue
+		}
+
+		err := writeFragment(w.context, array, f)
+		if err != nil {
+			w.mutex.Lock()
+			if w.err == nil {
+				w.err = err
+			}
+			w.mutex.Unlock()
+		}
+	}
+}
+
+// writeFragment submits one write query, in row major order to the subarray
+// of the fragment if set, unordered otherwise
+func writeFragment(ctx *Context, array *Array, f fragment) error {
+	query, err := NewQuery(ctx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	if f.subarray != nil {
+		err = query.SetLayout(TILEDB_ROW_MAJOR)
+		if err != nil {
+			return err
+		}
+		err = query.SetSubArray(f.subarray)
+	} else {
+		err = query.SetLayout(TILEDB_UNORDERED)
+	}
+	if err != nil {
+		return err
+	}
+
+	for name, data := range f.data {
+		if offsets, ok := f.offsets[name]; ok {
+			_, _, err = query.SetBufferVar(name, offsets, data)
+		} else {
+			_, err = query.SetBuffer(name, data)
+		}
+		if err != nil {
+			return fmt.Errorf("Error setting buffer for %s: %s", name, err)
+		}
+	}
+
+	err = query.Submit()
+	if err != nil {
+		return fmt.Errorf("Error writing fragment: %s", err)
+	}
+	return query.Finalize()
+}
+
+// Err returns the first error of the workers, if any
+func (w *FragmentWriter) Err() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.err
+}
+
+// write queues a fragment
+func (w *FragmentWriter) write(f fragment) error {
+	w.sending.RLock()
+	defer w.sending.RUnlock()
+	if w.closed {
+		return fmt.Errorf("Error writing fragment: writer is closed")
+	}
+
+	err := w.Err()
+	if err != nil {
+		return err
+	}
+
+	w.fragments <- f
+	return nil
+}
+
+// WriteSparse queues cells to be written as a fragment of a sparse array.
+// data holds the buffers of all dimensions and attributes, offsets the
+// offsets (in bytes) of variable sized ones. An error of a previous write is
+// returned if any.
+func (w *FragmentWriter) WriteSparse(data map[string]interface{}, offsets map[string][]uint64) error {
+	if w.arrayType != TILEDB_SPARSE {
+		return fmt.Errorf("Error writing fragment: WriteSparse requires a sparse array")
+	}
+	return w.write(fragment{data: data, offsets: offsets})
+}
+
+// subarraysOverlap returns true if two integer subarrays [start, end, ...]
+// intersect
+func subarraysOverlap(a, b interface{}) bool {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() || va.Len() != vb.Len() {
+		return false
+	}
+	for i := 0; i+1 < va.Len(); i += 2 {
+		c1, err := CompareValues(va.Index(i+1).Interface(), vb.Index(i).Interface())
+		if err != nil {
+			return false
+		}
+		c2, _ := CompareValues(vb.Index(i+1).Interface(), va.Index(i).Interface())
+		if c1 < 0 || c2 < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteDense queues the cells of a subarray [start, end, ...] to be written
+// as a fragment of a dense array, in row major order. data holds the buffers
+// of all attributes, offsets the offsets (in bytes) of variable sized ones.
+// Subarrays overlapping a previous write are rejected.
+func (w *FragmentWriter) WriteDense(subarray interface{}, data map[string]interface{}, offsets map[string][]uint64) error {
+	if w.arrayType != TILEDB_DENSE {
+		return fmt.Errorf("Error writing fragment: WriteDense requires a dense array")
+	}
+	if subarray == nil || reflect.TypeOf(subarray).Kind() != reflect.Slice {
+		return fmt.Errorf("Error writing fragment: subarray must be a slice")
+	}
+
+	w.mutex.Lock()
+	for _, previous := range w.subarrays {
+		if subarraysOverlap(previous, subarray) {
+			w.mutex.Unlock()
+			return fmt.Errorf("Error writing fragment: subarray %v overlaps subarray %v", subarray, previous)
+		}
+	}
+	w.subarrays = append(w.subarrays, subarray)
+	w.mutex.Unlock()
+
+	return w.write(fragment{subarray: subarray, data: data, offsets: offsets})
+}
+
+// Close waits for all queued writes to complete, consolidates the array if
+// requested and returns the first error of the writes
+func (w *FragmentWriter) Close() error {
+	// Waits for the writes being queued, the workers keep receiving
+	w.sending.Lock()
+	if w.closed {
+		w.sending.Unlock()
+		return w.Err()
+	}
+	w.closed = true
+	close(w.fragments)
+	w.sending.Unlock()
+
+	w.wg.Wait()
+
+	err := w.Err()
+	if err != nil {
+		return err
+	}
+
+	if w.options.Consolidate != nil {
+		array, err := NewArray(w.context, w.uri)
+		if err != nil {
+			return err
+		}
+		defer array.Free()
+
+		err = array.Consolidate(w.options.Consolidate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package tiledb
+
+import (
+	"io"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFragmentWriterSparse(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_fragment_writer_sparse")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	config, err := NewConfig()
+	assert.Nil(t, err)
+
+	writer, err := NewFragmentWriter(context, tmpArrayPath, &FragmentWriterOptions{Workers: 2, Consolidate: config})
+	assert.Nil(t, err)
+
+	// Dense writes are rejected for sparse arrays
+	assert.NotNil(t, writer.WriteDense([]int32{1, 1, 1, 1}, nil, nil))
+
+	for _, row := range []int32{3, 4} {
+		err = writer.WriteSparse(map[string]interface{}{
+			"rows": []int32{row},
+			"cols": []int32{1},
+			"a1":   []int32{row * 10},
+			"a2":   []byte("d"),
+			"a3":   []int64{18265},
+		}, map[string][]uint64{"a2": {0}})
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, writer.Close())
+
+	// Writing after Close fails
+	assert.NotNil(t, writer.WriteSparse(map[string]interface{}{}, nil))
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	reader, err := NewBatchReader(context, array, []string{"a1"}, 16)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+
+	batch, err := reader.Next()
+	assert.Nil(t, err)
+	data, err := batch.Data("a1")
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 2, 3, 30, 40}, data)
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestFragmentWriterCloseWhileWriting(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_fragment_writer_close")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	writer, err := NewFragmentWriter(context, tmpArrayPath, &FragmentWriterOptions{Workers: 1})
+	assert.Nil(t, err)
+
+	// Writes racing with Close are written or fail, they never panic
+	var wg sync.WaitGroup
+	for row := int32(3); row <= 4; row++ {
+		wg.Add(1)
+		go func(row int32) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				err := writer.WriteSparse(map[string]interface{}{
+					"rows": []int32{row},
+					"cols": []int32{4},
+					"a1":   []int32{row},
+					"a2":   []byte("d"),
+					"a3":   []int64{18265},
+				}, map[string][]uint64{"a2": {0}})
+				if err != nil {
+					return
+				}
+			}
+		}(row)
+	}
+	assert.Nil(t, writer.Close())
+	wg.Wait()
+}
+
+func TestFragmentWriterDense(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_fragment_writer_dense")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	rows, err := NewDimension(context, "rows", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	cols, err := NewDimension(context, "cols", []int32{1, 2}, int32(2))
+	assert.Nil(t, err)
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(rows, cols))
+
+	arraySchema, err := NewArraySchema(context, TILEDB_DENSE)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.SetDomain(domain))
+	a1, err := NewAttribute(context, "a1", TILEDB_INT32)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.AddAttributes(a1))
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+
+	writer, err := NewFragmentWriter(context, tmpArrayPath, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, writer.WriteDense([]int32{1, 2, 1, 2}, map[string]interface{}{"a1": []int32{1, 2, 3, 4}}, nil))
+	assert.Nil(t, writer.WriteDense([]int32{3, 4, 1, 2}, map[string]interface{}{"a1": []int32{5, 6, 7, 8}}, nil))
+
+	// Overlapping subarrays are rejected
+	assert.NotNil(t, writer.WriteDense([]int32{2, 3, 1, 1}, map[string]interface{}{"a1": []int32{0, 0}}, nil))
+	assert.Nil(t, writer.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	result, err := ReadNDArray(context, array, "a1", []int32{1, 4, 1, 2}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 2, 3, 4, 5, 6, 7, 8}, result.Data)
+}
@@ -0,0 +1,128 @@
+//go:build ignore
+// +build ignore
+
+// gen_typed generates typed_generated.go, the typed wrappers of the
+// interface{} APIs for each Go type values of a Datatype are stored as.
+// Run it with go generate.
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"text/template"
+)
+
+// goType is a Go type values of one or more Datatypes are stored as
+type goType struct {
+	// Name is the suffix of the generated functions
+	Name string
+	// Type is the Go type
+	Type string
+	// Kind is the reflect.Kind of the type
+	Kind string
+	// Datatypes lists the datatypes stored as the type
+	Datatypes string
+}
+
+var goTypes = []goType{
+	{"Int8", "int8", "reflect.Int8", "TILEDB_INT8"},
+	{"Int16", "int16", "reflect.Int16", "TILEDB_INT16"},
+	{"Int32", "int32", "reflect.Int32", "TILEDB_INT32"},
+	{"Int64", "int64", "reflect.Int64", "TILEDB_INT64 and TILEDB_DATETIME_*"},
+	{"Uint8", "uint8", "reflect.Uint8", "TILEDB_UINT8, TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8"},
+	{"Uint16", "uint16", "reflect.Uint16", "TILEDB_UINT16, TILEDB_STRING_UTF16 and TILEDB_STRING_UCS2"},
+	{"Uint32", "uint32", "reflect.Uint32", "TILEDB_UINT32, TILEDB_STRING_UTF32 and TILEDB_STRING_UCS4"},
+	{"Uint64", "uint64", "reflect.Uint64", "TILEDB_UINT64"},
+	{"Float32", "float32", "reflect.Float32", "TILEDB_FLOAT32"},
+	{"Float64", "float64", "reflect.Float64", "TILEDB_FLOAT64"},
+}
+
+var typedTemplate = template.Must(template.New("typed").Parse(`// Code generated by gen_typed.go; DO NOT EDIT.
+
+package tiledb
+
+import (
+	"reflect"
+	"unsafe"
+)
+{{range .}}
+// SetBuffer{{.Name}} sets the buffer of a fixed sized attribute or dimension
+// of datatype {{.Datatypes}}
+func (q *Query) SetBuffer{{.Name}}(attributeOrDimension string, buffer []{{.Type}}) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, {{.Kind}}, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, {{.Kind}}, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// Buffer{{.Name}} returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype {{.Datatypes}}
+func (q *Query) Buffer{{.Name}}(attributeOrDimension string) ([]{{.Type}}, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, {{.Kind}})
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof({{.Type}}(0)))
+	return (*[1 << 46]{{.Type}})(cbuffer)[:length:length], nil
+}
+
+// AddRange{{.Name}} adds the range [start, end] on a dimension of datatype
+// {{.Datatypes}}
+func (q *Query) AddRange{{.Name}}(dimIdx uint32, start {{.Type}}, end {{.Type}}) error {
+	return q.addTypedRange(dimIdx, {{.Kind}}, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// Domain{{.Name}} returns the [lower, upper] domain of a dimension of datatype
+// {{.Datatypes}}
+func (d *Dimension) Domain{{.Name}}() ([]{{.Type}}, error) {
+	cdomain, err := d.typedDomain({{.Kind}})
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]{{.Type}})(cdomain)
+	return domain[:], nil
+}
+
+// Extent{{.Name}} returns the tile extent of a dimension of datatype
+// {{.Datatypes}}
+func (d *Dimension) Extent{{.Name}}() ({{.Type}}, error) {
+	cextent, err := d.typedExtent({{.Kind}})
+	if err != nil {
+		return 0, err
+	}
+	return *(*{{.Type}})(cextent), nil
+}
+
+// GetMetadata{{.Name}} returns the values of a metadata item of datatype
+// {{.Datatypes}}
+func (a *Array) GetMetadata{{.Name}}(key string) ([]{{.Type}}, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, {{.Kind}})
+	if err != nil {
+		return nil, err
+	}
+	value := make([]{{.Type}}, valueNum)
+	copy(value, (*[1 << 46]{{.Type}})(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+{{end}}`))
+
+func main() {
+	var buf bytes.Buffer
+	err := typedTemplate.Execute(&buf, goTypes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = ioutil.WriteFile("typed_generated.go", source, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,256 @@
+package tiledb
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// globalOrderKey holds the tile and cell coordinates of the cells along one
+// dimension
+type globalOrderKey struct {
+	tiles  []int64
+	values []interface{}
+}
+
+/*
+GlobalOrder returns the permutation sorting cells in the global order of a
+sparse array schema: by space tile following the tile order, then by cell
+following the cell order within each tile. buffers holds the coordinates of
+the cells by dimension name, offsets the offsets (in bytes) of variable
+sized dimensions. Cell i of the sorted buffers is cell GlobalOrder()[i] of
+the input.
+
+Tiles are computed from the domain and tile extent of each numeric
+dimension, string dimensions are not tiled.
+*/
+func GlobalOrder(schema *ArraySchema, buffers map[string]interface{}, offsets map[string][]uint64) ([]uint64, error) {
+	batch, err := newWriteBatch(schema, buffers, offsets)
+	if err != nil {
+		return nil, fmt.Errorf("Error computing global order: %s", err)
+	}
+
+	tileOrder, err := schema.TileOrder()
+	if err != nil {
+		return nil, err
+	}
+	cellOrder, err := schema.CellOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]globalOrderKey, nDim)
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		dimension, err := domain.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return nil, err
+		}
+		defer dimension.Free()
+		name, err := dimension.Name()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := buffers[name]; !ok {
+			return nil, fmt.Errorf("Error computing global order: missing coordinates of dimension %s", name)
+		}
+		datatype, err := dimension.Type()
+		if err != nil {
+			return nil, err
+		}
+
+		key := globalOrderKey{
+			tiles:  make([]int64, batch.NumCells),
+			values: make([]interface{}, batch.NumCells),
+		}
+		for i := uint64(0); i < batch.NumCells; i++ {
+			key.values[i], err = batch.Cell(name, i)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !datatype.IsString() {
+			lower, e, err := dimensionTiling(dimension, datatype)
+			if err != nil {
+				return nil, err
+			}
+			for i, value := range key.values {
+				v := reflect.ValueOf(value)
+				switch v.Kind() {
+				case reflect.Float32, reflect.Float64:
+					key.tiles[i] = int64(math.Floor((v.Float() - lower.Float()) / e.Float()))
+				case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+					key.tiles[i] = int64((v.Uint() - lower.Uint()) / e.Uint())
+				default:
+					key.tiles[i] = (v.Int() - lower.Int()) / e.Int()
+				}
+			}
+		}
+		keys[dimIdx] = key
+	}
+
+	// dims returns the dimension indexes in the order they are compared
+	dims := func(layout Layout) []int {
+		order := make([]int, nDim)
+		for d := range order {
+			if layout == TILEDB_COL_MAJOR {
+				order[d] = int(nDim) - 1 - d
+			} else {
+				order[d] = d
+			}
+		}
+		return order
+	}
+	tileDims, cellDims := dims(tileOrder), dims(cellOrder)
+
+	permutation := make([]uint64, batch.NumCells)
+	for i := range permutation {
+		permutation[i] = uint64(i)
+	}
+
+	var sortErr error
+	sort.SliceStable(permutation, func(x, y int) bool {
+		i, j := permutation[x], permutation[y]
+		for _, d := range tileDims {
+			if keys[d].tiles[i] != keys[d].tiles[j] {
+				return keys[d].tiles[i] < keys[d].tiles[j]
+			}
+		}
+		for _, d := range cellDims {
+			c, err := CompareValues(keys[d].values[i], keys[d].values[j])
+			if err != nil {
+				sortErr = err
+				return false
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	if sortErr != nil {
+		return nil, fmt.Errorf("Error computing global order: %s", sortErr)
+	}
+
+	return permutation, nil
+}
+
+// dimensionTiling returns the lower bound of the domain and the tile extent
+// of a numeric dimension. Dimension.Domain and Dimension.Extent do not read
+// datetime dimensions, which are stored as int64.
+func dimensionTiling(dimension *Dimension, datatype Datatype) (reflect.Value, reflect.Value, error) {
+	if datatype.IsDatetime() {
+		cdomain, err := dimension.typedDomain(reflect.Int64)
+		if err != nil {
+			return reflect.Value{}, reflect.Value{}, err
+		}
+		cextent, err := dimension.typedExtent(reflect.Int64)
+		if err != nil {
+			return reflect.Value{}, reflect.Value{}, err
+		}
+		return reflect.ValueOf(*(*int64)(cdomain)), reflect.ValueOf(*(*int64)(cextent)), nil
+	}
+
+	dimensionDomain, err := dimension.Domain()
+	if err != nil {
+		return reflect.Value{}, reflect.Value{}, err
+	}
+	extent, err := dimension.Extent()
+	if err != nil {
+		return reflect.Value{}, reflect.Value{}, err
+	}
+	return reflect.ValueOf(dimensionDomain).Index(0), reflect.ValueOf(extent), nil
+}
+
+// newWriteBatch wraps the buffers of a write query in a Batch
+func newWriteBatch(schema *ArraySchema, buffers map[string]interface{}, offsets map[string][]uint64) (*Batch, error) {
+	fields, err := schema.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	batch := Batch{
+		Fields:  make([]BatchField, 0, len(buffers)),
+		offsets: make(map[string][]uint64),
+		data:    make(map[string]interface{}),
+	}
+	for _, field := range fields {
+		buffer, ok := buffers[field.Name]
+		if !ok {
+			continue
+		}
+		if reflect.TypeOf(buffer).Kind() != reflect.Slice {
+			return nil, fmt.Errorf("Buffer of %s must be a slice", field.Name)
+		}
+
+		var numCells uint64
+		if field.IsVar() {
+			fieldOffsets, ok := offsets[field.Name]
+			if !ok {
+				return nil, fmt.Errorf("Missing offsets of variable sized field %s", field.Name)
+			}
+			numCells = uint64(len(fieldOffsets))
+			batch.offsets[field.Name] = fieldOffsets
+		} else {
+			numCells = uint64(reflect.ValueOf(buffer).Len()) / uint64(field.CellValNum)
+		}
+
+		if len(batch.Fields) == 0 {
+			batch.NumCells = numCells
+		} else if numCells != batch.NumCells {
+			return nil, fmt.Errorf("Buffer of %s holds %d cells, expected %d", field.Name, numCells, batch.NumCells)
+		}
+
+		batch.Fields = append(batch.Fields, field)
+		batch.data[field.Name] = buffer
+	}
+
+	for name := range buffers {
+		if _, ok := batch.data[name]; !ok {
+			return nil, fmt.Errorf("%s is not an attribute or dimension of the array", name)
+		}
+	}
+
+	return &batch, nil
+}
+
+/*
+SortGlobalOrder returns copies of the buffers of a write query sorted in the
+global order of a sparse array schema, ready to be written with
+TILEDB_GLOBAL_ORDER. buffers holds the data of all dimensions and
+attributes by name and offsets the offsets (in bytes) of variable sized
+ones. The input buffers are not modified.
+
+	buffers, offsets, err = SortGlobalOrder(schema, buffers, offsets)
+	err = query.SetLayout(TILEDB_GLOBAL_ORDER)
+	_, err = query.SetBuffer("rows", buffers["rows"])
+*/
+func SortGlobalOrder(schema *ArraySchema, buffers map[string]interface{}, offsets map[string][]uint64) (map[string]interface{}, map[string][]uint64, error) {
+	permutation, err := GlobalOrder(schema, buffers, offsets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batch, err := newWriteBatch(schema, buffers, offsets)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error sorting buffers: %s", err)
+	}
+
+	sorted, err := batch.Select(nil, permutation)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error sorting buffers: %s", err)
+	}
+
+	return sorted.data, sorted.offsets, nil
+}
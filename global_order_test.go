@@ -0,0 +1,73 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortGlobalOrder(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_global_order")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	schema, err := LoadArraySchema(context, tmpArrayPath)
+	assert.Nil(t, err)
+
+	// Space tiles are 2x2, cells (1,1), (1,2) and (2,1) share the first tile
+	buffers := map[string]interface{}{
+		"rows": []int32{1, 2, 1, 3, 1},
+		"cols": []int32{3, 1, 1, 3, 2},
+		"a1":   []int32{0, 1, 2, 3, 4},
+		"a2":   []byte("abbcdddde"),
+		"a3":   []int64{0, 1, 2, 3, 4},
+	}
+	offsets := map[string][]uint64{"a2": {0, 1, 3, 4, 8}}
+
+	permutation, err := GlobalOrder(schema, buffers, offsets)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{2, 4, 1, 0, 3}, permutation)
+
+	sorted, sortedOffsets, err := SortGlobalOrder(schema, buffers, offsets)
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 1, 2, 1, 3}, sorted["rows"])
+	assert.Equal(t, []int32{1, 2, 1, 3, 3}, sorted["cols"])
+	assert.Equal(t, []int32{2, 4, 1, 0, 3}, sorted["a1"])
+	assert.Equal(t, []byte("cebbadddd"), sorted["a2"])
+	assert.Equal(t, []uint64{0, 1, 2, 4, 5}, sortedOffsets["a2"])
+
+	// The input buffers are not modified
+	assert.Equal(t, []int32{0, 1, 2, 3, 4}, buffers["a1"])
+
+	// The sorted buffers can be written in global order
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	defer array.Close()
+	query, err := NewQuery(context, array)
+	assert.Nil(t, err)
+	assert.Nil(t, query.SetLayout(TILEDB_GLOBAL_ORDER))
+	for name, buffer := range sorted {
+		if name == "a2" {
+			_, _, err = query.SetBufferVar(name, sortedOffsets[name], buffer)
+		} else {
+			_, err = query.SetBuffer(name, buffer)
+		}
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, query.Submit())
+	assert.Nil(t, query.Finalize())
+
+	// Buffers must hold the same number of cells
+	buffers["a1"] = []int32{0}
+	_, err = GlobalOrder(schema, buffers, offsets)
+	assert.NotNil(t, err)
+}
@@ -1,5 +1,5 @@
 module github.com/TileDB-Inc/TileDB-Go
 
-require github.com/stretchr/testify v1.3.0
+require github.com/stretchr/testify v1.5.1
 
 go 1.13
@@ -4,12 +4,27 @@ package tiledb
 #cgo LDFLAGS: -ltiledb
 #cgo linux LDFLAGS: -ldl
 #include <tiledb/tiledb.h>
+#include <stdint.h>
 #include <stdlib.h>
+
+extern int32_t objectCallback(char* path, int32_t type, void* data);
+
+static int32_t _tiledb_object_ls(tiledb_ctx_t* ctx, const char* path, void* data) {
+	return tiledb_object_ls(ctx, path, (int32_t (*)(const char*, tiledb_object_t, void*))objectCallback, data);
+}
+
+static int32_t _tiledb_object_walk(tiledb_ctx_t* ctx, const char* path, tiledb_walk_order_t order, void* data) {
+	return tiledb_object_walk(ctx, path, order, (int32_t (*)(const char*, tiledb_object_t, void*))objectCallback, data);
+}
 */
 import "C"
 
 import (
 	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
 	"unsafe"
 )
 
@@ -25,3 +40,284 @@ func GroupCreate(context *Context, group string) error {
 	}
 	return nil
 }
+
+// GetObjectType returns the type of the tiledb object at uri, TILEDB_INVALID
+// if uri is not an array or a group
+func GetObjectType(context *Context, uri string) (ObjectType, error) {
+	curi := C.CString(uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	var objectType C.tiledb_object_t
+	ret := C.tiledb_object_type(context.tiledbContext, curi, &objectType)
+	if ret != C.TILEDB_OK {
+		return TILEDB_INVALID, fmt.Errorf("Error in getting object type of %s: %s", uri, context.LastError())
+	}
+	return ObjectType(objectType), nil
+}
+
+// GroupMember is an array or a group in a group
+type GroupMember struct {
+	URI  string
+	Type ObjectType
+}
+
+// Name returns the last element of the URI of the member
+func (m GroupMember) Name() string {
+	return path.Base(strings.TrimSuffix(m.URI, "/"))
+}
+
+/*
+Group is a tiledb group, a directory holding arrays and other groups. Groups
+organize arrays in a hierarchy:
+
+	group, err := CreateGroup(ctx, "s3://bucket/experiments/2020/run1")
+	members, err := group.Members()
+	err = group.Tree(os.Stdout)
+*/
+type Group struct {
+	context *Context
+	uri     string
+}
+
+// OpenGroup opens the existing group at uri
+func OpenGroup(context *Context, uri string) (*Group, error) {
+	objectType, err := GetObjectType(context, uri)
+	if err != nil {
+		return nil, err
+	}
+	if objectType != TILEDB_GROUP {
+		return nil, fmt.Errorf("Error in opening group %s: object is of type %s", uri, objectType)
+	}
+	return &Group{context: context, uri: uri}, nil
+}
+
+// parentURI returns the parent of a URI, "" for the root of a path or bucket
+func parentURI(uri string) string {
+	uri = strings.TrimSuffix(uri, "/")
+	i := strings.LastIndex(uri, "/")
+	if i <= 0 {
+		return ""
+	}
+	parent := uri[:i]
+	if strings.HasSuffix(parent, ":/") || strings.HasSuffix(parent, "://") {
+		// Bucket of "s3://bucket" or root of "file:///path"
+		return ""
+	}
+	return parent
+}
+
+// isRootURI returns true if uri is the root of a file system or a bucket of
+// an object store, which can not be a group
+func isRootURI(uri string) bool {
+	uri = strings.TrimSuffix(uri, "/")
+	if uri == "" {
+		return true
+	}
+	i := strings.Index(uri, "://")
+	return i >= 0 && !strings.Contains(uri[i+len("://"):], "/")
+}
+
+/*
+CreateGroup creates the group at uri and its missing parent groups, like
+mkdir -p. Existing groups are opened, an error is returned if uri or one of
+its parents is an array, or a directory which is not a group.
+*/
+func CreateGroup(context *Context, uri string) (*Group, error) {
+	config, err := context.Config()
+	if err != nil {
+		return nil, err
+	}
+	defer config.Free()
+
+	vfs, err := NewVFS(context, config)
+	if err != nil {
+		return nil, err
+	}
+	defer vfs.Free()
+
+	if isRootURI(uri) {
+		return nil, fmt.Errorf("Error in creating group %s: a root or bucket can not be a group", uri)
+	}
+
+	// Find the missing groups, from uri up to the first existing directory.
+	// Empty buckets of object stores are not directories, the walk stops
+	// below the bucket.
+	missing := make([]string, 0)
+	for current := strings.TrimSuffix(uri, "/"); current != "" && !isRootURI(current); current = parentURI(current) {
+		objectType, err := GetObjectType(context, current)
+		if err != nil {
+			return nil, err
+		}
+		if objectType == TILEDB_ARRAY {
+			return nil, fmt.Errorf("Error in creating group %s: %s is an array", uri, current)
+		}
+		if objectType == TILEDB_GROUP {
+			break
+		}
+
+		isDir, err := vfs.IsDir(current)
+		if err != nil {
+			return nil, err
+		}
+		if isDir {
+			if len(missing) == 0 {
+				return nil, fmt.Errorf("Error in creating group %s: directory is not a group", uri)
+			}
+			break
+		}
+		missing = append(missing, current)
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		err = GroupCreate(context, missing[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Group{context: context, uri: uri}, nil
+}
+
+// URI returns the URI of the group
+func (g *Group) URI() string {
+	return g.uri
+}
+
+// Members returns the arrays and groups directly in the group, sorted by
+// URI
+func (g *Group) Members() ([]GroupMember, error) {
+	members := make([]GroupMember, 0)
+	data := registerCallback(func(path string, objectType ObjectType) bool {
+		members = append(members, GroupMember{URI: path, Type: objectType})
+		return true
+	})
+	defer unregisterCallback(data)
+
+	curi := C.CString(g.uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	ret := C._tiledb_object_ls(g.context.tiledbContext, curi, data)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("Error in listing group %s: %s", g.uri, g.context.LastError())
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].URI < members[j].URI
+	})
+	return members, nil
+}
+
+// Arrays returns the URIs of the arrays directly in the group, sorted
+func (g *Group) Arrays() ([]string, error) {
+	members, err := g.Members()
+	if err != nil {
+		return nil, err
+	}
+
+	arrays := make([]string, 0)
+	for _, member := range members {
+		if member.Type == TILEDB_ARRAY {
+			arrays = append(arrays, member.URI)
+		}
+	}
+	return arrays, nil
+}
+
+// Groups returns the groups directly in the group, sorted by URI
+func (g *Group) Groups() ([]*Group, error) {
+	members, err := g.Members()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]*Group, 0)
+	for _, member := range members {
+		if member.Type == TILEDB_GROUP {
+			groups = append(groups, &Group{context: g.context, uri: member.URI})
+		}
+	}
+	return groups, nil
+}
+
+// Walk calls fn for every array and group in the group, recursively, in the
+// given order. Walking stops at the first error returned by fn.
+func (g *Group) Walk(order WalkOrder, fn func(member GroupMember) error) error {
+	var fnErr error
+	data := registerCallback(func(path string, objectType ObjectType) bool {
+		fnErr = fn(GroupMember{URI: path, Type: objectType})
+		return fnErr == nil
+	})
+	defer unregisterCallback(data)
+
+	curi := C.CString(g.uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	ret := C._tiledb_object_walk(g.context.tiledbContext, curi, C.tiledb_walk_order_t(order), data)
+	if fnErr != nil {
+		return fnErr
+	}
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("Error in walking group %s: %s", g.uri, g.context.LastError())
+	}
+	return nil
+}
+
+// Remove deletes the group with all its arrays and groups
+func (g *Group) Remove() error {
+	curi := C.CString(g.uri)
+	defer C.free(unsafe.Pointer(curi))
+
+	ret := C.tiledb_object_remove(g.context.tiledbContext, curi)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("Error in removing group %s: %s", g.uri, g.context.LastError())
+	}
+	return nil
+}
+
+/*
+Tree writes the hierarchy of the group, groups having a trailing slash:
+
+	experiments/
+	├── 2020/
+	│   └── run1
+	└── baseline
+*/
+func (g *Group) Tree(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s/\n", path.Base(strings.TrimSuffix(g.uri, "/")))
+	if err != nil {
+		return err
+	}
+	return g.tree(w, "")
+}
+
+// tree writes the members of the group, each line starting with prefix
+func (g *Group) tree(w io.Writer, prefix string) error {
+	members, err := g.Members()
+	if err != nil {
+		return err
+	}
+
+	for i, member := range members {
+		branch, indent := "├── ", "│   "
+		if i == len(members)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		name := member.Name()
+		if member.Type == TILEDB_GROUP {
+			name += "/"
+		}
+		_, err = fmt.Fprintf(w, "%s%s%s\n", prefix, branch, name)
+		if err != nil {
+			return err
+		}
+
+		if member.Type == TILEDB_GROUP {
+			group := Group{context: g.context, uri: member.URI}
+			err = group.tree(w, prefix+indent)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
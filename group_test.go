@@ -1,9 +1,12 @@
 package tiledb
 
 import (
-	"github.com/stretchr/testify/assert"
+	"bytes"
 	"os"
+	"path"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGroupCreate(t *testing.T) {
@@ -29,6 +32,78 @@ func TestGroupCreate(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestGroup(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpGroup := path.Join(os.TempDir(), "tiledb_test_group_hierarchy")
+	defer os.RemoveAll(tmpGroup)
+	if _, err = os.Stat(tmpGroup); err == nil {
+		os.RemoveAll(tmpGroup)
+	}
+
+	// Parent groups are created, existing groups are opened
+	run, err := CreateGroup(context, path.Join(tmpGroup, "2020", "run1"))
+	assert.Nil(t, err)
+	_, err = CreateGroup(context, path.Join(tmpGroup, "2020", "run1"))
+	assert.Nil(t, err)
+	createBatchTestArray(t, context, path.Join(tmpGroup, "baseline"))
+	createBatchTestArray(t, context, path.Join(run.URI(), "samples"))
+
+	objectType, err := GetObjectType(context, tmpGroup)
+	assert.Nil(t, err)
+	assert.Equal(t, TILEDB_GROUP, objectType)
+	objectType, err = GetObjectType(context, path.Join(tmpGroup, "baseline"))
+	assert.Nil(t, err)
+	assert.Equal(t, TILEDB_ARRAY, objectType)
+
+	// Arrays and plain directories are not groups
+	_, err = OpenGroup(context, path.Join(tmpGroup, "baseline"))
+	assert.NotNil(t, err)
+	_, err = CreateGroup(context, path.Join(tmpGroup, "baseline", "sub"))
+	assert.NotNil(t, err)
+	_, err = CreateGroup(context, os.TempDir())
+	assert.NotNil(t, err)
+
+	group, err := OpenGroup(context, tmpGroup)
+	assert.Nil(t, err)
+
+	members, err := group.Members()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(members))
+	assert.Equal(t, "2020", members[0].Name())
+	assert.Equal(t, TILEDB_GROUP, members[0].Type)
+	assert.Equal(t, "baseline", members[1].Name())
+	assert.Equal(t, TILEDB_ARRAY, members[1].Type)
+
+	arrays, err := group.Arrays()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(arrays))
+	groups, err := group.Groups()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(groups))
+
+	names := make([]string, 0)
+	assert.Nil(t, group.Walk(TILEDB_PREORDER, func(member GroupMember) error {
+		names = append(names, member.Name())
+		return nil
+	}))
+	assert.ElementsMatch(t, []string{"2020", "run1", "samples", "baseline"}, names)
+
+	var tree bytes.Buffer
+	assert.Nil(t, group.Tree(&tree))
+	assert.Equal(t, "tiledb_test_group_hierarchy/\n"+
+		"├── 2020/\n"+
+		"│   └── run1/\n"+
+		"│       └── samples\n"+
+		"└── baseline\n", tree.String())
+
+	assert.Nil(t, groups[0].Remove())
+	members, err = group.Members()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(members))
+}
+
 func ExampleGroupCreate() {
 	// Create context without config
 	context, err := NewContext(nil)
@@ -44,3 +119,18 @@ func ExampleGroupCreate() {
 		return
 	}
 }
+
+func TestCreateGroupRoot(t *testing.T) {
+	assert.True(t, isRootURI("s3://bucket"))
+	assert.True(t, isRootURI("s3://bucket/"))
+	assert.True(t, isRootURI("file:///"))
+	assert.True(t, isRootURI("/"))
+	assert.False(t, isRootURI("s3://bucket/group"))
+	assert.False(t, isRootURI("file:///group"))
+	assert.False(t, isRootURI("group"))
+
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+	_, err = CreateGroup(context, "s3://bucket")
+	assert.NotNil(t, err)
+}
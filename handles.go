@@ -0,0 +1,172 @@
+package tiledb
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// HandleDebugEnv is the environment variable enabling the tracking of c
+// handles when set to 1. Building with the tiledb_debug tag enables it too.
+const HandleDebugEnv = "TILEDB_GO_DEBUG_HANDLES"
+
+// maxHandleStackDepth is the number of frames recorded per allocation
+const maxHandleStackDepth = 32
+
+// handleRecord is a live c handle
+type handleRecord struct {
+	kind  string
+	stack []uintptr
+}
+
+// handles holds the live c handles by address while tracking is enabled
+var handles = struct {
+	sync.Mutex
+	enabled int32
+	live    map[unsafe.Pointer]handleRecord
+}{live: make(map[unsafe.Pointer]handleRecord)}
+
+func init() {
+	if handleDebugBuild || os.Getenv(HandleDebugEnv) == "1" {
+		handles.enabled = 1
+	}
+}
+
+// EnableHandleTracking turns the tracking of c handles on or off. Only the
+// handles allocated while tracking is on are reported.
+func EnableHandleTracking(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&handles.enabled, 1)
+	} else {
+		atomic.StoreInt32(&handles.enabled, 0)
+	}
+}
+
+// HandleTrackingEnabled returns true if c handles are tracked
+func HandleTrackingEnabled() bool {
+	return atomic.LoadInt32(&handles.enabled) == 1
+}
+
+// trackHandle records a c handle of type kind (e.g. "tiledb_array_t") and
+// the stack allocating it
+func trackHandle(kind string, handle unsafe.Pointer) {
+	if handle == nil || !HandleTrackingEnabled() {
+		return
+	}
+
+	stack := make([]uintptr, maxHandleStackDepth)
+	// Skip runtime.Callers and trackHandle
+	stack = stack[:runtime.Callers(2, stack)]
+
+	handles.Lock()
+	defer handles.Unlock()
+	handles.live[handle] = handleRecord{kind: kind, stack: stack}
+}
+
+// untrackHandle forgets a c handle about to be freed
+func untrackHandle(handle unsafe.Pointer) {
+	if handle == nil {
+		return
+	}
+
+	handles.Lock()
+	defer handles.Unlock()
+	delete(handles.live, handle)
+}
+
+// LiveHandles returns the number of tracked c handles not freed yet, by c
+// type
+func LiveHandles() map[string]int {
+	handles.Lock()
+	defer handles.Unlock()
+
+	counts := make(map[string]int)
+	for _, record := range handles.live {
+		counts[record.kind]++
+	}
+	return counts
+}
+
+// LeakedHandle is a tracked c handle not freed yet
+type LeakedHandle struct {
+	// Type is the c type of the handle, e.g. "tiledb_array_t"
+	Type string
+	// Stack is the stack trace of the allocation of the handle
+	Stack string
+}
+
+// formatStack formats the frames of a stack, one "function\n\tfile:line"
+// per frame
+func formatStack(stack []uintptr) string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// LeakedHandles returns the tracked c handles not freed yet with the stack
+// trace of their allocation, sorted by type and stack
+func LeakedHandles() []LeakedHandle {
+	handles.Lock()
+	records := make([]handleRecord, 0, len(handles.live))
+	for _, record := range handles.live {
+		records = append(records, record)
+	}
+	handles.Unlock()
+
+	leaked := make([]LeakedHandle, len(records))
+	for i, record := range records {
+		leaked[i] = LeakedHandle{Type: record.kind, Stack: formatStack(record.stack)}
+	}
+	sort.Slice(leaked, func(i, j int) bool {
+		if leaked[i].Type != leaked[j].Type {
+			return leaked[i].Type < leaked[j].Type
+		}
+		return leaked[i].Stack < leaked[j].Stack
+	})
+	return leaked
+}
+
+/*
+CheckHandles returns an error listing the tracked c handles not freed yet
+with the stack trace of their allocation, nil if there are none. Tests can
+assert that they free everything they allocate:
+
+	func TestMain(m *testing.M) {
+		tiledb.EnableHandleTracking(true)
+		code := m.Run()
+		runtime.GC()
+		if err := tiledb.CheckHandles(); err != nil {
+			fmt.Println(err)
+			code = 1
+		}
+		os.Exit(code)
+	}
+
+Handles freed by finalizers are only released once the garbage collector has
+run them.
+*/
+func CheckHandles() error {
+	leaked := LeakedHandles()
+	if len(leaked) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d leaked tiledb handles:", len(leaked))
+	for _, handle := range leaked {
+		fmt.Fprintf(&b, "\n%s allocated at:\n%s", handle.Type, handle.Stack)
+	}
+	return fmt.Errorf("%s", b.String())
+}
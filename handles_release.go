@@ -0,0 +1,8 @@
+//go:build !tiledb_debug
+// +build !tiledb_debug
+
+package tiledb
+
+// handleDebugBuild enables the tracking of c handles in builds with the
+// tiledb_debug tag
+const handleDebugBuild = false
@@ -0,0 +1,94 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleTracking(t *testing.T) {
+	enabled := HandleTrackingEnabled()
+	EnableHandleTracking(true)
+	defer EnableHandleTracking(enabled)
+
+	// Other tests may leave handles behind, only count the new ones
+	before := LiveHandles()
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+	config, err := context.Config()
+	assert.Nil(t, err)
+	arraySchema, err := NewArraySchema(context, TILEDB_DENSE)
+	assert.Nil(t, err)
+	attribute, err := NewAttribute(context, "a1", TILEDB_INT32)
+	assert.Nil(t, err)
+
+	live := LiveHandles()
+	for _, kind := range []string{"tiledb_ctx_t", "tiledb_config_t", "tiledb_array_schema_t", "tiledb_attribute_t"} {
+		assert.Equal(t, before[kind]+1, live[kind], kind)
+	}
+
+	// Leaks are reported with the stack of their allocation
+	attribute.Free()
+	arraySchema.Free()
+	config.Free()
+	err = CheckHandles()
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "tiledb_ctx_t allocated at"))
+	assert.True(t, strings.Contains(err.Error(), "TestHandleTracking"))
+	leaked := 0
+	for _, handle := range LeakedHandles() {
+		if strings.Contains(handle.Stack, "TestHandleTracking") {
+			assert.Equal(t, "tiledb_ctx_t", handle.Type)
+			leaked++
+		}
+	}
+	assert.Equal(t, 1, leaked)
+
+	context.Free()
+	assert.Equal(t, before, LiveHandles())
+}
+
+func TestCopyArrayFreesHandles(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+	defer context.Free()
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_handles")
+	tmpCopyPath := path.Join(os.TempDir(), "tiledb_test_handles_copy")
+	for _, p := range []string{tmpArrayPath, tmpCopyPath} {
+		defer os.RemoveAll(p)
+		if _, err = os.Stat(p); err == nil {
+			os.RemoveAll(p)
+		}
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	enabled := HandleTrackingEnabled()
+	before := LiveHandles()
+	EnableHandleTracking(true)
+	defer EnableHandleTracking(enabled)
+
+	// Rewriting reads batches, writes fragments and copies the metadata
+	key := "0123456789abcdeF0123456789abcdeF"
+	err = CopyArray(context, tmpArrayPath, tmpCopyPath, &CopyOptions{
+		EncryptionType: TILEDB_AES_256_GCM,
+		Key:            key,
+		BatchSize:      1,
+	})
+	assert.Nil(t, err)
+
+	array, err := NewArray(context, tmpCopyPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.OpenWithKey(TILEDB_READ, TILEDB_AES_256_GCM, key))
+	a1, _ := readCopyTestArray(t, context, array)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	array.Free()
+
+	assert.Equal(t, before, LiveHandles())
+	if !enabled {
+		assert.Nil(t, CheckHandles())
+	}
+}
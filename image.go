@@ -0,0 +1,291 @@
+package tiledb
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// ImageAttribute is the name of the attribute holding the pixel values of
+// arrays created by NewImageArraySchema
+const ImageAttribute = "value"
+
+// imageTileExtent is the maximum tile extent of the rows and cols dimensions
+// of image arrays
+const imageTileExtent = 256
+
+/*
+NewImageArraySchema creates the schema of a dense array storing an image of
+height x width pixels. Images with a single band have two dimensions, rows
+and cols, images with 3 (RGB) or 4 (RGBA) bands have a third dimension band.
+Pixel values are stored in ImageAttribute with datatype TILEDB_UINT8, or
+TILEDB_UINT16 for single band 16 bit images.
+*/
+func NewImageArraySchema(context *Context, height, width, bands uint64, datatype Datatype) (*ArraySchema, error) {
+	if height == 0 || width == 0 {
+		return nil, fmt.Errorf("Image must have at least one pixel, got %dx%d", width, height)
+	}
+
+	switch {
+	case datatype == TILEDB_UINT8 && (bands == 1 || bands == 3 || bands == 4):
+	case datatype == TILEDB_UINT16 && bands == 1:
+	default:
+		return nil, fmt.Errorf("Unsupported image format with %d bands of %s", bands, datatype)
+	}
+
+	extent := func(size uint64) uint64 {
+		if size < imageTileExtent {
+			return size
+		}
+		return imageTileExtent
+	}
+
+	rows, err := NewDimension(context, "rows", []uint64{0, height - 1}, extent(height))
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := NewDimension(context, "cols", []uint64{0, width - 1}, extent(width))
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := NewDomain(context)
+	if err != nil {
+		return nil, err
+	}
+
+	err = domain.AddDimensions(rows, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	if bands > 1 {
+		band, err := NewDimension(context, "band", []uint64{0, bands - 1}, bands)
+		if err != nil {
+			return nil, err
+		}
+
+		err = domain.AddDimensions(band)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schema, err := NewArraySchema(context, TILEDB_DENSE)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.SetDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.SetCellOrder(TILEDB_ROW_MAJOR)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.SetTileOrder(TILEDB_ROW_MAJOR)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute, err := NewAttribute(context, ImageAttribute, datatype)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.AddAttributes(attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// imageFormat returns the number of bands and the pixel datatype of an image
+// array
+func imageFormat(array *Array) (uint64, Datatype, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer schema.Free()
+
+	attribute, err := schema.AttributeFromName(ImageAttribute)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer attribute.Free()
+
+	datatype, err := attribute.Type()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bands := uint64(1)
+	if nDim == 3 {
+		band, err := domain.DimensionFromName("band")
+		if err != nil {
+			return 0, 0, err
+		}
+		defer band.Free()
+
+		bandDomain, err := band.Domain()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		bandBounds, ok := bandDomain.([]uint64)
+		if !ok {
+			return 0, 0, fmt.Errorf("Dimension band of an image array must be of type uint64")
+		}
+		bands = bandBounds[1] + 1
+	} else if nDim != 2 {
+		return 0, 0, fmt.Errorf("Image arrays must have 2 or 3 dimensions, array has %d", nDim)
+	}
+
+	return bands, datatype, nil
+}
+
+// imageSubarray returns the subarray covering a rectangle of pixels and all
+// bands
+func imageSubarray(rect image.Rectangle, bands uint64) ([]uint64, error) {
+	if rect.Empty() || rect.Min.X < 0 || rect.Min.Y < 0 {
+		return nil, fmt.Errorf("Invalid image rectangle %v", rect)
+	}
+
+	subarray := []uint64{
+		uint64(rect.Min.Y), uint64(rect.Max.Y - 1),
+		uint64(rect.Min.X), uint64(rect.Max.X - 1),
+	}
+	if bands > 1 {
+		subarray = append(subarray, 0, bands-1)
+	}
+	return subarray, nil
+}
+
+/*
+WriteImage writes an image to an array created with NewImageArraySchema,
+with the top left pixel of the image at origin (x = cols, y = rows). The
+image is converted to the format of the array, e.g. a color image written
+to a single band array is stored in grayscale. Color values are stored
+without premultiplied alpha. The array must be opened in WRITE mode.
+*/
+func WriteImage(context *Context, array *Array, img image.Image, origin image.Point) error {
+	bands, datatype, err := imageFormat(array)
+	if err != nil {
+		return err
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	rect := image.Rect(0, 0, width, height)
+
+	subarray, err := imageSubarray(rect.Add(origin), bands)
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	switch {
+	case datatype == TILEDB_UINT16:
+		gray := image.NewGray16(rect)
+		draw.Draw(gray, rect, img, bounds.Min, draw.Src)
+		pixels := make([]uint16, width*height)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				pixels[y*width+x] = gray.Gray16At(x, y).Y
+			}
+		}
+		data = pixels
+	case bands == 1:
+		gray := image.NewGray(rect)
+		draw.Draw(gray, rect, img, bounds.Min, draw.Src)
+		data = gray.Pix
+	case bands == 3:
+		nrgba := image.NewNRGBA(rect)
+		draw.Draw(nrgba, rect, img, bounds.Min, draw.Src)
+		pixels := make([]uint8, 0, width*height*3)
+		for i := 0; i < len(nrgba.Pix); i += 4 {
+			pixels = append(pixels, nrgba.Pix[i:i+3]...)
+		}
+		data = pixels
+	default:
+		nrgba := image.NewNRGBA(rect)
+		draw.Draw(nrgba, rect, img, bounds.Min, draw.Src)
+		data = nrgba.Pix
+	}
+
+	shape := []uint64{uint64(height), uint64(width)}
+	if bands > 1 {
+		shape = append(shape, bands)
+	}
+
+	ndArray, err := NewNDArray(data, shape, TILEDB_ROW_MAJOR)
+	if err != nil {
+		return err
+	}
+
+	return WriteNDArray(context, array, ImageAttribute, subarray, ndArray)
+}
+
+/*
+ReadImage reads a window of an array created with NewImageArraySchema as an
+image with bounds rect (x = cols, y = rows). Single band arrays are returned
+as *image.Gray or *image.Gray16, color arrays as *image.NRGBA (RGB arrays
+are opaque). The array must be opened in READ mode.
+*/
+func ReadImage(context *Context, array *Array, rect image.Rectangle) (image.Image, error) {
+	bands, datatype, err := imageFormat(array)
+	if err != nil {
+		return nil, err
+	}
+
+	subarray, err := imageSubarray(rect, bands)
+	if err != nil {
+		return nil, err
+	}
+
+	ndArray, err := ReadNDArray(context, array, ImageAttribute, subarray, TILEDB_ROW_MAJOR)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := rect.Dx(), rect.Dy()
+	switch {
+	case datatype == TILEDB_UINT16:
+		pixels := ndArray.Data.([]uint16)
+		gray := image.NewGray16(rect)
+		for i, value := range pixels {
+			gray.Pix[2*i] = uint8(value >> 8)
+			gray.Pix[2*i+1] = uint8(value)
+		}
+		return gray, nil
+	case bands == 1:
+		return &image.Gray{Pix: ndArray.Data.([]uint8), Stride: width, Rect: rect}, nil
+	case bands == 3:
+		pixels := ndArray.Data.([]uint8)
+		nrgba := image.NewNRGBA(rect)
+		for i := 0; i < width*height; i++ {
+			copy(nrgba.Pix[4*i:4*i+3], pixels[3*i:3*i+3])
+			nrgba.Pix[4*i+3] = 0xff
+		}
+		return nrgba, nil
+	default:
+		return &image.NRGBA{Pix: ndArray.Data.([]uint8), Stride: 4 * width, Rect: rect}, nil
+	}
+}
@@ -0,0 +1,146 @@
+package tiledb
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageRGBA(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_image_rgba")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	_, err = NewImageArraySchema(context, 8, 8, 2, TILEDB_UINT8)
+	assert.NotNil(t, err)
+
+	arraySchema, err := NewImageArraySchema(context, 8, 8, 4, TILEDB_UINT8)
+	assert.Nil(t, err)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 0xff, A: 0xff})
+		}
+	}
+	// Semi-transparent pixels keep their color
+	img.SetNRGBA(0, 0, color.NRGBA{R: 0xc0, G: 0x40, B: 0x20, A: 0x80})
+
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, WriteImage(context, array, img, image.Pt(2, 1)))
+	assert.Nil(t, array.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	window := image.Rect(3, 2, 6, 4)
+	result, err := ReadImage(context, array, window)
+	assert.Nil(t, err)
+	assert.Equal(t, window, result.Bounds())
+
+	nrgba, ok := result.(*image.NRGBA)
+	assert.True(t, ok)
+	// Pixel (3, 2) of the array is pixel (1, 1) of the image
+	assert.Equal(t, color.NRGBA{R: 1, G: 1, B: 0xff, A: 0xff}, nrgba.NRGBAAt(3, 2))
+	assert.Equal(t, color.NRGBA{R: 3, G: 2, B: 0xff, A: 0xff}, nrgba.NRGBAAt(5, 3))
+
+	result, err = ReadImage(context, array, image.Rect(2, 1, 3, 2))
+	assert.Nil(t, err)
+	assert.Equal(t, color.NRGBA{R: 0xc0, G: 0x40, B: 0x20, A: 0x80}, result.(*image.NRGBA).NRGBAAt(2, 1))
+
+	var buffer bytes.Buffer
+	assert.Nil(t, png.Encode(&buffer, result))
+	decoded, err := png.Decode(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, decoded.Bounds().Dx())
+
+	_, err = ReadImage(context, array, image.Rect(-1, 0, 2, 2))
+	assert.NotNil(t, err)
+}
+
+func TestImageRGB(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_image_rgb")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	arraySchema, err := NewImageArraySchema(context, 2, 2, 3, TILEDB_UINT8)
+	assert.Nil(t, err)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.SetNRGBA(0, 0, color.NRGBA{R: 0xc0, G: 0x40, B: 0x20, A: 0x80})
+	img.SetNRGBA(1, 1, color.NRGBA{R: 1, G: 2, B: 3, A: 0xff})
+
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, WriteImage(context, array, img, image.Pt(0, 0)))
+	assert.Nil(t, array.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	result, err := ReadImage(context, array, image.Rect(0, 0, 2, 2))
+	assert.Nil(t, err)
+	nrgba, ok := result.(*image.NRGBA)
+	assert.True(t, ok)
+	// The color of semi-transparent pixels is stored without alpha
+	assert.Equal(t, color.NRGBA{R: 0xc0, G: 0x40, B: 0x20, A: 0xff}, nrgba.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{R: 1, G: 2, B: 3, A: 0xff}, nrgba.NRGBAAt(1, 1))
+}
+
+func TestImageGray16(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_image_gray16")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	arraySchema, err := NewImageArraySchema(context, 2, 2, 1, TILEDB_UINT16)
+	assert.Nil(t, err)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+
+	img := image.NewGray16(image.Rect(0, 0, 2, 2))
+	img.SetGray16(0, 0, color.Gray16{Y: 0})
+	img.SetGray16(1, 0, color.Gray16{Y: 1000})
+	img.SetGray16(0, 1, color.Gray16{Y: 40000})
+	img.SetGray16(1, 1, color.Gray16{Y: 65535})
+
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, WriteImage(context, array, img, image.Pt(0, 0)))
+	assert.Nil(t, array.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	result, err := ReadImage(context, array, image.Rect(0, 0, 2, 2))
+	assert.Nil(t, err)
+	assert.Equal(t, img, result)
+}
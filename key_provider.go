@@ -0,0 +1,153 @@
+package tiledb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+/*
+KeyProvider supplies the encryption keys of arrays. Once set on a context
+with SetKeyProvider, Array.Open, Array.OpenAt, Array.Create,
+Array.Consolidate, Array.ConsolidateMetadata and LoadArraySchema use the key
+returned for the URI of the array, so keys do not have to be passed around:
+
+	ctx.SetKeyProvider(StaticKeyProvider{
+		"s3://bucket/secure": key,
+	})
+	array, err := NewArray(ctx, "s3://bucket/secure")
+	err = array.Open(TILEDB_READ)
+*/
+type KeyProvider interface {
+	// Key returns the encryption type and key of the array at uri, an empty
+	// key if the array is not encrypted
+	Key(uri string) (EncryptionType, string, error)
+}
+
+// KeyProviderFunc adapts a function to a KeyProvider
+type KeyProviderFunc func(uri string) (EncryptionType, string, error)
+
+// Key calls f(uri)
+func (f KeyProviderFunc) Key(uri string) (EncryptionType, string, error) {
+	return f(uri)
+}
+
+// matchURIPrefix returns true if uri is one of the prefixes or under one of
+// them. Trailing slashes are ignored.
+func matchURIPrefix(uri string, prefixes []string) bool {
+	uri = strings.TrimSuffix(uri, "/")
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if uri == prefix || strings.HasPrefix(uri, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvKeyProvider returns the AES-256-GCM key stored in an environment
+// variable for the arrays under one of Prefixes (e.g. "s3://bucket/secure"),
+// no key for other arrays or if the variable is not set
+type EnvKeyProvider struct {
+	Variable string
+	Prefixes []string
+}
+
+// Key returns the value of the environment variable
+func (p EnvKeyProvider) Key(uri string) (EncryptionType, string, error) {
+	if !matchURIPrefix(uri, p.Prefixes) {
+		return TILEDB_NO_ENCRYPTION, "", nil
+	}
+	key, ok := os.LookupEnv(p.Variable)
+	if !ok || key == "" {
+		return TILEDB_NO_ENCRYPTION, "", nil
+	}
+	return TILEDB_AES_256_GCM, key, nil
+}
+
+// FileKeyProvider returns the AES-256-GCM key stored in a file for the arrays
+// under one of Prefixes, no key for other arrays. The file is read on every
+// call, so the key can be rotated without restarting, and surrounding
+// whitespace is ignored.
+type FileKeyProvider struct {
+	Path     string
+	Prefixes []string
+}
+
+// Key reads the key from the file
+func (p FileKeyProvider) Key(uri string) (EncryptionType, string, error) {
+	if !matchURIPrefix(uri, p.Prefixes) {
+		return TILEDB_NO_ENCRYPTION, "", nil
+	}
+	content, err := ioutil.ReadFile(p.Path)
+	if err != nil {
+		return TILEDB_NO_ENCRYPTION, "", fmt.Errorf("Error reading encryption key: %s", err)
+	}
+	key := strings.TrimSpace(string(content))
+	if key == "" {
+		return TILEDB_NO_ENCRYPTION, "", fmt.Errorf("Error reading encryption key: %s is empty", p.Path)
+	}
+	return TILEDB_AES_256_GCM, key, nil
+}
+
+// StaticKeyProvider maps array URIs to their AES-256-GCM key. Arrays not in
+// the map are not encrypted. Trailing slashes of URIs are ignored.
+type StaticKeyProvider map[string]string
+
+// Key returns the key of the array at uri
+func (p StaticKeyProvider) Key(uri string) (EncryptionType, string, error) {
+	uri = strings.TrimSuffix(uri, "/")
+	for arrayURI, key := range p {
+		if strings.TrimSuffix(arrayURI, "/") == uri {
+			return TILEDB_AES_256_GCM, key, nil
+		}
+	}
+	return TILEDB_NO_ENCRYPTION, "", nil
+}
+
+// SetKeyProvider sets the provider of the encryption keys of the arrays
+// opened, created, consolidated or whose schema is loaded with the context.
+// A nil provider disables it.
+func (c *Context) SetKeyProvider(provider KeyProvider) {
+	c.keyProvider = provider
+}
+
+// KeyProvider returns the key provider of the context, nil if not set
+func (c *Context) KeyProvider() KeyProvider {
+	return c.keyProvider
+}
+
+// encryptionKey returns the encryption key of the array at uri from the key
+// provider, an empty key if the context has no key provider
+func (c *Context) encryptionKey(uri string) (EncryptionType, string, error) {
+	if c.keyProvider == nil {
+		return TILEDB_NO_ENCRYPTION, "", nil
+	}
+	return c.keyProvider.Key(uri)
+}
+
+/*
+RotateKey re-encrypts the AES-256-GCM array at uri with a new key. The core
+can not change the key of an array, so the cells and metadata are rewritten
+into a new array encrypted with newKey, which replaces the old one. As with
+CopyArray, the fragments are rewritten and earlier versions of the array can
+no longer be opened with OpenAt.
+
+Key providers return the old key until they are updated, RotateKey only uses
+the keys it is given.
+*/
+func RotateKey(ctx *Context, uri string, oldKey string, newKey string) error {
+	err := replaceArray(ctx, uri, "", func(tmpURI string) error {
+		return rewriteArray(ctx, uri, tmpURI, CopyOptions{
+			SourceEncryptionType: TILEDB_AES_256_GCM,
+			SourceKey:            oldKey,
+			EncryptionType:       TILEDB_AES_256_GCM,
+			Key:                  newKey,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("Error rotating the key of array %s: %s", uri, err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package tiledb
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyProviders(t *testing.T) {
+	key := "0123456789abcdeF0123456789abcdeF"
+
+	static := StaticKeyProvider{"s3://bucket/secure/": key}
+	encryptionType, providedKey, err := static.Key("s3://bucket/secure")
+	assert.Nil(t, err)
+	assert.Equal(t, TILEDB_AES_256_GCM, encryptionType)
+	assert.Equal(t, key, providedKey)
+	encryptionType, providedKey, err = static.Key("s3://bucket/public")
+	assert.Nil(t, err)
+	assert.Equal(t, TILEDB_NO_ENCRYPTION, encryptionType)
+	assert.Equal(t, "", providedKey)
+
+	env := EnvKeyProvider{Variable: "TILEDB_TEST_ENCRYPTION_KEY", Prefixes: []string{"s3://bucket/secure/"}}
+	os.Unsetenv(env.Variable)
+	_, providedKey, err = env.Key("s3://bucket/secure/array")
+	assert.Nil(t, err)
+	assert.Equal(t, "", providedKey)
+	os.Setenv(env.Variable, key)
+	defer os.Unsetenv(env.Variable)
+	encryptionType, providedKey, err = env.Key("s3://bucket/secure/array")
+	assert.Nil(t, err)
+	assert.Equal(t, TILEDB_AES_256_GCM, encryptionType)
+	assert.Equal(t, key, providedKey)
+	// Arrays out of the prefixes have no key
+	for _, uri := range []string{"s3://bucket/public/array", "s3://bucket/secure2", "array"} {
+		encryptionType, providedKey, err = env.Key(uri)
+		assert.Nil(t, err)
+		assert.Equal(t, TILEDB_NO_ENCRYPTION, encryptionType)
+		assert.Equal(t, "", providedKey)
+	}
+
+	tmpKeyPath := path.Join(os.TempDir(), "tiledb_test_key_provider_key")
+	defer os.Remove(tmpKeyPath)
+	file := FileKeyProvider{Path: tmpKeyPath, Prefixes: []string{"s3://bucket/secure"}}
+	os.Remove(tmpKeyPath)
+	_, _, err = file.Key("s3://bucket/secure")
+	assert.NotNil(t, err)
+	assert.Nil(t, ioutil.WriteFile(tmpKeyPath, []byte(key+"\n"), 0600))
+	encryptionType, providedKey, err = file.Key("s3://bucket/secure")
+	assert.Nil(t, err)
+	assert.Equal(t, TILEDB_AES_256_GCM, encryptionType)
+	assert.Equal(t, key, providedKey)
+	_, providedKey, err = file.Key("s3://bucket/public/array")
+	assert.Nil(t, err)
+	assert.Equal(t, "", providedKey)
+}
+
+func TestKeyProviderUnencryptedArray(t *testing.T) {
+	key := "0123456789abcdeF0123456789abcdeF"
+
+	tmpDir := path.Join(os.TempDir(), "tiledb_test_key_provider_prefix")
+	defer os.RemoveAll(tmpDir)
+	os.RemoveAll(tmpDir)
+	assert.Nil(t, os.MkdirAll(tmpDir, 0755))
+	securePath := path.Join(tmpDir, "secure")
+	publicPath := path.Join(tmpDir, "public")
+
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+	createBatchTestArray(t, context, publicPath)
+
+	// The provider only encrypts the arrays under its prefix
+	env := EnvKeyProvider{Variable: "TILEDB_TEST_ENCRYPTION_KEY", Prefixes: []string{securePath}}
+	os.Setenv(env.Variable, key)
+	defer os.Unsetenv(env.Variable)
+	context.SetKeyProvider(env)
+	createBatchTestArray(t, context, securePath)
+
+	array, err := NewArray(context, publicPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	a1, _ := readCopyTestArray(t, context, array)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Nil(t, array.Close())
+
+	array, err = NewArray(context, securePath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	assert.Nil(t, array.Close())
+
+	plainContext, err := NewContext(nil)
+	assert.Nil(t, err)
+	_, err = LoadArraySchema(plainContext, securePath)
+	assert.NotNil(t, err)
+}
+
+func TestKeyProviderArray(t *testing.T) {
+	key := "0123456789abcdeF0123456789abcdeF"
+	newKey := "FEDCBA9876543210FEDCBA9876543210"
+
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, context.KeyProvider())
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_key_provider")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	// Create encrypts the array with the provided key
+	context.SetKeyProvider(StaticKeyProvider{tmpArrayPath: key})
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	plainContext, err := NewContext(nil)
+	assert.Nil(t, err)
+	array, err := NewArray(plainContext, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.NotNil(t, array.Open(TILEDB_READ))
+	_, err = LoadArraySchema(plainContext, tmpArrayPath)
+	assert.NotNil(t, err)
+
+	array, err = NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	a1, _ := readCopyTestArray(t, context, array)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Nil(t, array.Close())
+	_, err = LoadArraySchema(context, tmpArrayPath)
+	assert.Nil(t, err)
+
+	// Rotating the key rewrites the array
+	assert.Nil(t, RotateKey(context, tmpArrayPath, key, newKey))
+	array, err = NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.NotNil(t, array.Open(TILEDB_READ))
+
+	context.SetKeyProvider(StaticKeyProvider{tmpArrayPath: newKey})
+	assert.Nil(t, array.Open(TILEDB_READ))
+	a1, _ = readCopyTestArray(t, context, array)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Nil(t, array.Close())
+}
@@ -0,0 +1,160 @@
+package tiledb
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metadataFileTimestamp parses the timestamp of a metadata file named
+// __<t1>_<t2>_<uuid>, or __<uuid>_<t> in older formats
+func metadataFileTimestamp(uri string) (uint64, bool) {
+	name := path.Base(strings.TrimSuffix(uri, "/"))
+	if !strings.HasPrefix(name, "__") || strings.HasSuffix(name, ".vac") {
+		return 0, false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(name, "__"), "_")
+	if len(parts) >= 3 {
+		if _, err := strconv.ParseUint(parts[0], 10, 64); err == nil {
+			if timestamp, err := strconv.ParseUint(parts[1], 10, 64); err == nil {
+				return timestamp, true
+			}
+		}
+	}
+	timestamp, err := strconv.ParseUint(parts[len(parts)-1], 10, 64)
+	return timestamp, err == nil
+}
+
+// MetadataTimestamps returns the distinct timestamps at which the metadata
+// of the array was written, in increasing order. They are read from the
+// metadata files of the array, consolidating metadata merges them.
+func (a *Array) MetadataTimestamps() ([]time.Time, error) {
+	config, err := a.context.Config()
+	if err != nil {
+		return nil, err
+	}
+	defer config.Free()
+
+	vfs, err := NewVFS(a.context, config)
+	if err != nil {
+		return nil, err
+	}
+	defer vfs.Free()
+
+	metaURI := strings.TrimSuffix(a.uri, "/") + "/__meta"
+	isDir, err := vfs.IsDir(metaURI)
+	if err != nil {
+		return nil, err
+	}
+	if !isDir {
+		return []time.Time{}, nil
+	}
+
+	_, files, err := vfs.List(metaURI)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint64]bool)
+	timestamps := make([]uint64, 0, len(files))
+	for _, file := range files {
+		timestamp, ok := metadataFileTimestamp(file)
+		if ok && !seen[timestamp] {
+			seen[timestamp] = true
+			timestamps = append(timestamps, timestamp)
+		}
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i] < timestamps[j]
+	})
+
+	times := make([]time.Time, len(timestamps))
+	for i, timestamp := range timestamps {
+		times[i] = timeFromTimestamp(timestamp)
+	}
+	return times, nil
+}
+
+// metadataEntryAt returns a metadata item as of a timestamp, nil if it does
+// not exist then
+func (a *Array) metadataEntryAt(key string, timestamp uint64) (*MetadataEntry, error) {
+	array, err := NewArray(a.context, a.uri)
+	if err != nil {
+		return nil, err
+	}
+	defer array.Free()
+
+	err = array.OpenAt(TILEDB_READ, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := array.MetadataEntries()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Key == key {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetMetadataAt returns a metadata item as it was at a given time, by
+// opening the array at that time. The array itself does not need to be open.
+func (a *Array) GetMetadataAt(key string, t time.Time) (*MetadataEntry, error) {
+	entry, err := a.metadataEntryAt(key, timestampFromTime(t))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("Error getting metadata from array, key: %s does not exist at %s", key, t)
+	}
+	return entry, nil
+}
+
+// MetadataVersion is the value of a metadata item from a timestamp on
+type MetadataVersion struct {
+	Timestamp time.Time
+	// Entry is nil if the item was deleted at Timestamp
+	Entry *MetadataEntry
+}
+
+// MetadataHistory returns the successive values of a metadata item, by
+// opening the array at each metadata timestamp. Only the timestamps at which
+// the item was created, changed or deleted are returned. The array itself
+// does not need to be open.
+func (a *Array) MetadataHistory(key string) ([]MetadataVersion, error) {
+	times, err := a.MetadataTimestamps()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]MetadataVersion, 0)
+	var previous *MetadataEntry
+	for _, t := range times {
+		entry, err := a.metadataEntryAt(key, timestampFromTime(t))
+		if err != nil {
+			return nil, err
+		}
+
+		changed := entry != nil || previous != nil
+		if entry != nil && previous != nil {
+			equal, err := metadataEntriesEqual(*previous, *entry)
+			if err != nil {
+				return nil, err
+			}
+			changed = !equal
+		}
+		if changed {
+			history = append(history, MetadataVersion{Timestamp: t, Entry: entry})
+		}
+		previous = entry
+	}
+	return history, nil
+}
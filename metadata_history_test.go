@@ -0,0 +1,71 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataFileTimestamp(t *testing.T) {
+	timestamp, ok := metadataFileTimestamp("file:///tmp/array/__meta/__1589718600000_1589718600000_f5fbd3b1e8a445d38e6d4c1e8b1a5f0e")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1589718600000), timestamp)
+
+	timestamp, ok = metadataFileTimestamp("file:///tmp/array/__meta/__f5fbd3b1e8a445d38e6d4c1e8b1a5f0e_1589718600000")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1589718600000), timestamp)
+
+	_, ok = metadataFileTimestamp("file:///tmp/array/__meta/__1589718600000_1589718600000_f5fbd3b1e8a445d38e6d4c1e8b1a5f0e.vac")
+	assert.False(t, ok)
+}
+
+func TestMetadataHistory(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_metadata_history")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	defer array.Free()
+
+	write := func(fn func()) {
+		// Metadata timestamps are in milliseconds
+		time.Sleep(5 * time.Millisecond)
+		assert.Nil(t, array.Open(TILEDB_WRITE))
+		fn()
+		assert.Nil(t, array.Close())
+	}
+	write(func() { assert.Nil(t, array.PutMetadata("level", int32(1))) })
+	write(func() { assert.Nil(t, array.PutMetadata("other", int32(5))) })
+	write(func() { assert.Nil(t, array.PutMetadata("level", int32(2))) })
+	write(func() { assert.Nil(t, array.DeleteMetadata("level")) })
+
+	timestamps, err := array.MetadataTimestamps()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(timestamps))
+
+	history, err := array.MetadataHistory("level")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(history))
+	assert.Equal(t, timestamps[0], history[0].Timestamp)
+	assert.Equal(t, []int32{1}, history[0].Entry.Values)
+	assert.Equal(t, timestamps[2], history[1].Timestamp)
+	assert.Equal(t, []int32{2}, history[1].Entry.Values)
+	assert.Equal(t, timestamps[3], history[2].Timestamp)
+	assert.Nil(t, history[2].Entry)
+
+	entry, err := array.GetMetadataAt("level", timestamps[1])
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1}, entry.Values)
+	_, err = array.GetMetadataAt("level", timestamps[0].Add(-time.Second))
+	assert.NotNil(t, err)
+}
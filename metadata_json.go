@@ -0,0 +1,410 @@
+package tiledb
+
+/*
+#cgo LDFLAGS: -ltiledb
+#cgo linux LDFLAGS: -ldl
+#include <tiledb/tiledb.h>
+#include <stdlib.h>
+#include <string.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unsafe"
+)
+
+/*
+MetadataEntry is a lossless representation of an array metadata item. Values
+holds a slice of the Go type of the datatype (see Datatype.MakeSlice), or a
+string for TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8.
+
+In JSON an entry is an object with the key, the datatype name and the
+values as an array of numbers, non finite floats being encoded as "NaN",
+"+Inf" and "-Inf":
+
+	{"key": "scale", "datatype": "FLOAT64", "values": [0.5, "NaN"]}
+
+TILEDB_STRING_UTF8 values are encoded as a JSON string. TILEDB_CHAR and
+TILEDB_STRING_ASCII values may hold any byte and are encoded as an array of
+byte values, a JSON string is also accepted when decoding.
+*/
+type MetadataEntry struct {
+	Key      string
+	Datatype Datatype
+	Values   interface{}
+}
+
+// jsonMetadataEntry is the JSON encoding of a MetadataEntry
+type jsonMetadataEntry struct {
+	Key      string          `json:"key"`
+	Datatype Datatype        `json:"datatype"`
+	Values   json.RawMessage `json:"values"`
+}
+
+// isMetadataString returns true if metadata values of datatype are
+// represented as a string
+func isMetadataString(datatype Datatype) bool {
+	switch datatype {
+	case TILEDB_CHAR, TILEDB_STRING_ASCII, TILEDB_STRING_UTF8:
+		return true
+	default:
+		return false
+	}
+}
+
+// isMetadataBytes returns true if metadata values of datatype are encoded
+// in JSON as an array of byte values
+func isMetadataBytes(datatype Datatype) bool {
+	switch datatype {
+	case TILEDB_CHAR, TILEDB_STRING_ASCII, TILEDB_UINT8, TILEDB_INT8:
+		return true
+	default:
+		return false
+	}
+}
+
+// MarshalJSON implements the Marshaler interface for MetadataEntry
+func (e MetadataEntry) MarshalJSON() ([]byte, error) {
+	values := e.Values
+	if e.Datatype == TILEDB_FLOAT32 || e.Datatype == TILEDB_FLOAT64 {
+		v := reflect.ValueOf(e.Values)
+		if v.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("Values of metadata %s must be a slice, got %T", e.Key, e.Values)
+		}
+		floats := make([]interface{}, v.Len())
+		for i := range floats {
+			f := v.Index(i).Float()
+			switch {
+			case math.IsNaN(f):
+				floats[i] = "NaN"
+			case math.IsInf(f, 1):
+				floats[i] = "+Inf"
+			case math.IsInf(f, -1):
+				floats[i] = "-Inf"
+			default:
+				floats[i] = v.Index(i).Interface()
+			}
+		}
+		values = floats
+	} else if isMetadataBytes(e.Datatype) {
+		// json encodes []uint8 as a base64 string and invalid UTF-8 in
+		// strings as U+FFFD, build an array of numbers
+		v := reflect.ValueOf(e.Values)
+		if v.Kind() == reflect.String {
+			v = reflect.ValueOf([]byte(v.String()))
+		}
+		if v.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("Values of metadata %s must be a slice, got %T", e.Key, e.Values)
+		}
+		numbers := make([]interface{}, v.Len())
+		for i := range numbers {
+			numbers[i] = v.Index(i).Interface()
+		}
+		values = numbers
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonMetadataEntry{Key: e.Key, Datatype: e.Datatype, Values: encoded})
+}
+
+// UnmarshalJSON implements the Unmarshaler interface for MetadataEntry
+func (e *MetadataEntry) UnmarshalJSON(b []byte) error {
+	var entry jsonMetadataEntry
+	err := json.Unmarshal(b, &entry)
+	if err != nil {
+		return err
+	}
+
+	e.Key = entry.Key
+	e.Datatype = entry.Datatype
+	if isMetadataString(entry.Datatype) {
+		var s string
+		if isMetadataBytes(entry.Datatype) && !bytes.HasPrefix(bytes.TrimSpace(entry.Values), []byte(`"`)) {
+			var b []byte
+			err = json.Unmarshal(entry.Values, &b)
+			s = string(b)
+		} else {
+			err = json.Unmarshal(entry.Values, &s)
+		}
+		e.Values = s
+		return err
+	}
+
+	slice, _, err := entry.Datatype.MakeSlice(1)
+	if err != nil {
+		return err
+	}
+	values := reflect.New(reflect.TypeOf(slice))
+
+	if entry.Datatype == TILEDB_FLOAT32 || entry.Datatype == TILEDB_FLOAT64 {
+		var floats []interface{}
+		err = json.Unmarshal(entry.Values, &floats)
+		if err != nil {
+			return err
+		}
+		elemType := values.Elem().Type().Elem()
+		for _, value := range floats {
+			var f float64
+			switch v := value.(type) {
+			case float64:
+				f = v
+			case string:
+				f, err = strconv.ParseFloat(v, 64)
+				if err != nil {
+					return fmt.Errorf("Invalid value %q of metadata %s", v, entry.Key)
+				}
+			default:
+				return fmt.Errorf("Invalid value %v of metadata %s", v, entry.Key)
+			}
+			values.Elem().Set(reflect.Append(values.Elem(), reflect.ValueOf(f).Convert(elemType)))
+		}
+	} else {
+		err = json.Unmarshal(entry.Values, values.Interface())
+		if err != nil {
+			return err
+		}
+	}
+
+	e.Values = values.Elem().Interface()
+	return nil
+}
+
+// valueNum returns the number of values of the entry and a pointer to a copy
+// of them
+func (e *MetadataEntry) valueNum() (uint, unsafe.Pointer, error) {
+	if isMetadataString(e.Datatype) {
+		s, ok := e.Values.(string)
+		if !ok {
+			return 0, nil, fmt.Errorf("Values of metadata %s of datatype %s must be a string, got %T",
+				e.Key, e.Datatype.String(), e.Values)
+		}
+		if len(s) == 0 {
+			return 0, nil, fmt.Errorf("Metadata %s has no values", e.Key)
+		}
+		b := []byte(s)
+		return uint(len(b)), unsafe.Pointer(&b[0]), nil
+	}
+
+	v := reflect.ValueOf(e.Values)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != e.Datatype.ReflectKind() {
+		return 0, nil, fmt.Errorf("Values of metadata %s of datatype %s must be a slice of %s, got %T",
+			e.Key, e.Datatype.String(), e.Datatype.ReflectKind().String(), e.Values)
+	}
+	if v.Len() == 0 {
+		return 0, nil, fmt.Errorf("Metadata %s has no values", e.Key)
+	}
+
+	values, cvalues, err := e.Datatype.MakeSlice(uint64(v.Len()))
+	if err != nil {
+		return 0, nil, err
+	}
+	reflect.Copy(reflect.ValueOf(values), v)
+	return uint(v.Len()), cvalues, nil
+}
+
+// PutMetadataEntry puts a metadata item with its datatype to an open array.
+// The array must be opened in WRITE mode, otherwise the function will error
+// out.
+func (a *Array) PutMetadataEntry(entry MetadataEntry) error {
+	valueNum, cvalue, err := entry.valueNum()
+	if err != nil {
+		return fmt.Errorf("Error adding metadata to array: %s", err)
+	}
+
+	ckey := C.CString(entry.Key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	ret := C.tiledb_array_put_metadata(a.context.tiledbContext, a.tiledbArray, ckey,
+		C.tiledb_datatype_t(entry.Datatype), C.uint(valueNum), cvalue)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("Error adding metadata to array: %s", a.context.LastError())
+	}
+	return nil
+}
+
+// metadataEntryFromIndex copies the metadata item at index
+func (a *Array) metadataEntryFromIndex(index uint64) (*MetadataEntry, error) {
+	var cKey *C.char
+	var cKeyLen C.uint32_t
+	var cType C.tiledb_datatype_t
+	var cValueNum C.uint
+	var cvalue unsafe.Pointer
+
+	ret := C.tiledb_array_get_metadata_from_index(a.context.tiledbContext,
+		a.tiledbArray, C.uint64_t(index), &cKey, &cKeyLen, &cType, &cValueNum, &cvalue)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("Error getting metadata from array: %s, Index: %d", a.context.LastError(), index)
+	}
+
+	entry := MetadataEntry{
+		Key:      C.GoStringN(cKey, C.int(cKeyLen)),
+		Datatype: Datatype(cType),
+	}
+	valueNum := uint64(cValueNum)
+	if valueNum == 0 {
+		return nil, fmt.Errorf("Error getting metadata from array, Index: %d does not exist", index)
+	}
+	if isMetadataString(entry.Datatype) {
+		entry.Values = C.GoStringN((*C.char)(cvalue), C.int(valueNum))
+		return &entry, nil
+	}
+
+	values, cvalues, err := entry.Datatype.MakeSlice(valueNum)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting metadata %s from array: %s", entry.Key, err)
+	}
+	C.memcpy(cvalues, cvalue, C.size_t(valueNum*entry.Datatype.Size()))
+	entry.Values = values
+	return &entry, nil
+}
+
+// MetadataEntries returns all metadata items of an open array with their
+// datatype, sorted by key. The array must be opened in READ mode, otherwise
+// the function will error out.
+func (a *Array) MetadataEntries() ([]MetadataEntry, error) {
+	num, err := a.GetMetadataNum()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]MetadataEntry, 0, num)
+	for i := uint64(0); i < num; i++ {
+		entry, err := a.metadataEntryFromIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+	return entries, nil
+}
+
+// ExportMetadata writes all metadata items of an array opened in READ mode
+// as an indented JSON list of MetadataEntry, sorted by key
+func (a *Array) ExportMetadata(w io.Writer) error {
+	entries, err := a.MetadataEntries()
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Error exporting metadata: %s", err)
+	}
+	_, err = w.Write(append(encoded, '\n'))
+	return err
+}
+
+// ImportMetadata reads a JSON list of MetadataEntry written by
+// ExportMetadata and puts the items to an array opened in WRITE mode.
+// Existing items with other keys are kept.
+func (a *Array) ImportMetadata(r io.Reader) error {
+	var entries []MetadataEntry
+	err := json.NewDecoder(r).Decode(&entries)
+	if err != nil {
+		return fmt.Errorf("Error importing metadata: %s", err)
+	}
+
+	for _, entry := range entries {
+		err = a.PutMetadataEntry(entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MetadataChange is a metadata item with different values in two arrays
+type MetadataChange struct {
+	Key  string
+	From MetadataEntry
+	To   MetadataEntry
+}
+
+// MetadataDiff lists the differences between the metadata of two arrays
+type MetadataDiff struct {
+	// Added holds the items only in the second array
+	Added []MetadataEntry
+	// Removed holds the items only in the first array
+	Removed []MetadataEntry
+	// Changed holds the items with a different datatype or values
+	Changed []MetadataChange
+}
+
+// Empty returns true if the metadata of both arrays are identical
+func (d *MetadataDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// metadataEntriesEqual returns true if two metadata items have the same
+// datatype and values. The JSON encodings are compared, which handle NaN
+// values.
+func metadataEntriesEqual(a MetadataEntry, b MetadataEntry) (bool, error) {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aJSON, bJSON), nil
+}
+
+// DiffMetadata compares the metadata of two arrays opened in READ mode. All
+// lists of the diff are sorted by key.
+func DiffMetadata(from *Array, to *Array) (*MetadataDiff, error) {
+	fromEntries, err := from.MetadataEntries()
+	if err != nil {
+		return nil, err
+	}
+	toEntries, err := to.MetadataEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := MetadataDiff{
+		Added:   make([]MetadataEntry, 0),
+		Removed: make([]MetadataEntry, 0),
+		Changed: make([]MetadataChange, 0),
+	}
+
+	// Both lists are sorted by key
+	i, j := 0, 0
+	for i < len(fromEntries) || j < len(toEntries) {
+		switch {
+		case j == len(toEntries) || (i < len(fromEntries) && fromEntries[i].Key < toEntries[j].Key):
+			diff.Removed = append(diff.Removed, fromEntries[i])
+			i++
+		case i == len(fromEntries) || toEntries[j].Key < fromEntries[i].Key:
+			diff.Added = append(diff.Added, toEntries[j])
+			j++
+		default:
+			equal, err := metadataEntriesEqual(fromEntries[i], toEntries[j])
+			if err != nil {
+				return nil, err
+			}
+			if !equal {
+				diff.Changed = append(diff.Changed, MetadataChange{Key: fromEntries[i].Key, From: fromEntries[i], To: toEntries[j]})
+			}
+			i++
+			j++
+		}
+	}
+
+	return &diff, nil
+}
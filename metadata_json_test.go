@@ -0,0 +1,118 @@
+package tiledb
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataEntryJSON(t *testing.T) {
+	entry := MetadataEntry{Key: "scale", Datatype: TILEDB_FLOAT32, Values: []float32{0.5, float32(math.Inf(-1))}}
+	encoded, err := json.Marshal(entry)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"key": "scale", "datatype": "FLOAT32", "values": [0.5, "-Inf"]}`, string(encoded))
+
+	var decoded MetadataEntry
+	assert.Nil(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, entry, decoded)
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"key": "day", "datatype": "DATETIME_DAY", "values": [18262]}`), &decoded))
+	assert.Equal(t, MetadataEntry{Key: "day", Datatype: TILEDB_DATETIME_DAY, Values: []int64{18262}}, decoded)
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"key": "name", "datatype": "STRING_ASCII", "values": "abc"}`), &decoded))
+	assert.Equal(t, MetadataEntry{Key: "name", Datatype: TILEDB_STRING_ASCII, Values: "abc"}, decoded)
+
+	// Bytes are numbers, not base64 strings
+	entry = MetadataEntry{Key: "flags", Datatype: TILEDB_UINT8, Values: []uint8{1, 255}}
+	encoded, err = json.Marshal(entry)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"key": "flags", "datatype": "UINT8", "values": [1, 255]}`, string(encoded))
+	assert.Nil(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, entry, decoded)
+
+	// CHAR and ASCII values are bytes, which may not be valid UTF-8
+	entry = MetadataEntry{Key: "raw", Datatype: TILEDB_CHAR, Values: "a\xff"}
+	encoded, err = json.Marshal(entry)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"key": "raw", "datatype": "CHAR", "values": [97, 255]}`, string(encoded))
+	assert.Nil(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, entry, decoded)
+}
+
+func TestExportImportDiffMetadata(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_metadata_json")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	tmpCopyPath := path.Join(os.TempDir(), "tiledb_test_metadata_json_copy")
+	defer os.RemoveAll(tmpCopyPath)
+	if _, err = os.Stat(tmpCopyPath); err == nil {
+		os.RemoveAll(tmpCopyPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+	createBatchTestArray(t, context, tmpCopyPath)
+
+	entries := []MetadataEntry{
+		{Key: "day", Datatype: TILEDB_DATETIME_DAY, Values: []int64{18262, 18263}},
+		{Key: "name", Datatype: TILEDB_STRING_ASCII, Values: "sensor"},
+		{Key: "scale", Datatype: TILEDB_FLOAT64, Values: []float64{0.5, math.NaN()}},
+	}
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	for _, entry := range entries {
+		assert.Nil(t, array.PutMetadataEntry(entry))
+	}
+	assert.NotNil(t, array.PutMetadataEntry(MetadataEntry{Key: "bad", Datatype: TILEDB_INT32, Values: []int64{1}}))
+	assert.Nil(t, array.Close())
+
+	var exported bytes.Buffer
+	assert.Nil(t, array.Open(TILEDB_READ))
+	assert.Nil(t, array.ExportMetadata(&exported))
+	assert.Nil(t, array.Close())
+
+	arrayCopy, err := NewArray(context, tmpCopyPath)
+	assert.Nil(t, err)
+	assert.Nil(t, arrayCopy.Open(TILEDB_WRITE))
+	assert.Nil(t, arrayCopy.ImportMetadata(&exported))
+	assert.Nil(t, arrayCopy.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+	assert.Nil(t, arrayCopy.Open(TILEDB_READ))
+	imported, err := arrayCopy.MetadataEntries()
+	assert.Nil(t, err)
+	assert.Equal(t, entries[:2], imported[:2])
+	assert.True(t, math.IsNaN(imported[2].Values.([]float64)[1]))
+
+	diff, err := DiffMetadata(array, arrayCopy)
+	assert.Nil(t, err)
+	assert.True(t, diff.Empty())
+	assert.Nil(t, arrayCopy.Close())
+
+	assert.Nil(t, arrayCopy.Open(TILEDB_WRITE))
+	assert.Nil(t, arrayCopy.DeleteMetadata("day"))
+	assert.Nil(t, arrayCopy.PutMetadataEntry(MetadataEntry{Key: "name", Datatype: TILEDB_STRING_UTF8, Values: "sensor"}))
+	assert.Nil(t, arrayCopy.PutMetadataEntry(MetadataEntry{Key: "version", Datatype: TILEDB_UINT32, Values: []uint32{2}}))
+	assert.Nil(t, arrayCopy.Close())
+
+	assert.Nil(t, arrayCopy.Open(TILEDB_READ))
+	defer arrayCopy.Close()
+	diff, err = DiffMetadata(array, arrayCopy)
+	assert.Nil(t, err)
+	assert.Equal(t, []MetadataEntry{{Key: "version", Datatype: TILEDB_UINT32, Values: []uint32{2}}}, diff.Added)
+	assert.Equal(t, []MetadataEntry{entries[0]}, diff.Removed)
+	assert.Equal(t, 1, len(diff.Changed))
+	assert.Equal(t, "name", diff.Changed[0].Key)
+	assert.Equal(t, TILEDB_STRING_UTF8, diff.Changed[0].To.Datatype)
+}
@@ -0,0 +1,391 @@
+package tiledb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// metadataField is a field of a struct stored as one array metadata item
+type metadataField struct {
+	key   string
+	value reflect.Value
+	// absent is true for the fields under a nil pointer to a nested struct
+	absent bool
+}
+
+// metadataPointer is a nil pointer to a nested struct allocated to set the
+// fields [first, last) of a metadataStructFields
+type metadataPointer struct {
+	value       reflect.Value
+	first, last int
+}
+
+// metadataStructFields holds the fields of a struct stored as metadata
+type metadataStructFields struct {
+	fields   []metadataField
+	pointers []metadataPointer
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// metadataTypes maps numeric kinds to the type they are stored as
+var metadataTypes = map[reflect.Kind]reflect.Type{
+	reflect.Int:     reflect.TypeOf(int64(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint64(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.Bool:    reflect.TypeOf(uint8(0)),
+}
+
+// metadataFields lists the fields of a struct stored as metadata, flattening
+// nested structs with dotted keys and embedded structs without. With alloc,
+// nil pointers to nested structs are allocated and listed in pointers,
+// otherwise the fields of a zero struct are listed as absent. Recursive
+// types, which would flatten to infinitely many keys, are rejected.
+func metadataFields(v reflect.Value, alloc bool) (*metadataStructFields, error) {
+	// Recursion is checked on the type, before add allocates any pointer
+	err := checkMetadataType(v.Type(), make(map[reflect.Type]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	s := metadataStructFields{
+		fields:   make([]metadataField, 0),
+		pointers: make([]metadataPointer, 0),
+	}
+	s.add(v, "", alloc, false)
+	return &s, nil
+}
+
+// checkMetadataType fails if the struct type t contains itself through
+// pointers to nested structs. parents holds the struct types t is nested in.
+func checkMetadataType(t reflect.Type, parents map[reflect.Type]bool) error {
+	if parents[t] {
+		return fmt.Errorf("recursive type %s is not supported", t)
+	}
+	parents[t] = true
+	defer delete(parents, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if structField.PkgPath != "" || structField.Tag.Get("tiledb") == "-" {
+			continue
+		}
+
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			err := checkMetadataType(fieldType, parents)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// add appends the fields of the struct v with keys starting with prefix
+func (s *metadataStructFields) add(v reflect.Value, prefix string, alloc bool, absent bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		// Skip unexported fields
+		if structField.PkgPath != "" {
+			continue
+		}
+
+		name := structField.Name
+		tag, tagged := structField.Tag.Lookup("tiledb")
+		if tag == "-" {
+			continue
+		}
+		if tag != "" {
+			name = tag
+		}
+		key := prefix + name
+
+		value := v.Field(i)
+		fieldAbsent := absent
+		pointer := -1
+		if value.Kind() == reflect.Ptr && value.Type().Elem().Kind() == reflect.Struct && value.Type().Elem() != timeType {
+			if value.IsNil() {
+				if alloc {
+					value.Set(reflect.New(value.Type().Elem()))
+					pointer = len(s.pointers)
+					s.pointers = append(s.pointers, metadataPointer{value: value, first: len(s.fields)})
+				} else {
+					value = reflect.New(value.Type().Elem())
+					fieldAbsent = true
+				}
+			}
+			value = value.Elem()
+		}
+
+		if value.Kind() == reflect.Struct && value.Type() != timeType {
+			if structField.Anonymous && !tagged {
+				s.add(value, prefix, alloc, fieldAbsent)
+			} else {
+				s.add(value, key+".", alloc, fieldAbsent)
+			}
+			if pointer >= 0 {
+				s.pointers[pointer].last = len(s.fields)
+			}
+			continue
+		}
+
+		s.fields = append(s.fields, metadataField{key: key, value: value, absent: fieldAbsent})
+	}
+}
+
+// metadataStruct returns the struct v points to, or v itself
+func metadataStruct(v interface{}, pointer bool) (reflect.Value, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	} else if pointer {
+		return reflect.Value{}, fmt.Errorf("Expected a non nil pointer to a struct, got %T", v)
+	}
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("Expected a struct, got %T", v)
+	}
+	return value, nil
+}
+
+// encodeMetadata converts a field to a value accepted by PutMetadata, nil for
+// empty strings and slices which can not be stored
+func encodeMetadata(value reflect.Value) (interface{}, error) {
+	if value.Type() == timeType {
+		return value.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		if value.Len() == 0 {
+			return nil, nil
+		}
+		return value.String(), nil
+	case reflect.Bool:
+		if value.Bool() {
+			return uint8(1), nil
+		}
+		return uint8(0), nil
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return nil, nil
+		}
+
+		elemKind := value.Type().Elem().Kind()
+		if elemKind == reflect.String {
+			encoded, err := json.Marshal(value.Interface())
+			if err != nil {
+				return nil, err
+			}
+			return string(encoded), nil
+		}
+
+		elemType, ok := metadataTypes[elemKind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %s", value.Type())
+		}
+		slice := reflect.MakeSlice(reflect.SliceOf(elemType), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elem := value.Index(i)
+			if elemKind == reflect.Bool {
+				if elem.Bool() {
+					slice.Index(i).SetUint(1)
+				}
+				continue
+			}
+			slice.Index(i).Set(elem.Convert(elemType))
+		}
+		return slice.Interface(), nil
+	default:
+		valueType, ok := metadataTypes[value.Kind()]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %s", value.Type())
+		}
+		return value.Convert(valueType).Interface(), nil
+	}
+}
+
+// decodeMetadataValue sets a numeric or bool value from a metadata value
+func decodeMetadataValue(target reflect.Value, value reflect.Value) error {
+	if _, ok := metadataTypes[value.Kind()]; !ok || value.Kind() == reflect.Bool {
+		return fmt.Errorf("can not convert %s to %s", value.Type(), target.Type())
+	}
+
+	if target.Kind() == reflect.Bool {
+		zero := reflect.Zero(value.Type()).Interface()
+		target.SetBool(value.Interface() != zero)
+		return nil
+	}
+	if _, ok := metadataTypes[target.Kind()]; !ok {
+		return fmt.Errorf("can not convert %s to %s", value.Type(), target.Type())
+	}
+	target.Set(value.Convert(target.Type()))
+	return nil
+}
+
+// decodeMetadata sets a field from a metadata value
+func decodeMetadata(target reflect.Value, value interface{}) error {
+	if target.Type() == timeType {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("can not convert %T to time.Time", value)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("can not convert %T to %s", value, target.Type())
+		}
+		target.SetString(s)
+		return nil
+	case reflect.Slice:
+		if target.Type().Elem().Kind() == reflect.String {
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("can not convert %T to %s", value, target.Type())
+			}
+			return json.Unmarshal([]byte(s), target.Addr().Interface())
+		}
+
+		values := reflect.ValueOf(value)
+		if values.Kind() != reflect.Slice {
+			// Single values are not returned as slices
+			values = reflect.Append(reflect.MakeSlice(reflect.SliceOf(values.Type()), 0, 1), values)
+		}
+		slice := reflect.MakeSlice(target.Type(), values.Len(), values.Len())
+		for i := 0; i < values.Len(); i++ {
+			err := decodeMetadataValue(slice.Index(i), values.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	default:
+		return decodeMetadataValue(target, reflect.ValueOf(value))
+	}
+}
+
+/*
+MarshalMetadata stores the exported fields of a struct as metadata items of
+an array opened in WRITE mode. Fields are stored under their name, or the
+name given by a `tiledb:"name"` tag, and skipped with `tiledb:"-"`. Nested
+structs are flattened with dotted keys ("source.name"), recursive types are
+not supported.
+
+Numbers are stored with their datatype (int and uint as 64 bit integers),
+bool as TILEDB_UINT8, strings as TILEDB_STRING_UTF8, time.Time as RFC 3339
+strings and []string as JSON encoded strings. Empty strings and slices can
+not be stored, their key is deleted, as are the keys of the fields of a nil
+pointer to a nested struct.
+
+	type Provenance struct {
+		Source  string    `tiledb:"source"`
+		Created time.Time `tiledb:"created"`
+		Tags    []string  `tiledb:"tags"`
+		Params  struct {
+			Threshold float64 `tiledb:"threshold"`
+		} `tiledb:"params"`
+	}
+	err = array.MarshalMetadata(provenance)
+*/
+func (a *Array) MarshalMetadata(v interface{}) error {
+	value, err := metadataStruct(v, false)
+	if err != nil {
+		return fmt.Errorf("Error marshalling metadata: %s", err)
+	}
+	fields, err := metadataFields(value, false)
+	if err != nil {
+		return fmt.Errorf("Error marshalling metadata: %s", err)
+	}
+	for _, field := range fields.fields {
+		var encoded interface{}
+		if !field.absent {
+			encoded, err = encodeMetadata(field.value)
+			if err != nil {
+				return fmt.Errorf("Error marshalling metadata %s: %s", field.key, err)
+			}
+		}
+		if encoded == nil {
+			err = a.DeleteMetadata(field.key)
+		} else {
+			err = a.PutMetadata(field.key, encoded)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnmarshalMetadata sets the fields of the struct v points to from the
+// metadata of an array opened in READ mode, following the mapping of
+// MarshalMetadata. Fields without a metadata item are set to their zero
+// value, pointers to nested structs without any to nil. Numbers are converted
+// to the type of the field.
+func (a *Array) UnmarshalMetadata(v interface{}) error {
+	value, err := metadataStruct(v, true)
+	if err != nil {
+		return fmt.Errorf("Error unmarshalling metadata: %s", err)
+	}
+
+	metadata, err := a.GetMetadataMap()
+	if err != nil {
+		return err
+	}
+
+	fields, err := metadataFields(value, true)
+	if err != nil {
+		return fmt.Errorf("Error unmarshalling metadata: %s", err)
+	}
+	found := make([]bool, len(fields.fields))
+	for i, field := range fields.fields {
+		item, ok := metadata[field.key]
+		if !ok {
+			field.value.Set(reflect.Zero(field.value.Type()))
+			continue
+		}
+		found[i] = true
+
+		err = decodeMetadata(field.value, item.Value)
+		if err != nil {
+			return fmt.Errorf("Error unmarshalling metadata %s of datatype %s: %s",
+				field.key, item.Datatype.String(), err)
+		}
+	}
+
+	// Pointers to nested structs without any metadata item are left nil
+	for _, pointer := range fields.pointers {
+		empty := true
+		for i := pointer.first; i < pointer.last; i++ {
+			empty = empty && !found[i]
+		}
+		if empty {
+			pointer.value.Set(reflect.Zero(pointer.value.Type()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testProvenanceSource struct {
+	Name    string `tiledb:"name"`
+	Version int    `tiledb:"version"`
+}
+
+type testProvenance struct {
+	Created  time.Time            `tiledb:"created"`
+	Tags     []string             `tiledb:"tags"`
+	Verified bool                 `tiledb:"verified"`
+	Scale    []float32            `tiledb:"scale"`
+	Source   testProvenanceSource `tiledb:"source"`
+	Parent   *testProvenanceSource
+	Comment  string `tiledb:"-"`
+	internal string
+}
+
+type testMetadataNode struct {
+	Value int
+	Next  *testMetadataNode
+}
+
+func TestMarshalMetadata(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_marshal_metadata")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+
+	provenance := testProvenance{
+		Created:  time.Date(2020, 5, 17, 12, 30, 0, 500, time.UTC),
+		Tags:     []string{"raw", "daily"},
+		Verified: true,
+		Scale:    []float32{1.5},
+		Source:   testProvenanceSource{Name: "sensor", Version: 3},
+		Comment:  "not stored",
+		internal: "not stored",
+	}
+
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, array.MarshalMetadata(&provenance))
+	assert.NotNil(t, array.MarshalMetadata(42))
+	assert.Nil(t, array.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	metadata, err := array.GetMetadataMap()
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(metadata))
+	assert.Equal(t, TILEDB_UINT8, metadata["verified"].Datatype)
+	assert.Equal(t, TILEDB_INT64, metadata["source.version"].Datatype)
+	assert.Equal(t, "sensor", metadata["source.name"].Value)
+
+	var result testProvenance
+	assert.Nil(t, array.UnmarshalMetadata(&result))
+	assert.True(t, provenance.Created.Equal(result.Created))
+	assert.Equal(t, provenance.Tags, result.Tags)
+	assert.True(t, result.Verified)
+	assert.Equal(t, provenance.Scale, result.Scale)
+	assert.Equal(t, provenance.Source, result.Source)
+	assert.Nil(t, result.Parent)
+	assert.Equal(t, "", result.Comment)
+
+	// Numbers are converted to the type of the field
+	var converted struct {
+		Source struct {
+			Version float64 `tiledb:"version"`
+		} `tiledb:"source"`
+		Verified string `tiledb:"verified"`
+	}
+	assert.NotNil(t, array.UnmarshalMetadata(&converted))
+	assert.Equal(t, 3.0, converted.Source.Version)
+	assert.NotNil(t, array.UnmarshalMetadata(converted))
+	assert.Nil(t, array.Close())
+
+	// The keys of a nil pointer to a nested struct are deleted. Metadata
+	// timestamps are in milliseconds.
+	provenance.Parent = &testProvenanceSource{Name: "batch", Version: 1}
+	time.Sleep(5 * time.Millisecond)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, array.MarshalMetadata(&provenance))
+	assert.Nil(t, array.Close())
+	assert.Nil(t, array.Open(TILEDB_READ))
+	assert.Nil(t, array.UnmarshalMetadata(&result))
+	assert.Equal(t, provenance.Parent, result.Parent)
+	assert.Nil(t, array.Close())
+
+	provenance.Parent = nil
+	time.Sleep(5 * time.Millisecond)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, array.MarshalMetadata(&provenance))
+	assert.Nil(t, array.Close())
+	assert.Nil(t, array.Open(TILEDB_READ))
+	metadata, err = array.GetMetadataMap()
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(metadata))
+	assert.Nil(t, array.UnmarshalMetadata(&result))
+	assert.Nil(t, result.Parent)
+
+	// Recursive types are rejected without allocating their pointers
+	var node testMetadataNode
+	assert.NotNil(t, array.UnmarshalMetadata(&node))
+	assert.Nil(t, node.Next)
+	_, err = metadataFields(reflect.ValueOf(testMetadataNode{}), false)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,316 @@
+package tiledb
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NDArray is an N-dimensional array of single valued cells stored in a flat
+// typed slice, e.g. the cells of one attribute of a dense subarray.
+// Strides are expressed in elements and follow from the shape and layout.
+type NDArray struct {
+	Data    interface{}
+	Shape   []uint64
+	Strides []uint64
+	Layout  Layout
+}
+
+// NewNDArray wraps a typed slice holding the cells of an array with the
+// given shape, in TILEDB_ROW_MAJOR or TILEDB_COL_MAJOR layout
+func NewNDArray(data interface{}, shape []uint64, layout Layout) (*NDArray, error) {
+	if data == nil || reflect.TypeOf(data).Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Data passed must be a slice, type passed was: %T", data)
+	}
+
+	if layout != TILEDB_ROW_MAJOR && layout != TILEDB_COL_MAJOR {
+		return nil, fmt.Errorf("Layout of an NDArray must be TILEDB_ROW_MAJOR or TILEDB_COL_MAJOR")
+	}
+
+	if len(shape) == 0 {
+		return nil, fmt.Errorf("Shape of an NDArray must have at least one dimension")
+	}
+
+	size := uint64(1)
+	for _, extent := range shape {
+		size *= extent
+	}
+
+	if uint64(reflect.ValueOf(data).Len()) != size {
+		return nil, fmt.Errorf("Data has %d elements but shape %v holds %d", reflect.ValueOf(data).Len(), shape, size)
+	}
+
+	strides := make([]uint64, len(shape))
+	stride := uint64(1)
+	if layout == TILEDB_ROW_MAJOR {
+		for i := len(shape) - 1; i >= 0; i-- {
+			strides[i] = stride
+			stride *= shape[i]
+		}
+	} else {
+		for i := 0; i < len(shape); i++ {
+			strides[i] = stride
+			stride *= shape[i]
+		}
+	}
+
+	return &NDArray{Data: data, Shape: shape, Strides: strides, Layout: layout}, nil
+}
+
+// Size returns the number of cells of the array
+func (n *NDArray) Size() uint64 {
+	size := uint64(1)
+	for _, extent := range n.Shape {
+		size *= extent
+	}
+	return size
+}
+
+// Index returns the position in Data of the cell at the given coordinates,
+// which are zero based
+func (n *NDArray) Index(coords ...uint64) (uint64, error) {
+	if len(coords) != len(n.Shape) {
+		return 0, fmt.Errorf("Expected %d coordinates, got %d", len(n.Shape), len(coords))
+	}
+
+	var index uint64
+	for i, coord := range coords {
+		if coord >= n.Shape[i] {
+			return 0, fmt.Errorf("Coordinate %d is out of bounds for dimension %d of size %d", coord, i, n.Shape[i])
+		}
+		index += coord * n.Strides[i]
+	}
+	return index, nil
+}
+
+// At returns the value of the cell at the given coordinates
+func (n *NDArray) At(coords ...uint64) (interface{}, error) {
+	index, err := n.Index(coords...)
+	if err != nil {
+		return nil, err
+	}
+	return reflect.ValueOf(n.Data).Index(int(index)).Interface(), nil
+}
+
+// Set sets the value of the cell at the given coordinates. The value must
+// have the element type of Data
+func (n *NDArray) Set(value interface{}, coords ...uint64) error {
+	index, err := n.Index(coords...)
+	if err != nil {
+		return err
+	}
+
+	element := reflect.ValueOf(n.Data).Index(int(index))
+	if reflect.TypeOf(value) != element.Type() {
+		return fmt.Errorf("Value of type %T can not be stored in NDArray of %s", value, element.Type())
+	}
+	element.Set(reflect.ValueOf(value))
+	return nil
+}
+
+// ToLayout returns a copy of the array with its cells stored in the given
+// layout. The array itself is returned if it already has that layout
+func (n *NDArray) ToLayout(layout Layout) (*NDArray, error) {
+	if layout == n.Layout {
+		return n, nil
+	}
+
+	source := reflect.ValueOf(n.Data)
+	data := reflect.MakeSlice(source.Type(), source.Len(), source.Len())
+	result, err := NewNDArray(data.Interface(), n.Shape, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk the cells in row major order, updating both flat indexes
+	coords := make([]uint64, len(n.Shape))
+	for i := uint64(0); i < n.Size(); i++ {
+		var from, to uint64
+		for dim, coord := range coords {
+			from += coord * n.Strides[dim]
+			to += coord * result.Strides[dim]
+		}
+		data.Index(int(to)).Set(source.Index(int(from)))
+
+		for dim := len(coords) - 1; dim >= 0; dim-- {
+			coords[dim]++
+			if coords[dim] < n.Shape[dim] {
+				break
+			}
+			coords[dim] = 0
+		}
+	}
+
+	return result, nil
+}
+
+// subarrayShape returns the number of cells along each dimension of an
+// integer subarray [start0, end0, start1, end1, ...]
+func subarrayShape(subarray interface{}) ([]uint64, error) {
+	if subarray == nil || reflect.TypeOf(subarray).Kind() != reflect.Slice {
+		return nil, fmt.Errorf("Subarray passed must be a slice, type passed was: %T", subarray)
+	}
+
+	value := reflect.ValueOf(subarray)
+	if value.Len() == 0 || value.Len()%2 != 0 {
+		return nil, fmt.Errorf("Subarray must hold a start and an end per dimension, got %d values", value.Len())
+	}
+
+	shape := make([]uint64, value.Len()/2)
+	for i := range shape {
+		start, end := value.Index(2*i), value.Index(2*i+1)
+		switch start.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if end.Int() < start.Int() {
+				return nil, fmt.Errorf("Subarray end %d is before start %d", end.Int(), start.Int())
+			}
+			shape[i] = uint64(end.Int()-start.Int()) + 1
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if end.Uint() < start.Uint() {
+				return nil, fmt.Errorf("Subarray end %d is before start %d", end.Uint(), start.Uint())
+			}
+			shape[i] = end.Uint() - start.Uint() + 1
+		default:
+			return nil, fmt.Errorf("Subarray of an NDArray must have integer coordinates, type passed was: %T", subarray)
+		}
+	}
+
+	return shape, nil
+}
+
+// ReadNDArray reads the cells of one attribute of a subarray into an
+// NDArray with the given layout. The array must be dense and opened in READ
+// mode.
+func ReadNDArray(ctx *Context, array *Array, attribute string, subarray interface{}, layout Layout) (*NDArray, error) {
+	shape, err := subarrayShape(subarray)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return nil, err
+	}
+	if arrayType != TILEDB_DENSE {
+		return nil, fmt.Errorf("Error reading NDArray, array must be dense")
+	}
+
+	attr, err := schema.AttributeFromName(attribute)
+	if err != nil {
+		return nil, err
+	}
+	defer attr.Free()
+
+	cellValNum, err := attr.CellValNum()
+	if err != nil {
+		return nil, err
+	}
+	if cellValNum != 1 {
+		return nil, fmt.Errorf("Attribute %s must have one value per cell to be read as an NDArray", attribute)
+	}
+
+	datatype, err := attr.Type()
+	if err != nil {
+		return nil, err
+	}
+
+	size := uint64(1)
+	for _, extent := range shape {
+		size *= extent
+	}
+
+	data, _, err := datatype.MakeSlice(size)
+	if err != nil {
+		return nil, err
+	}
+
+	ndArray, err := NewNDArray(data, shape, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := NewQuery(ctx, array)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Free()
+
+	err = query.SetLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.SetSubArray(subarray)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = query.SetBuffer(attribute, data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = query.Submit()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := query.Status()
+	if err != nil {
+		return nil, err
+	}
+	if status != TILEDB_COMPLETED {
+		return nil, fmt.Errorf("Error reading NDArray, query did not complete, status: %d", status)
+	}
+
+	elements, err := query.ResultBufferElements()
+	if err != nil {
+		return nil, err
+	}
+	if elements[attribute][1] != size {
+		return nil, fmt.Errorf("Error reading NDArray, read %d cells of attribute %s, expected %d", elements[attribute][1], attribute, size)
+	}
+
+	return ndArray, nil
+}
+
+// WriteNDArray writes an NDArray to one attribute of a dense subarray with
+// the same shape. The array must be opened in WRITE mode.
+func WriteNDArray(ctx *Context, array *Array, attribute string, subarray interface{}, ndArray *NDArray) error {
+	shape, err := subarrayShape(subarray)
+	if err != nil {
+		return err
+	}
+
+	if !reflect.DeepEqual(shape, ndArray.Shape) {
+		return fmt.Errorf("Shape of NDArray %v does not match subarray shape %v", ndArray.Shape, shape)
+	}
+
+	query, err := NewQuery(ctx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	err = query.SetLayout(ndArray.Layout)
+	if err != nil {
+		return err
+	}
+
+	err = query.SetSubArray(subarray)
+	if err != nil {
+		return err
+	}
+
+	_, err = query.SetBuffer(attribute, ndArray.Data)
+	if err != nil {
+		return err
+	}
+
+	return query.Submit()
+}
@@ -0,0 +1,110 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNDArrayLayout(t *testing.T) {
+	// 2 x 3 array in row major order
+	ndArray, err := NewNDArray([]int32{1, 2, 3, 4, 5, 6}, []uint64{2, 3}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{3, 1}, ndArray.Strides)
+	assert.Equal(t, uint64(6), ndArray.Size())
+
+	value, err := ndArray.At(1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), value)
+
+	_, err = ndArray.At(2, 0)
+	assert.NotNil(t, err)
+
+	colMajor, err := ndArray.ToLayout(TILEDB_COL_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2}, colMajor.Strides)
+	assert.Equal(t, []int32{1, 4, 2, 5, 3, 6}, colMajor.Data)
+
+	value, err = colMajor.At(1, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), value)
+
+	assert.Nil(t, colMajor.Set(int32(7), 0, 2))
+	assert.Equal(t, []int32{1, 4, 2, 5, 7, 6}, colMajor.Data)
+	assert.NotNil(t, colMajor.Set(int64(7), 0, 2))
+
+	_, err = NewNDArray([]int32{1, 2, 3}, []uint64{2, 2}, TILEDB_ROW_MAJOR)
+	assert.NotNil(t, err)
+}
+
+func TestReadWriteNDArray(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_ndarray")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	rows, err := NewDimension(context, "rows", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	cols, err := NewDimension(context, "cols", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(rows, cols))
+
+	arraySchema, err := NewArraySchema(context, TILEDB_DENSE)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.SetDomain(domain))
+	a1, err := NewAttribute(context, "a1", TILEDB_FLOAT64)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.AddAttributes(a1))
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+
+	// Write a 2 x 3 block given in column major order
+	ndArray, err := NewNDArray([]float64{1, 4, 2, 5, 3, 6}, []uint64{2, 3}, TILEDB_COL_MAJOR)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.NotNil(t, WriteNDArray(context, array, "a1", []int32{1, 2, 1, 2}, ndArray))
+	assert.Nil(t, WriteNDArray(context, array, "a1", []int32{1, 2, 1, 3}, ndArray))
+	assert.Nil(t, array.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	result, err := ReadNDArray(context, array, "a1", []int32{1, 2, 1, 3}, TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{2, 3}, result.Shape)
+	assert.Equal(t, []float64{1, 2, 3, 4, 5, 6}, result.Data)
+
+	value, err := result.At(1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(6), value)
+}
+
+func TestReadNDArraySparse(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_ndarray_sparse")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	_, err = ReadNDArray(context, array, "a1", []int32{1, 2, 1, 2}, TILEDB_ROW_MAJOR)
+	assert.NotNil(t, err)
+}
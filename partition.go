@@ -0,0 +1,474 @@
+package tiledb
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// PartitionOptions configures a PartitionedReader
+type PartitionOptions struct {
+	// Partitions is the number of partitions the subarray is split into,
+	// runtime.NumCPU() if 0. Fewer partitions are created if the subarray
+	// spans fewer tiles.
+	Partitions int
+	// Workers is the number of partitions read concurrently, Partitions if 0
+	Workers int
+	// BatchSize is the number of cells read per query, DefaultBatchSize if 0
+	BatchSize uint64
+	// Layout of the results, TILEDB_ROW_MAJOR by default. With
+	// TILEDB_ROW_MAJOR or TILEDB_COL_MAJOR the results of the partitions are
+	// returned in order. With TILEDB_UNORDERED batches are returned as soon
+	// as they are read.
+	Layout Layout
+}
+
+// partitionResult is a batch read by a worker, or the error it failed with
+type partitionResult struct {
+	batch *Batch
+	err   error
+}
+
+/*
+PartitionedReader reads a subarray with concurrent queries. The subarray is
+split along tile boundaries of one dimension into partitions, each read by
+its own Query in a pool of workers.
+
+For TILEDB_ROW_MAJOR the first dimension is split and for TILEDB_COL_MAJOR
+the last one, so that concatenating the results of the partitions gives the
+results in the requested layout. For TILEDB_UNORDERED the dimension
+spanning the most tiles is split. Only integer dimensions can be split.
+
+	reader, err := NewPartitionedReader(ctx, array, []string{"a1"}, nil, &PartitionOptions{Partitions: 8})
+	defer reader.Free()
+	err = reader.ReadAll(func(batch *Batch) error {
+		// Consume batch
+		return nil
+	})
+*/
+type PartitionedReader struct {
+	context    *Context
+	array      *Array
+	names      []string
+	partitions [][]QueryRange
+	options    PartitionOptions
+
+	started bool
+	results []chan partitionResult
+	current int
+	done    chan struct{}
+	freed   sync.Once
+	workers sync.WaitGroup
+}
+
+// NewPartitionedReader creates a PartitionedReader for an array opened in
+// READ mode. names lists the attributes and dimensions to read, all of them
+// if it is empty. subarray holds at most one range per dimension, dimensions
+// without a range are read over their non empty domain.
+func NewPartitionedReader(ctx *Context, array *Array, names []string, subarray []QueryRange, options *PartitionOptions) (*PartitionedReader, error) {
+	reader := PartitionedReader{context: ctx, array: array, names: names, done: make(chan struct{})}
+	if options != nil {
+		reader.options = *options
+	}
+	if reader.options.Partitions <= 0 {
+		reader.options.Partitions = runtime.NumCPU()
+	}
+	if reader.options.Workers <= 0 {
+		reader.options.Workers = reader.options.Partitions
+	}
+	if reader.options.BatchSize == 0 {
+		reader.options.BatchSize = DefaultBatchSize
+	}
+
+	// Check the fields before starting the workers
+	check, err := NewBatchReader(ctx, array, names, 1)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating partitioned reader: %s", err)
+	}
+	check.Free()
+
+	reader.partitions, err = array.partitionSubarray(subarray, reader.options.Partitions, reader.options.Layout)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating partitioned reader: %s", err)
+	}
+
+	return &reader, nil
+}
+
+// Partitions returns the ranges read by each partition
+func (r *PartitionedReader) Partitions() [][]QueryRange {
+	return r.partitions
+}
+
+// readPartition reads partition i, sending copies of its batches to results
+func (r *PartitionedReader) readPartition(i int, results chan<- partitionResult) {
+	send := func(result partitionResult) bool {
+		select {
+		case results <- result:
+			return true
+		case <-r.done:
+			return false
+		}
+	}
+
+	reader, err := NewBatchReader(r.context, r.array, r.names, r.options.BatchSize)
+	if err != nil {
+		send(partitionResult{err: err})
+		return
+	}
+	defer reader.Free()
+
+	layout := r.options.Layout
+	if layout == TILEDB_UNORDERED {
+		schema, err := r.array.Schema()
+		if err != nil {
+			send(partitionResult{err: err})
+			return
+		}
+		defer schema.Free()
+		arrayType, err := schema.Type()
+		if err != nil {
+			send(partitionResult{err: err})
+			return
+		}
+		if arrayType == TILEDB_DENSE {
+			layout = TILEDB_ROW_MAJOR
+		}
+	}
+
+	err = reader.Query().SetLayout(layout)
+	if err == nil {
+		err = addQueryRanges(reader.Query(), r.partitions[i])
+	}
+	if err != nil {
+		send(partitionResult{err: err})
+		return
+	}
+
+	for {
+		batch, err := reader.Next()
+		if err == io.EOF {
+			return
+		}
+		if err == nil {
+			// Copy the batch, the buffers of the reader are reused
+			indexes := make([]uint64, batch.NumCells)
+			for j := range indexes {
+				indexes[j] = uint64(j)
+			}
+			batch, err = batch.Select(nil, indexes)
+		}
+		if !send(partitionResult{batch: batch, err: err}) || err != nil {
+			return
+		}
+	}
+}
+
+// start runs the workers
+func (r *PartitionedReader) start() {
+	r.started = true
+
+	ordered := r.options.Layout != TILEDB_UNORDERED
+	if ordered {
+		r.results = make([]chan partitionResult, len(r.partitions))
+		for i := range r.results {
+			r.results[i] = make(chan partitionResult, 1)
+		}
+	} else {
+		r.results = []chan partitionResult{make(chan partitionResult, r.options.Workers)}
+	}
+
+	// Partitions are handed to the workers in order, so the first partition
+	// not yet returned by Next is always being read
+	jobs := make(chan int, len(r.partitions))
+	for i := range r.partitions {
+		jobs <- i
+	}
+	close(jobs)
+
+	for w := 0; w < r.options.Workers; w++ {
+		r.workers.Add(1)
+		go func() {
+			defer r.workers.Done()
+			for i := range jobs {
+				// Once freed the remaining jobs are drained without reading
+				select {
+				case <-r.done:
+					if ordered {
+						close(r.results[i])
+					}
+					continue
+				default:
+				}
+
+				if ordered {
+					r.readPartition(i, r.results[i])
+					close(r.results[i])
+				} else {
+					r.readPartition(i, r.results[0])
+				}
+			}
+		}()
+	}
+
+	if !ordered {
+		go func() {
+			r.workers.Wait()
+			close(r.results[0])
+		}()
+	}
+}
+
+// Next returns the next batch of results, io.EOF once all partitions have
+// been read. The batches returned remain valid after the next call to Next.
+func (r *PartitionedReader) Next() (*Batch, error) {
+	if !r.started {
+		r.start()
+	}
+
+	for r.current < len(r.results) {
+		result, ok := <-r.results[r.current]
+		if !ok {
+			r.current++
+			continue
+		}
+		if result.err != nil {
+			r.Free()
+			r.current = len(r.results)
+			return nil, result.err
+		}
+		return result.batch, nil
+	}
+	return nil, io.EOF
+}
+
+// ReadAll calls fn for every batch until all partitions have been read
+func (r *PartitionedReader) ReadAll(fn func(batch *Batch) error) error {
+	for {
+		batch, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = fn(batch)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Free stops the workers and waits for the queries running to end, after
+// which the array can be closed. It must be called if the results are not
+// read until io.EOF
+func (r *PartitionedReader) Free() {
+	r.freed.Do(func() {
+		close(r.done)
+	})
+	r.workers.Wait()
+}
+
+// Close stops the workers of the reader, implementing io.Closer
+func (r *PartitionedReader) Close() error {
+	r.Free()
+	return nil
+}
+
+// partitionSubarray splits a subarray into at most n subarrays along tile
+// boundaries of one dimension, chosen according to the layout
+func (a *Array) partitionSubarray(subarray []QueryRange, n int, layout Layout) ([][]QueryRange, error) {
+	schema, err := a.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+
+	// One range per dimension, defaulting to the non empty domain
+	ranges := make([]QueryRange, nDim)
+	found := make([]bool, nDim)
+	for _, r := range subarray {
+		if r.Dimension >= uint32(nDim) {
+			return nil, fmt.Errorf("Invalid dimension index %d", r.Dimension)
+		}
+		if found[r.Dimension] {
+			return nil, fmt.Errorf("More than one range on dimension %d", r.Dimension)
+		}
+		ranges[r.Dimension] = r
+		found[r.Dimension] = true
+	}
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		if found[dimIdx] {
+			continue
+		}
+
+		dimension, err := domain.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return nil, err
+		}
+		defer dimension.Free()
+		cellValNum, err := dimension.CellValNum()
+		if err != nil {
+			return nil, err
+		}
+
+		var nonEmptyDomain *NonEmptyDomain
+		var isEmpty bool
+		if cellValNum == TILEDB_VAR_NUM {
+			nonEmptyDomain, isEmpty, err = a.NonEmptyDomainVarFromIndex(dimIdx)
+		} else {
+			nonEmptyDomain, isEmpty, err = a.NonEmptyDomainFromIndex(dimIdx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isEmpty {
+			return [][]QueryRange{}, nil
+		}
+
+		bounds := reflect.ValueOf(nonEmptyDomain.Bounds)
+		ranges[dimIdx] = QueryRange{
+			Dimension: uint32(dimIdx),
+			Start:     bounds.Index(0).Interface(),
+			End:       bounds.Index(1).Interface(),
+		}
+	}
+
+	// Tiles spanned by the range of each integer dimension. Offsets from the
+	// lower bound of the domain are unsigned, as they may exceed
+	// math.MaxInt64.
+	type tiling struct {
+		lower, start, end   int64
+		extent, first, last uint64
+	}
+	tilings := make([]*tiling, nDim)
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		dimension, err := domain.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return nil, err
+		}
+		defer dimension.Free()
+		datatype, err := dimension.Type()
+		if err != nil {
+			return nil, err
+		}
+		if datatype.IsString() || datatype.IsDatetime() || datatype == TILEDB_FLOAT32 || datatype == TILEDB_FLOAT64 {
+			continue
+		}
+
+		dimensionDomain, err := dimension.Domain()
+		if err != nil {
+			return nil, err
+		}
+		extent, err := dimension.Extent()
+		if err != nil {
+			return nil, err
+		}
+
+		lower, err := integerValue(reflect.ValueOf(dimensionDomain).Index(0))
+		if err != nil {
+			return nil, fmt.Errorf("Can not partition dimension %d: %s", dimIdx, err)
+		}
+		e, err := integerValue(reflect.ValueOf(extent))
+		if err != nil {
+			return nil, fmt.Errorf("Can not partition dimension %d: %s", dimIdx, err)
+		}
+		start, err := integerValue(reflect.ValueOf(ranges[dimIdx].Start))
+		if err != nil {
+			return nil, fmt.Errorf("Range on dimension %d: %s", dimIdx, err)
+		}
+		end, err := integerValue(reflect.ValueOf(ranges[dimIdx].End))
+		if err != nil {
+			return nil, fmt.Errorf("Range on dimension %d: %s", dimIdx, err)
+		}
+		tilings[dimIdx] = &tiling{
+			lower:  lower,
+			start:  start,
+			end:    end,
+			extent: uint64(e),
+			first:  uint64(start-lower) / uint64(e),
+			last:   uint64(end-lower) / uint64(e),
+		}
+	}
+
+	var split uint
+	switch layout {
+	case TILEDB_ROW_MAJOR:
+		split = 0
+	case TILEDB_COL_MAJOR:
+		split = nDim - 1
+	case TILEDB_UNORDERED:
+		for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+			t := tilings[dimIdx]
+			if t != nil && (tilings[split] == nil || t.last-t.first > tilings[split].last-tilings[split].first) {
+				split = dimIdx
+			}
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported layout %d", layout)
+	}
+
+	t := tilings[split]
+	if t == nil {
+		if n > 1 {
+			return nil, fmt.Errorf("Can not split dimension %d, only integer dimensions can be partitioned", split)
+		}
+		return [][]QueryRange{ranges}, nil
+	}
+
+	// The range spans last - first + 1 tiles, at most math.MaxUint64
+	tiles := t.last - t.first
+	if tiles < math.MaxUint64 {
+		tiles++
+	}
+	if uint64(n) > tiles {
+		n = int(tiles)
+	}
+	// tileOffset returns k * tiles / n without overflowing
+	tileOffset := func(k uint64) uint64 {
+		return k*(tiles/uint64(n)) + k*(tiles%uint64(n))/uint64(n)
+	}
+
+	startOffset, endOffset := uint64(t.start-t.lower), uint64(t.end-t.lower)
+	elemType := reflect.TypeOf(ranges[split].Start)
+
+	partitions := make([][]QueryRange, 0, n)
+	for k := uint64(0); k < uint64(n); k++ {
+		firstTile := t.first + tileOffset(k)
+		lastTile := t.first + tileOffset(k+1) - 1
+
+		first := firstTile * t.extent
+		if first < startOffset {
+			first = startOffset
+		}
+		// The end of the last tile may be out of the unsigned range
+		last := endOffset
+		if lastTile+1 <= endOffset/t.extent {
+			last = (lastTile+1)*t.extent - 1
+		}
+		partitionStart := int64(uint64(t.lower) + first)
+		partitionEnd := int64(uint64(t.lower) + last)
+
+		partition := append([]QueryRange{}, ranges...)
+		partition[split] = QueryRange{
+			Dimension: uint32(split),
+			Start:     reflect.ValueOf(partitionStart).Convert(elemType).Interface(),
+			End:       reflect.ValueOf(partitionEnd).Convert(elemType).Interface(),
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions, nil
+}
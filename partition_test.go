@@ -0,0 +1,125 @@
+package tiledb
+
+import (
+	"io"
+	"os"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionedReader(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_partitioned_reader")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	subarray := []QueryRange{
+		{Dimension: 0, Start: int32(1), End: int32(4)},
+		{Dimension: 1, Start: int32(1), End: int32(3)},
+	}
+
+	// The subarray spans two tiles of each dimension
+	reader, err := NewPartitionedReader(context, array, []string{"rows", "a1", "a2"}, subarray, &PartitionOptions{Partitions: 4, BatchSize: 1})
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Equal(t, [][]QueryRange{
+		{{Dimension: 0, Start: int32(1), End: int32(2)}, subarray[1]},
+		{{Dimension: 0, Start: int32(3), End: int32(4)}, subarray[1]},
+	}, reader.Partitions())
+
+	a1 := make([]int32, 0)
+	a2 := make([]string, 0)
+	err = reader.ReadAll(func(batch *Batch) error {
+		for i := uint64(0); i < batch.NumCells; i++ {
+			value, err := batch.Cell("a1", i)
+			assert.Nil(t, err)
+			a1 = append(a1, value.(int32))
+			str, err := batch.Cell("a2", i)
+			assert.Nil(t, err)
+			a2 = append(a2, str.(string))
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, a2)
+
+	// Column major splits the last dimension
+	reader, err = NewPartitionedReader(context, array, nil, subarray, &PartitionOptions{Partitions: 2, Layout: TILEDB_COL_MAJOR})
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Equal(t, QueryRange{Dimension: 1, Start: int32(3), End: int32(3)}, reader.Partitions()[1][1])
+
+	// Unordered results are returned as they are read
+	reader, err = NewPartitionedReader(context, array, []string{"a1"}, nil, &PartitionOptions{Workers: 2, Layout: TILEDB_UNORDERED})
+	assert.Nil(t, err)
+	defer reader.Free()
+	a1 = make([]int32, 0)
+	err = reader.ReadAll(func(batch *Batch) error {
+		data, err := batch.Data("a1")
+		assert.Nil(t, err)
+		a1 = append(a1, data.([]int32)...)
+		return nil
+	})
+	assert.Nil(t, err)
+	sort.Slice(a1, func(i, j int) bool { return a1[i] < a1[j] })
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+
+	// Only one range per dimension
+	_, err = NewPartitionedReader(context, array, nil, append(subarray, subarray[0]), nil)
+	assert.NotNil(t, err)
+}
+
+func TestPartitionedReaderFreeWhileReading(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_partitioned_reader_free")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+
+	subarray := []QueryRange{
+		{Dimension: 0, Start: int32(1), End: int32(4)},
+		{Dimension: 1, Start: int32(1), End: int32(4)},
+	}
+	reader, err := NewPartitionedReader(context, array, []string{"a1"}, subarray, &PartitionOptions{Partitions: 2, Workers: 1, BatchSize: 1})
+	assert.Nil(t, err)
+
+	_, err = reader.Next()
+	assert.Nil(t, err)
+
+	// Free waits for the running query, so the array can be freed right away
+	reader.Free()
+	assert.Nil(t, array.Close())
+	array.Free()
+
+	// Only the batches read before Free are returned, the remaining
+	// partitions are not read
+	for i := 0; i < 3; i++ {
+		_, err = reader.Next()
+		if err != nil {
+			break
+		}
+	}
+	assert.Equal(t, io.EOF, err)
+}
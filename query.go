@@ -69,6 +69,7 @@ func NewQuery(ctx *Context, array *Array) (*Query, error) {
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error creating tiledb query: %s", query.context.LastError())
 	}
+	trackHandle("tiledb_query_t", unsafe.Pointer(query.tiledbQuery))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&query, func(query *Query) {
@@ -87,10 +88,18 @@ func (q *Query) Free() {
 	q.buffers = nil
 	q.resultBufferElements = nil
 	if q.tiledbQuery != nil {
+		untrackHandle(unsafe.Pointer(q.tiledbQuery))
 		C.tiledb_query_free(&q.tiledbQuery)
 	}
 }
 
+// Close frees the query and releases its buffers, implementing io.Closer.
+// Closing twice is a no-op.
+func (q *Query) Close() error {
+	q.Free()
+	return nil
+}
+
 // SetSubArray Sets a subarray, defined in the order dimensions were added.
 // Coordinates are inclusive. For the case of writes, this is meaningful only
 // for dense arrays, and specifically dense writes.
@@ -106,11 +115,13 @@ func (q *Query) SetSubArray(subArray interface{}) error {
 	if err != nil {
 		return fmt.Errorf("Could not get array schema from query array: %s", err)
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
 		return fmt.Errorf("Could not get domain from array schema: %s", err)
 	}
+	defer domain.Free()
 
 	domainType, err := domain.Type()
 	if err != nil {
@@ -226,6 +237,7 @@ func (q *Query) SetBuffer(attributeOrDimension string, buffer interface{}) (*uin
 			"Could not get array schema for SetBuffer: %s",
 			err)
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
@@ -233,6 +245,7 @@ func (q *Query) SetBuffer(attributeOrDimension string, buffer interface{}) (*uin
 			"Could not get domain for SetBuffer: %s",
 			attributeOrDimension)
 	}
+	defer domain.Free()
 
 	var attributeOrDimensionType Datatype
 	// If we are setting tiledb coordinates for a sparse array we want to check
@@ -257,6 +270,7 @@ func (q *Query) SetBuffer(attributeOrDimension string, buffer interface{}) (*uin
 				return nil, fmt.Errorf("Could not get attribute or dimension for SetBuffer: %s",
 					attributeOrDimension)
 			}
+			defer dimension.Free()
 
 			attributeOrDimensionType, err = dimension.Type()
 			if err != nil {
@@ -269,6 +283,7 @@ func (q *Query) SetBuffer(attributeOrDimension string, buffer interface{}) (*uin
 				return nil, fmt.Errorf("Could not get attribute %s for SetBuffer",
 					attributeOrDimension)
 			}
+			defer schemaAttribute.Free()
 
 			attributeOrDimensionType, err = schemaAttribute.Type()
 			if err != nil {
@@ -620,18 +635,21 @@ func (q *Query) GetRange(dimIdx uint32, rangeNum uint64) (interface{}, interface
 	if err != nil {
 		return nil, nil, err
 	}
+	defer schema.Free()
 
 	// Get the domain object
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, nil, err
 	}
+	defer domain.Free()
 
 	// Use the index to retrieve the dimension object
 	dimension, err := domain.DimensionFromIndex(uint(dimIdx))
 	if err != nil {
 		return nil, nil, err
 	}
+	defer dimension.Free()
 
 	// Finally get the dimension's type
 	datatype, err := dimension.Type()
@@ -743,12 +761,14 @@ func (q *Query) GetRanges() (map[string][]RangeLimits, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer schema.Free()
 
 	// Get the domain object
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, err
 	}
+	defer domain.Free()
 
 	// Use the index to retrieve the dimension object
 	nDim, err := domain.NDim()
@@ -765,6 +785,7 @@ func (q *Query) GetRanges() (map[string][]RangeLimits, error) {
 		if err != nil {
 			return nil, err
 		}
+		defer dimension.Free()
 		// Get name from dimension
 		name, err := dimension.Name()
 		if err != nil {
@@ -819,6 +840,7 @@ func (q *Query) Buffer(attributeOrDimension string) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
@@ -826,6 +848,7 @@ func (q *Query) Buffer(attributeOrDimension string) (interface{}, error) {
 			"Could not get domain from array schema for Buffer: %s",
 			err)
 	}
+	defer domain.Free()
 
 	if attributeOrDimension == TILEDB_COORDS {
 		datatype, err = domain.Type()
@@ -843,6 +866,7 @@ func (q *Query) Buffer(attributeOrDimension string) (interface{}, error) {
 			if err != nil {
 				return nil, fmt.Errorf("Could not get attribute or dimension for SetBuffer: %s", attributeOrDimension)
 			}
+			defer dimension.Free()
 
 			datatype, err = dimension.Type()
 			if err != nil {
@@ -853,6 +877,7 @@ func (q *Query) Buffer(attributeOrDimension string) (interface{}, error) {
 			if err != nil {
 				return nil, fmt.Errorf("Could not get attribute %s for Buffer", attributeOrDimension)
 			}
+			defer attribute.Free()
 
 			datatype, err = attribute.Type()
 			if err != nil {
@@ -1011,6 +1036,7 @@ func (q *Query) SetBufferVar(attributeOrDimension string, offset []uint64, buffe
 			"Could not get array schema for SetBuffer: %s",
 			err)
 	}
+	defer schema.Free()
 
 	var attributeOrDimensionType Datatype
 
@@ -1020,6 +1046,7 @@ func (q *Query) SetBufferVar(attributeOrDimension string, offset []uint64, buffe
 			"Could not get domain from array schema for SetBufferVar: %s",
 			err)
 	}
+	defer domain.Free()
 
 	hasDim, err := domain.HasDimension(attributeOrDimension)
 	if err != nil {
@@ -1032,6 +1059,7 @@ func (q *Query) SetBufferVar(attributeOrDimension string, offset []uint64, buffe
 			return nil, nil, fmt.Errorf("Could not get attribute or dimension for SetBufferVar: %s",
 				attributeOrDimension)
 		}
+		defer dimension.Free()
 		attributeOrDimensionType, err = dimension.Type()
 		if err != nil {
 			return nil, nil, fmt.Errorf("Could not get dimensionType for SetBufferVar: %s",
@@ -1043,6 +1071,7 @@ func (q *Query) SetBufferVar(attributeOrDimension string, offset []uint64, buffe
 			return nil, nil, fmt.Errorf("Could not get attribute %s SetBufferVar",
 				attributeOrDimension)
 		}
+		defer schemaAttribute.Free()
 
 		attributeOrDimensionType, err = schemaAttribute.Type()
 		if err != nil {
@@ -1233,11 +1262,13 @@ func (q *Query) ResultBufferElements() (map[string][2]uint64, error) {
 	if err != nil {
 		return nil, fmt.Errorf("Could not get schema for ResultBufferElements: %s", err)
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, fmt.Errorf("Could not get domain for ResultBufferElements: %s", err)
 	}
+	defer domain.Free()
 
 	var datatype Datatype
 	for attributeOrDimension, v := range q.resultBufferElements {
@@ -1272,6 +1303,7 @@ func (q *Query) ResultBufferElements() (map[string][2]uint64, error) {
 				if err != nil {
 					return nil, fmt.Errorf("Could not get attribute or dimension for SetBuffer: %s", attributeOrDimension)
 				}
+				defer dimension.Free()
 
 				datatype, err = dimension.Type()
 				if err != nil {
@@ -1283,6 +1315,7 @@ func (q *Query) ResultBufferElements() (map[string][2]uint64, error) {
 				if err != nil {
 					return nil, fmt.Errorf("Could not get attribute %s for ResultBufferElements: %s", attributeOrDimension, err)
 				}
+				defer attribute.Free()
 
 				// Get datatype size to convert byte lengths to needed buffer sizes
 				datatype, err = attribute.Type()
@@ -1308,6 +1341,7 @@ func (q *Query) BufferVar(attributeOrDimension string) ([]uint64, interface{}, e
 	if err != nil {
 		return nil, nil, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
@@ -1315,6 +1349,7 @@ func (q *Query) BufferVar(attributeOrDimension string) ([]uint64, interface{}, e
 			"Could not get domain from array schema for BufferVar: %s",
 			err)
 	}
+	defer domain.Free()
 
 	if attributeOrDimension == TILEDB_COORDS {
 		datatype, err = domain.Type()
@@ -1332,6 +1367,7 @@ func (q *Query) BufferVar(attributeOrDimension string) ([]uint64, interface{}, e
 			if err != nil {
 				return nil, nil, fmt.Errorf("Could not get attribute or dimension for BufferVar: %s", attributeOrDimension)
 			}
+			defer dimension.Free()
 
 			datatype, err = dimension.Type()
 			if err != nil {
@@ -1342,6 +1378,7 @@ func (q *Query) BufferVar(attributeOrDimension string) ([]uint64, interface{}, e
 			if err != nil {
 				return nil, nil, fmt.Errorf("Could not get attribute for BufferVar: %s", attributeOrDimension)
 			}
+			defer attribute.Free()
 
 			datatype, err = attribute.Type()
 			if err != nil {
@@ -1504,6 +1541,7 @@ func (q *Query) BufferSizeVar(attributeOrDimension string) (uint64, uint64, erro
 	if err != nil {
 		return 0, 0, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
@@ -1511,6 +1549,7 @@ func (q *Query) BufferSizeVar(attributeOrDimension string) (uint64, uint64, erro
 			"Could not get domain from array schema for BufferSizeVar: %s",
 			err)
 	}
+	defer domain.Free()
 
 	if attributeOrDimension == TILEDB_COORDS {
 		datatype, err = domain.Type()
@@ -1528,6 +1567,7 @@ func (q *Query) BufferSizeVar(attributeOrDimension string) (uint64, uint64, erro
 			if err != nil {
 				return 0, 0, fmt.Errorf("Could not get attribute or dimension for BufferSizeVar: %s", attributeOrDimension)
 			}
+			defer dimension.Free()
 
 			datatype, err = dimension.Type()
 			if err != nil {
@@ -1538,6 +1578,7 @@ func (q *Query) BufferSizeVar(attributeOrDimension string) (uint64, uint64, erro
 			if err != nil {
 				return 0, 0, fmt.Errorf("Could not get attribute %s for BufferSizeVar", attributeOrDimension)
 			}
+			defer attribute.Free()
 
 			datatype, err = attribute.Type()
 			if err != nil {
@@ -1586,6 +1627,7 @@ func (q *Query) BufferSize(attributeNameOrDimension string) (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
+	defer schema.Free()
 
 	domain, err := schema.Domain()
 	if err != nil {
@@ -1593,6 +1635,7 @@ func (q *Query) BufferSize(attributeNameOrDimension string) (uint64, error) {
 			"Could not get domain from array schema for BufferSize: %s",
 			err)
 	}
+	defer domain.Free()
 
 	if attributeNameOrDimension == TILEDB_COORDS {
 		datatype, err = domain.Type()
@@ -1610,6 +1653,7 @@ func (q *Query) BufferSize(attributeNameOrDimension string) (uint64, error) {
 			if err != nil {
 				return 0, fmt.Errorf("Could not get attribute or dimension for BufferSize: %s", attributeNameOrDimension)
 			}
+			defer dimension.Free()
 
 			datatype, err = dimension.Type()
 			if err != nil {
@@ -1620,6 +1664,7 @@ func (q *Query) BufferSize(attributeNameOrDimension string) (uint64, error) {
 			if err != nil {
 				return 0, err
 			}
+			defer attribute.Free()
 
 			datatype, err = attribute.Type()
 			if err != nil {
@@ -0,0 +1,227 @@
+package tiledb
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// rangeDimension describes a dimension ranges are added on
+type rangeDimension struct {
+	index    uint32
+	datatype Datatype
+	isVar    bool
+	elemType reflect.Type
+	// domain is the [lower, upper] domain of fixed sized dimensions, the
+	// zero Value if it is not available
+	domain reflect.Value
+	ranges []QueryRange
+}
+
+/*
+RangeBuilder collects ranges on the dimensions of an array by name,
+validating them against the type and domain of each dimension. Overlapping
+and adjacent ranges on a dimension are merged before being applied to a
+query.
+
+	builder, err := NewRangeBuilder(schema)
+	err = builder.Add("rows", int32(1), int32(4))
+	err = builder.Add("rows", int32(3), int32(8))
+	err = builder.Add("name", "a", "c")
+	// Adds rows [1, 8] and name ["a", "c"]
+	err = builder.Apply(query)
+*/
+type RangeBuilder struct {
+	names      map[string]int
+	dimensions []*rangeDimension
+}
+
+// NewRangeBuilder creates an empty RangeBuilder for the dimensions of an
+// array schema
+func NewRangeBuilder(schema *ArraySchema) (*RangeBuilder, error) {
+	domain, err := schema.Domain()
+	if err != nil {
+		return nil, err
+	}
+	defer domain.Free()
+	nDim, err := domain.NDim()
+	if err != nil {
+		return nil, err
+	}
+
+	builder := RangeBuilder{names: make(map[string]int)}
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		dimension, err := domain.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return nil, err
+		}
+		defer dimension.Free()
+		name, err := dimension.Name()
+		if err != nil {
+			return nil, err
+		}
+		datatype, err := dimension.Type()
+		if err != nil {
+			return nil, err
+		}
+		cellValNum, err := dimension.CellValNum()
+		if err != nil {
+			return nil, err
+		}
+
+		d := rangeDimension{index: uint32(dimIdx), datatype: datatype, isVar: cellValNum == TILEDB_VAR_NUM}
+		if !d.isVar {
+			slice, _, err := datatype.MakeSlice(1)
+			if err != nil {
+				return nil, err
+			}
+			d.elemType = reflect.TypeOf(slice).Elem()
+
+			if datatype.IsDatetime() {
+				cdomain, err := dimension.typedDomain(reflect.Int64)
+				if err != nil {
+					return nil, err
+				}
+				bounds := (*[2]int64)(cdomain)
+				d.domain = reflect.ValueOf([]int64{bounds[0], bounds[1]})
+			} else {
+				dimensionDomain, err := dimension.Domain()
+				if err != nil {
+					return nil, err
+				}
+				d.domain = reflect.ValueOf(dimensionDomain)
+			}
+		}
+
+		builder.names[name] = len(builder.dimensions)
+		builder.dimensions = append(builder.dimensions, &d)
+	}
+
+	return &builder, nil
+}
+
+// rangeBound converts a bound to a comparable value, strings for variable
+// sized dimensions
+func (d *rangeDimension) rangeBound(bound interface{}) (interface{}, error) {
+	if d.isVar {
+		switch b := bound.(type) {
+		case string:
+			return b, nil
+		case []byte:
+			return string(b), nil
+		default:
+			return nil, fmt.Errorf("expected a string or []byte, got %T", bound)
+		}
+	}
+
+	if reflect.TypeOf(bound) != d.elemType {
+		return nil, fmt.Errorf("expected a %s, got %T", d.elemType, bound)
+	}
+	if isNaN(bound) {
+		return nil, fmt.Errorf("NaN is not a valid bound")
+	}
+	return bound, nil
+}
+
+// Add adds the range [start, end] on the dimension name. Bounds must have the
+// Go type of the dimension, int64 for datetime dimensions, variable sized
+// dimensions take a string or []byte. Ranges outside of the domain of the
+// dimension and NaN bounds are rejected.
+func (b *RangeBuilder) Add(name string, start interface{}, end interface{}) error {
+	index, ok := b.names[name]
+	if !ok {
+		return fmt.Errorf("Error adding range: %s is not a dimension of the array", name)
+	}
+	d := b.dimensions[index]
+
+	startBound, err := d.rangeBound(start)
+	if err != nil {
+		return fmt.Errorf("Error adding range on %s: invalid start, %s", name, err)
+	}
+	endBound, err := d.rangeBound(end)
+	if err != nil {
+		return fmt.Errorf("Error adding range on %s: invalid end, %s", name, err)
+	}
+
+	c, err := CompareValues(startBound, endBound)
+	if err != nil {
+		return fmt.Errorf("Error adding range on %s: %s", name, err)
+	}
+	if c > 0 {
+		return fmt.Errorf("Error adding range on %s: start %v is greater than end %v", name, start, end)
+	}
+
+	if d.domain.IsValid() {
+		lower, upper := d.domain.Index(0).Interface(), d.domain.Index(1).Interface()
+		cLower, _ := CompareValues(startBound, lower)
+		cUpper, _ := CompareValues(endBound, upper)
+		if cLower < 0 || cUpper > 0 {
+			return fmt.Errorf("Error adding range on %s: [%v, %v] is not within the domain [%v, %v]", name, start, end, lower, upper)
+		}
+	}
+
+	d.ranges = append(d.ranges, QueryRange{Dimension: d.index, Start: startBound, End: endBound})
+	return nil
+}
+
+// adjacent returns true if two ranges of an integer dimension can be merged
+// because the second starts right after the end of the first
+func (d *rangeDimension) adjacent(end, start interface{}) bool {
+	e, s := reflect.ValueOf(end), reflect.ValueOf(start)
+	switch e.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.Int() != math.MaxInt64 && e.Int()+1 == s.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return e.Uint() != math.MaxUint64 && e.Uint()+1 == s.Uint()
+	default:
+		return false
+	}
+}
+
+// coalesce sorts the ranges of a dimension by start and merges overlapping
+// and adjacent ranges
+func (d *rangeDimension) coalesce() []QueryRange {
+	ranges := append([]QueryRange{}, d.ranges...)
+	sort.SliceStable(ranges, func(i, j int) bool {
+		c, _ := CompareValues(ranges[i].Start, ranges[j].Start)
+		return c < 0
+	})
+
+	merged := make([]QueryRange, 0, len(ranges))
+	for _, r := range ranges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			c, _ := CompareValues(r.Start, last.End)
+			if c <= 0 || d.adjacent(last.End, r.Start) {
+				if c, _ := CompareValues(r.End, last.End); c > 0 {
+					last.End = r.End
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Ranges returns the coalesced ranges of all dimensions, ordered by
+// dimension and start. Bounds of variable sized dimensions are []byte.
+func (b *RangeBuilder) Ranges() []QueryRange {
+	ranges := make([]QueryRange, 0)
+	for _, d := range b.dimensions {
+		for _, r := range d.coalesce() {
+			if d.isVar {
+				r.Start = []byte(r.Start.(string))
+				r.End = []byte(r.End.(string))
+			}
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// Apply adds the coalesced ranges to a query
+func (b *RangeBuilder) Apply(query *Query) error {
+	return addQueryRanges(query, b.Ranges())
+}
@@ -0,0 +1,85 @@
+package tiledb
+
+import (
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeBuilder(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_range_builder")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	schema, err := array.Schema()
+	assert.Nil(t, err)
+	defer schema.Free()
+
+	builder, err := NewRangeBuilder(schema)
+	assert.Nil(t, err)
+
+	// Invalid dimension, type and bounds
+	assert.NotNil(t, builder.Add("a1", int32(1), int32(1)))
+	assert.NotNil(t, builder.Add("rows", int64(1), int64(1)))
+	assert.NotNil(t, builder.Add("rows", int32(3), int32(2)))
+	assert.NotNil(t, builder.Add("rows", int32(0), int32(2)))
+	assert.NotNil(t, builder.Add("cols", int32(3), int32(5)))
+
+	// Overlapping and adjacent ranges are merged
+	assert.Nil(t, builder.Add("cols", int32(1), int32(1)))
+	assert.Nil(t, builder.Add("rows", int32(3), int32(4)))
+	assert.Nil(t, builder.Add("rows", int32(1), int32(1)))
+	assert.Nil(t, builder.Add("rows", int32(3), int32(3)))
+	assert.Nil(t, builder.Add("cols", int32(2), int32(2)))
+	assert.Equal(t, []QueryRange{
+		{Dimension: 0, Start: int32(1), End: int32(1)},
+		{Dimension: 0, Start: int32(3), End: int32(4)},
+		{Dimension: 1, Start: int32(1), End: int32(2)},
+	}, builder.Ranges())
+
+	query, err := NewQuery(context, array)
+	assert.Nil(t, err)
+	defer query.Free()
+	assert.Nil(t, builder.Apply(query))
+
+	numRanges, err := query.GetRangeNum(0)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), *numRanges)
+	numRanges, err = query.GetRangeNum(1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), *numRanges)
+}
+
+func TestRangeBuilderNaN(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	x, err := NewDimension(context, "x", []float64{0, 10}, float64(5))
+	assert.Nil(t, err)
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(x))
+	schema, err := NewArraySchema(context, TILEDB_SPARSE)
+	assert.Nil(t, err)
+	assert.Nil(t, schema.SetDomain(domain))
+
+	builder, err := NewRangeBuilder(schema)
+	assert.Nil(t, err)
+	assert.NotNil(t, builder.Add("x", math.NaN(), float64(1)))
+	assert.NotNil(t, builder.Add("x", float64(1), math.NaN()))
+	assert.Nil(t, builder.Add("x", float64(1), float64(2)))
+}
@@ -0,0 +1,401 @@
+package tiledb
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MigrateOptions configures MigrateArray
+type MigrateOptions struct {
+	// Defaults holds the value of every cell of the attributes of the new
+	// schema which do not exist in the old array, keyed by attribute name.
+	// A value is a single value of the Go type of the attribute datatype, a
+	// slice of them for attributes with several values per cell, or a string
+	// for string attributes.
+	Defaults map[string]interface{}
+	// EncryptionType and Key open an encrypted array, the migrated array is
+	// encrypted with the same key. If not set, the key provider of the
+	// context is used.
+	EncryptionType EncryptionType
+	Key            string
+	// BatchSize is the number of cells read and written at once,
+	// DefaultBatchSize if 0
+	BatchSize uint64
+	// BackupURI, if set, is where the old array is moved to. Otherwise it is
+	// removed once the data has been migrated.
+	BackupURI string
+	// Progress, if set, is called after each batch of cells migrated
+	Progress func(progress CopyProgress)
+}
+
+// isNumericDatatype returns true for the integer and floating point datatypes
+func isNumericDatatype(datatype Datatype) bool {
+	switch datatype {
+	case TILEDB_INT8, TILEDB_INT16, TILEDB_INT32, TILEDB_INT64,
+		TILEDB_UINT8, TILEDB_UINT16, TILEDB_UINT32, TILEDB_UINT64,
+		TILEDB_FLOAT32, TILEDB_FLOAT64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSafeConversion returns true if all values of a datatype are represented
+// exactly by another: integers to wider integers, unsigned integers to wider
+// signed integers, integers to floats with a wider mantissa and float32 to
+// float64. Other datatypes must be identical.
+func isSafeConversion(from Datatype, to Datatype) bool {
+	if from == to {
+		return true
+	}
+	if !isNumericDatatype(from) || !isNumericDatatype(to) {
+		return false
+	}
+
+	isFloat := func(d Datatype) bool { return d == TILEDB_FLOAT32 || d == TILEDB_FLOAT64 }
+	isSigned := func(d Datatype) bool {
+		switch d {
+		case TILEDB_INT8, TILEDB_INT16, TILEDB_INT32, TILEDB_INT64:
+			return true
+		default:
+			return false
+		}
+	}
+
+	switch {
+	case isFloat(from):
+		return isFloat(to) && to.Size() > from.Size()
+	case isFloat(to):
+		// 8 and 16 bit integers fit the 24 bit mantissa of float32, 32 bit
+		// integers the 53 bit mantissa of float64
+		return to.Size() > from.Size()
+	case isSigned(from) == isSigned(to):
+		return to.Size() > from.Size()
+	case isSigned(to):
+		return to.Size() > from.Size()
+	default:
+		return false
+	}
+}
+
+// elemType returns the Go type values of a datatype are stored as
+func elemType(datatype Datatype) (reflect.Type, error) {
+	slice, _, err := datatype.MakeSlice(1)
+	if err != nil {
+		return nil, err
+	}
+	return reflect.TypeOf(slice).Elem(), nil
+}
+
+// defaultCell validates the default value of a field and returns the values
+// of one cell
+func defaultCell(field BatchField, value interface{}) (reflect.Value, error) {
+	elem, err := elemType(field.Datatype)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	v := reflect.ValueOf(value)
+	if s, ok := value.(string); ok && field.Datatype.IsString() {
+		v = reflect.ValueOf([]byte(s))
+	}
+	if !v.IsValid() {
+		return reflect.Value{}, fmt.Errorf("Default value of %s is nil", field.Name)
+	}
+	if v.Kind() != reflect.Slice {
+		if v.Type() != elem {
+			return reflect.Value{}, fmt.Errorf("Default value of %s must be of type %s, got %T", field.Name, elem, value)
+		}
+		v = reflect.Append(reflect.MakeSlice(reflect.SliceOf(elem), 0, 1), v)
+	}
+	if v.Type().Elem() != elem {
+		return reflect.Value{}, fmt.Errorf("Default value of %s must be of type []%s, got %T", field.Name, elem, value)
+	}
+	if v.Len() == 0 {
+		return reflect.Value{}, fmt.Errorf("Default value of %s is empty", field.Name)
+	}
+	if !field.IsVar() && uint(v.Len()) != field.CellValNum {
+		return reflect.Value{}, fmt.Errorf("Default value of %s must have %d values, got %d", field.Name, field.CellValNum, v.Len())
+	}
+	return v, nil
+}
+
+// fieldTransform returns the data (and offsets if variable sized) of one
+// field of the migrated array from a batch of the old array
+type fieldTransform func(batch *Batch) (interface{}, []uint64)
+
+// defaultTransform repeats the values of a default cell for all cells of a
+// batch
+func defaultTransform(field BatchField, cell reflect.Value) fieldTransform {
+	size := uint64(cell.Len()) * field.Datatype.Size()
+	return func(batch *Batch) (interface{}, []uint64) {
+		data := reflect.MakeSlice(cell.Type(), 0, cell.Len()*int(batch.NumCells))
+		var offsets []uint64
+		if field.IsVar() {
+			offsets = make([]uint64, batch.NumCells)
+		}
+		for i := uint64(0); i < batch.NumCells; i++ {
+			data = reflect.AppendSlice(data, cell)
+			if offsets != nil {
+				offsets[i] = i * size
+			}
+		}
+		return data.Interface(), offsets
+	}
+}
+
+// convertTransform converts the values of a fixed sized field to another
+// numeric type
+func convertTransform(name string, elem reflect.Type) fieldTransform {
+	return func(batch *Batch) (interface{}, []uint64) {
+		values := reflect.ValueOf(batch.data[name])
+		data := reflect.MakeSlice(reflect.SliceOf(elem), values.Len(), values.Len())
+		for i := 0; i < values.Len(); i++ {
+			data.Index(i).Set(values.Index(i).Convert(elem))
+		}
+		return data.Interface(), nil
+	}
+}
+
+// migrateTransform returns the transform writing the fields of schema from
+// batches of the fields of an old schema. Fields are mapped by name and
+// converted if safe, new attributes are set to their default value.
+// Dimensions are not written to dense arrays.
+func migrateTransform(old *ArraySchema, schema *ArraySchema, defaults map[string]interface{}) (batchTransform, error) {
+	oldFields, err := old.Fields()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]BatchField)
+	for _, field := range oldFields {
+		byName[field.Name] = field
+	}
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return nil, err
+	}
+	fields, err := schema.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	transforms := make(map[string]fieldTransform)
+	for _, field := range fields {
+		if field.IsDimension && arrayType == TILEDB_DENSE {
+			continue
+		}
+		name := field.Name
+
+		oldField, ok := byName[name]
+		if !ok {
+			if field.IsDimension {
+				return nil, fmt.Errorf("Dimension %s does not exist in the old array", name)
+			}
+			value, ok := defaults[name]
+			if !ok {
+				return nil, fmt.Errorf("No default value for the new attribute %s", name)
+			}
+			cell, err := defaultCell(field, value)
+			if err != nil {
+				return nil, err
+			}
+			transforms[name] = defaultTransform(field, cell)
+			continue
+		}
+
+		if oldField.IsDimension != field.IsDimension {
+			return nil, fmt.Errorf("%s can not be converted between an attribute and a dimension", name)
+		}
+		if oldField.CellValNum != field.CellValNum {
+			return nil, fmt.Errorf("%s has a different number of values per cell in the old array", name)
+		}
+		if !isSafeConversion(oldField.Datatype, field.Datatype) || (field.IsVar() && oldField.Datatype != field.Datatype) {
+			return nil, fmt.Errorf("%s can not be safely converted from %s to %s",
+				name, oldField.Datatype.String(), field.Datatype.String())
+		}
+
+		if oldField.Datatype == field.Datatype {
+			transforms[name] = func(batch *Batch) (interface{}, []uint64) {
+				return batch.data[name], batch.offsets[name]
+			}
+			continue
+		}
+		elem, err := elemType(field.Datatype)
+		if err != nil {
+			return nil, err
+		}
+		transforms[name] = convertTransform(name, elem)
+	}
+
+	return func(batch *Batch) (map[string]interface{}, map[string][]uint64, error) {
+		data := make(map[string]interface{})
+		offsets := make(map[string][]uint64)
+		for name, transform := range transforms {
+			values, valueOffsets := transform(batch)
+			data[name] = values
+			if valueOffsets != nil {
+				offsets[name] = valueOffsets
+			}
+		}
+		return data, offsets, nil
+	}, nil
+}
+
+// migrateCells creates the array at dstURI with schema and migrates the
+// cells and metadata of the array at srcURI into it
+func migrateCells(ctx *Context, srcURI string, dstURI string, schema *ArraySchema, opts MigrateOptions) (err error) {
+	src, err := openArray(ctx, srcURI, TILEDB_READ, opts.EncryptionType, opts.Key)
+	if err != nil {
+		return err
+	}
+	defer src.Free()
+
+	oldSchema, err := src.Schema()
+	if err != nil {
+		return err
+	}
+	defer oldSchema.Free()
+	transform, err := migrateTransform(oldSchema, schema, opts.Defaults)
+	if err != nil {
+		return err
+	}
+
+	dst, err := NewArray(ctx, dstURI)
+	if err != nil {
+		return err
+	}
+	defer dst.Free()
+
+	if opts.Key != "" {
+		err = dst.CreateWithKey(schema, opts.EncryptionType, opts.Key)
+	} else {
+		err = dst.Create(schema)
+	}
+	if err != nil {
+		return err
+	}
+	// The caller removes the destination on error, it must be closed first
+	opened := false
+	defer func() {
+		if err != nil && opened {
+			dst.Close()
+		}
+	}()
+
+	if opts.Key != "" {
+		err = dst.OpenWithKey(TILEDB_WRITE, opts.EncryptionType, opts.Key)
+	} else {
+		err = dst.Open(TILEDB_WRITE)
+	}
+	if err != nil {
+		return err
+	}
+	opened = true
+
+	var progress CopyProgress
+	err = rewriteCells(ctx, src, dst, opts.BatchSize, transform, func(cells uint64) {
+		progress.CellsCopied += cells
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = copyMetadata(src, dst)
+	if err != nil {
+		return err
+	}
+	return dst.Close()
+}
+
+/*
+MigrateArray changes the schema of the array at uri. As schemas can not be
+evolved, a new array is created next to the old one with the new schema, the
+cells and metadata are copied into it and it replaces the old array.
+
+Attributes and dimensions are mapped by name. Attributes missing from the
+new schema are dropped, new attributes are set to the value given in
+options.Defaults. Values are converted to the datatype of the new schema if
+all values can be represented exactly (e.g. int32 to int64 or float64),
+otherwise an error is returned before anything is written.
+
+	err = MigrateArray(ctx, "data/array", schema, &MigrateOptions{
+		Defaults: map[string]interface{}{"quality": float64(1)},
+	})
+*/
+func MigrateArray(ctx *Context, uri string, schema *ArraySchema, options *MigrateOptions) error {
+	var opts MigrateOptions
+	if options != nil {
+		opts = *options
+	}
+	if opts.Key == "" {
+		// The migrated array is written to another URI, keep the key the
+		// key provider returns for the array
+		var err error
+		opts.EncryptionType, opts.Key, err = ctx.encryptionKey(uri)
+		if err != nil {
+			return fmt.Errorf("Error migrating array %s: %s", uri, err)
+		}
+	}
+
+	err := replaceArray(ctx, uri, opts.BackupURI, func(tmpURI string) error {
+		return migrateCells(ctx, uri, tmpURI, schema, opts)
+	})
+	if err != nil {
+		return fmt.Errorf("Error migrating array %s: %s", uri, err)
+	}
+	return nil
+}
+
+// replaceArray calls write to write a new array next to the array at uri,
+// and replaces the array with it. The old array is moved to backupURI if set,
+// removed otherwise. The new array is removed if write fails.
+func replaceArray(ctx *Context, uri string, backupURI string, write func(tmpURI string) error) error {
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+	defer config.Free()
+
+	vfs, err := NewVFS(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer vfs.Free()
+
+	tmpURI := strings.TrimSuffix(uri, "/") + "_migration"
+	isDir, err := vfs.IsDir(tmpURI)
+	if err != nil {
+		return err
+	}
+	if isDir {
+		return fmt.Errorf("%s already exists", tmpURI)
+	}
+
+	err = write(tmpURI)
+	if err != nil {
+		if isDir, _ := vfs.IsDir(tmpURI); isDir {
+			vfs.RemoveDir(tmpURI)
+		}
+		return err
+	}
+
+	if backupURI != "" {
+		err = vfs.MoveDir(uri, backupURI)
+	} else {
+		err = vfs.RemoveDir(uri)
+	}
+	if err != nil {
+		return fmt.Errorf("the new array is at %s: %s", tmpURI, err)
+	}
+
+	err = vfs.MoveDir(tmpURI, uri)
+	if err != nil {
+		return fmt.Errorf("the new array is at %s: %s", tmpURI, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createMigrationSchema returns the schema of createBatchTestArray with a1 of
+// type a1Type, a3 dropped and the new attributes a4 and a5
+func createMigrationSchema(t *testing.T, context *Context, a1Type Datatype) *ArraySchema {
+	rows, err := NewDimension(context, "rows", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+	cols, err := NewDimension(context, "cols", []int32{1, 4}, int32(2))
+	assert.Nil(t, err)
+
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(rows, cols))
+
+	arraySchema, err := NewArraySchema(context, TILEDB_SPARSE)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.SetDomain(domain))
+	assert.Nil(t, arraySchema.SetCellOrder(TILEDB_ROW_MAJOR))
+	assert.Nil(t, arraySchema.SetTileOrder(TILEDB_ROW_MAJOR))
+
+	a1, err := NewAttribute(context, "a1", a1Type)
+	assert.Nil(t, err)
+	a2, err := NewAttribute(context, "a2", TILEDB_STRING_UTF8)
+	assert.Nil(t, err)
+	assert.Nil(t, a2.SetCellValNum(TILEDB_VAR_NUM))
+	a4, err := NewAttribute(context, "a4", TILEDB_FLOAT64)
+	assert.Nil(t, err)
+	a5, err := NewAttribute(context, "a5", TILEDB_STRING_UTF8)
+	assert.Nil(t, err)
+	assert.Nil(t, a5.SetCellValNum(TILEDB_VAR_NUM))
+	assert.Nil(t, arraySchema.AddAttributes(a1, a2, a4, a5))
+	return arraySchema
+}
+
+func TestIsSafeConversion(t *testing.T) {
+	assert.True(t, isSafeConversion(TILEDB_INT32, TILEDB_INT32))
+	assert.True(t, isSafeConversion(TILEDB_INT32, TILEDB_INT64))
+	assert.True(t, isSafeConversion(TILEDB_UINT16, TILEDB_INT32))
+	assert.True(t, isSafeConversion(TILEDB_INT16, TILEDB_FLOAT32))
+	assert.True(t, isSafeConversion(TILEDB_UINT32, TILEDB_FLOAT64))
+	assert.True(t, isSafeConversion(TILEDB_FLOAT32, TILEDB_FLOAT64))
+
+	assert.False(t, isSafeConversion(TILEDB_INT64, TILEDB_INT32))
+	assert.False(t, isSafeConversion(TILEDB_INT32, TILEDB_UINT64))
+	assert.False(t, isSafeConversion(TILEDB_UINT32, TILEDB_INT32))
+	assert.False(t, isSafeConversion(TILEDB_INT32, TILEDB_FLOAT32))
+	assert.False(t, isSafeConversion(TILEDB_FLOAT64, TILEDB_FLOAT32))
+	assert.False(t, isSafeConversion(TILEDB_FLOAT32, TILEDB_INT64))
+	assert.False(t, isSafeConversion(TILEDB_DATETIME_DAY, TILEDB_DATETIME_MS))
+	assert.False(t, isSafeConversion(TILEDB_STRING_ASCII, TILEDB_UINT8))
+}
+
+func TestMigrateArray(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_migrate_array")
+	tmpBackupPath := path.Join(os.TempDir(), "tiledb_test_migrate_array_backup")
+	for _, p := range []string{tmpArrayPath, tmpBackupPath, tmpArrayPath + "_migration"} {
+		defer os.RemoveAll(p)
+		if _, err = os.Stat(p); err == nil {
+			os.RemoveAll(p)
+		}
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, array.PutMetadata("source", "sensor"))
+	assert.Nil(t, array.Close())
+
+	defaults := map[string]interface{}{"a4": float64(0.5), "a5": "n/a"}
+
+	// Narrowing a1 is rejected before anything is written
+	err = MigrateArray(context, tmpArrayPath, createMigrationSchema(t, context, TILEDB_INT16), &MigrateOptions{
+		Defaults: defaults,
+	})
+	assert.NotNil(t, err)
+	_, err = os.Stat(tmpArrayPath + "_migration")
+	assert.True(t, os.IsNotExist(err))
+
+	// New attributes need a default value
+	err = MigrateArray(context, tmpArrayPath, createMigrationSchema(t, context, TILEDB_INT64), &MigrateOptions{
+		Defaults: map[string]interface{}{"a4": float64(0.5)},
+	})
+	assert.NotNil(t, err)
+
+	var migrated CopyProgress
+	err = MigrateArray(context, tmpArrayPath, createMigrationSchema(t, context, TILEDB_INT64), &MigrateOptions{
+		Defaults:  defaults,
+		BatchSize: 2,
+		BackupURI: tmpBackupPath,
+		Progress: func(progress CopyProgress) {
+			migrated = progress
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), migrated.CellsCopied)
+
+	// The old array is kept at the backup URI
+	backup, err := NewArray(context, tmpBackupPath)
+	assert.Nil(t, err)
+	assert.Nil(t, backup.Open(TILEDB_READ))
+	a1, a2 := readCopyTestArray(t, context, backup)
+	assert.Equal(t, []int32{1, 2, 3}, a1)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, a2)
+	assert.Nil(t, backup.Close())
+
+	array, err = NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	schema, err := array.Schema()
+	assert.Nil(t, err)
+	_, err = schema.AttributeFromName("a3")
+	assert.NotNil(t, err)
+
+	reader, err := NewBatchReader(context, array, []string{"a1", "a2", "a4", "a5"}, DefaultBatchSize)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(TILEDB_ROW_MAJOR))
+
+	a1Values := make([]int64, 0)
+	a2Values := make([]string, 0)
+	a4Values := make([]float64, 0)
+	a5Values := make([]string, 0)
+	assert.Nil(t, reader.ReadAll(func(batch *Batch) error {
+		for i := uint64(0); i < batch.NumCells; i++ {
+			value, err := batch.Cell("a1", i)
+			assert.Nil(t, err)
+			a1Values = append(a1Values, value.(int64))
+
+			value, err = batch.Cell("a2", i)
+			assert.Nil(t, err)
+			a2Values = append(a2Values, value.(string))
+
+			value, err = batch.Cell("a4", i)
+			assert.Nil(t, err)
+			a4Values = append(a4Values, value.(float64))
+
+			value, err = batch.Cell("a5", i)
+			assert.Nil(t, err)
+			a5Values = append(a5Values, value.(string))
+		}
+		return nil
+	}))
+	assert.Equal(t, []int64{1, 2, 3}, a1Values)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, a2Values)
+	assert.Equal(t, []float64{0.5, 0.5, 0.5}, a4Values)
+	assert.Equal(t, []string{"n/a", "n/a", "n/a"}, a5Values)
+
+	_, _, value, err := array.GetMetadata("source")
+	assert.Nil(t, err)
+	assert.Equal(t, "sensor", value)
+}
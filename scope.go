@@ -0,0 +1,229 @@
+package tiledb
+
+import (
+	"fmt"
+	"sync"
+)
+
+// freer is implemented by all objects holding c data structures
+type freer interface {
+	Free()
+}
+
+/*
+Scope tracks the objects created through it and frees them all when it is
+closed, instead of waiting for the garbage collector to run their finalizers,
+which under load lets the c heap grow long before they run:
+
+	scope, err := NewScope(nil)
+	if err != nil {
+		return err
+	}
+	defer scope.Close()
+
+	array, err := scope.NewArray("my_array")
+	...
+	query, err := scope.NewQuery(array)
+
+Objects are freed in the reverse order of their creation, the context of the
+scope last. Arrays are closed before being freed, which flushes the metadata
+of arrays opened in WRITE mode. Objects created elsewhere can be added with
+Track. Objects must not be used once the scope is closed.
+*/
+type Scope struct {
+	context *Context
+	mutex   sync.Mutex
+	objects []freer
+	closed  bool
+}
+
+// NewScope creates a scope with a new context with the given configuration,
+// the default configuration if nil
+func NewScope(config *Config) (*Scope, error) {
+	context, err := NewContext(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Scope{context: context}, nil
+}
+
+// Context returns the context of the scope, freed when the scope is closed
+func (s *Scope) Context() *Context {
+	return s.context
+}
+
+// Track adds an object to free when the scope is closed. If the scope is
+// already closed, the object is freed and an error is returned.
+func (s *Scope) Track(object freer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		object.Free()
+		return fmt.Errorf("Error tracking object: scope is closed")
+	}
+	s.objects = append(s.objects, object)
+	return nil
+}
+
+// Close closes the arrays of the scope and frees all its objects and its
+// context, implementing io.Closer. The first error closing an array is
+// returned, all objects are freed anyway. Closing twice is a no-op.
+func (s *Scope) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var err error
+	for i := len(s.objects) - 1; i >= 0; i-- {
+		// Array.Free closes the array too, but can not report errors
+		if array, ok := s.objects[i].(*Array); ok {
+			closeErr := array.Close()
+			if closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+		s.objects[i].Free()
+	}
+	s.objects = nil
+	s.context.Free()
+	return err
+}
+
+// NewConfig creates a config freed with the scope
+func (s *Scope) NewConfig() (*Config, error) {
+	config, err := NewConfig()
+	if err != nil {
+		return nil, err
+	}
+	return config, s.Track(config)
+}
+
+// NewArray creates an array freed with the scope
+func (s *Scope) NewArray(uri string) (*Array, error) {
+	array, err := NewArray(s.context, uri)
+	if err != nil {
+		return nil, err
+	}
+	return array, s.Track(array)
+}
+
+// NewArraySchema creates an array schema freed with the scope
+func (s *Scope) NewArraySchema(arrayType ArrayType) (*ArraySchema, error) {
+	arraySchema, err := NewArraySchema(s.context, arrayType)
+	if err != nil {
+		return nil, err
+	}
+	return arraySchema, s.Track(arraySchema)
+}
+
+// LoadArraySchema loads the schema of the array at uri, freed with the scope
+func (s *Scope) LoadArraySchema(uri string) (*ArraySchema, error) {
+	arraySchema, err := LoadArraySchema(s.context, uri)
+	if err != nil {
+		return nil, err
+	}
+	return arraySchema, s.Track(arraySchema)
+}
+
+// NewAttribute creates an attribute freed with the scope
+func (s *Scope) NewAttribute(name string, datatype Datatype) (*Attribute, error) {
+	attribute, err := NewAttribute(s.context, name, datatype)
+	if err != nil {
+		return nil, err
+	}
+	return attribute, s.Track(attribute)
+}
+
+// NewDimension creates a dimension freed with the scope
+func (s *Scope) NewDimension(name string, domain interface{}, extent interface{}) (*Dimension, error) {
+	dimension, err := NewDimension(s.context, name, domain, extent)
+	if err != nil {
+		return nil, err
+	}
+	return dimension, s.Track(dimension)
+}
+
+// NewDomain creates a domain freed with the scope
+func (s *Scope) NewDomain() (*Domain, error) {
+	domain, err := NewDomain(s.context)
+	if err != nil {
+		return nil, err
+	}
+	return domain, s.Track(domain)
+}
+
+// NewFilter creates a filter freed with the scope
+func (s *Scope) NewFilter(filterType FilterType) (*Filter, error) {
+	filter, err := NewFilter(s.context, filterType)
+	if err != nil {
+		return nil, err
+	}
+	return filter, s.Track(filter)
+}
+
+// NewFilterList creates a filter list freed with the scope
+func (s *Scope) NewFilterList() (*FilterList, error) {
+	filterList, err := NewFilterList(s.context)
+	if err != nil {
+		return nil, err
+	}
+	return filterList, s.Track(filterList)
+}
+
+// NewQuery creates a query on an open array, freed with the scope
+func (s *Scope) NewQuery(array *Array) (*Query, error) {
+	query, err := NewQuery(s.context, array)
+	if err != nil {
+		return nil, err
+	}
+	return query, s.Track(query)
+}
+
+// NewBatchReader creates a batch reader freed with the scope
+func (s *Scope) NewBatchReader(array *Array, names []string, batchSize uint64) (*BatchReader, error) {
+	reader, err := NewBatchReader(s.context, array, names, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	return reader, s.Track(reader)
+}
+
+// NewBuffer creates a buffer freed with the scope
+func (s *Scope) NewBuffer() (*Buffer, error) {
+	buffer, err := NewBuffer(s.context)
+	if err != nil {
+		return nil, err
+	}
+	return buffer, s.Track(buffer)
+}
+
+// NewBufferList creates a buffer list freed with the scope
+func (s *Scope) NewBufferList() (*BufferList, error) {
+	bufferList, err := NewBufferList(s.context)
+	if err != nil {
+		return nil, err
+	}
+	return bufferList, s.Track(bufferList)
+}
+
+// NewVFS creates a VFS with the configuration of the context of the scope,
+// freed with the scope. VFS.Close closes files, so unlike other objects a VFS
+// is not an io.Closer, VFS.Closer returns one freeing it.
+func (s *Scope) NewVFS() (*VFS, error) {
+	config, err := s.context.Config()
+	if err != nil {
+		return nil, err
+	}
+	defer config.Free()
+
+	vfs, err := NewVFS(s.context, config)
+	if err != nil {
+		return nil, err
+	}
+	return vfs, s.Track(vfs)
+}
@@ -0,0 +1,140 @@
+package tiledb
+
+import (
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseIsIdempotent(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	config, err := NewConfig()
+	assert.Nil(t, err)
+	arraySchema, err := NewArraySchema(context, TILEDB_SPARSE)
+	assert.Nil(t, err)
+	attribute, err := NewAttribute(context, "a1", TILEDB_INT32)
+	assert.Nil(t, err)
+	domain, err := NewDomain(context)
+	assert.Nil(t, err)
+	filterList, err := NewFilterList(context)
+	assert.Nil(t, err)
+	buffer, err := NewBuffer(context)
+	assert.Nil(t, err)
+
+	closers := []io.Closer{config, arraySchema, attribute, domain, filterList, buffer, context}
+	for _, closer := range closers {
+		assert.Nil(t, closer.Close())
+		assert.Nil(t, closer.Close())
+	}
+	assert.Nil(t, arraySchema.tiledbArraySchema)
+	assert.Nil(t, context.tiledbContext)
+}
+
+func TestCloserFreesArrayAndVFS(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_closer")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	config, err := NewConfig()
+	assert.Nil(t, err)
+	vfs, err := NewVFS(context, config)
+	assert.Nil(t, err)
+
+	closers := []io.Closer{array.Closer(), vfs.Closer()}
+	for _, closer := range closers {
+		assert.Nil(t, closer.Close())
+		assert.Nil(t, closer.Close())
+	}
+	assert.Nil(t, array.tiledbArray)
+	assert.Nil(t, vfs.tiledbVFS)
+}
+
+func TestScope(t *testing.T) {
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_scope")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err := os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	scope, err := NewScope(nil)
+	assert.Nil(t, err)
+	createBatchTestArray(t, scope.Context(), tmpArrayPath)
+
+	arraySchema, err := scope.LoadArraySchema(tmpArrayPath)
+	assert.Nil(t, err)
+	array, err := scope.NewArray(tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	query, err := scope.NewQuery(array)
+	assert.Nil(t, err)
+	vfs, err := scope.NewVFS()
+	assert.Nil(t, err)
+
+	assert.Nil(t, scope.Close())
+	assert.Nil(t, arraySchema.tiledbArraySchema)
+	assert.Nil(t, array.tiledbArray)
+	assert.Nil(t, query.tiledbQuery)
+	assert.Nil(t, vfs.tiledbVFS)
+	assert.Nil(t, scope.Context().tiledbContext)
+
+	// Closing twice is a no-op, objects tracked after closing are freed
+	assert.Nil(t, scope.Close())
+	config, err := NewConfig()
+	assert.Nil(t, err)
+	assert.NotNil(t, scope.Track(config))
+	assert.Nil(t, config.tiledbConfig)
+}
+
+func TestScopeClosesArrays(t *testing.T) {
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_scope_close")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err := os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	// Array.Close closes the array without freeing it
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	assert.Nil(t, array.Close())
+	assert.NotNil(t, array.tiledbArray)
+	assert.Nil(t, array.Open(TILEDB_READ))
+	array.Free()
+	assert.Nil(t, array.tiledbArray)
+
+	// Closing the scope closes the array, flushing its metadata, then frees it
+	scope, err := NewScope(nil)
+	assert.Nil(t, err)
+	array, err = scope.NewArray(tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, array.PutMetadata("source", "sensor"))
+	assert.Nil(t, scope.Close())
+	assert.Nil(t, array.tiledbArray)
+
+	array, err = NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	defer array.Free()
+	assert.Nil(t, array.Open(TILEDB_READ))
+	_, _, value, err := array.GetMetadata("source")
+	assert.Nil(t, err)
+	assert.Equal(t, "sensor", value)
+}
@@ -36,6 +36,7 @@ func SerializeArraySchema(schema *ArraySchema, serializationType SerializationTy
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error serializing array schema: %s", schema.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -59,6 +60,7 @@ func DeserializeArraySchema(buffer *Buffer, serializationType SerializationType,
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error deserializing array schema: %s", schema.context.LastError())
 	}
+	trackHandle("tiledb_array_schema_t", unsafe.Pointer(schema.tiledbArraySchema))
 
 	return &schema, nil
 }
@@ -69,10 +71,12 @@ func SerializeArrayNonEmptyDomain(a *Array, serializationType SerializationType)
 	if err != nil {
 		return nil, err
 	}
+	defer schema.Free()
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, err
 	}
+	defer domain.Free()
 	domainType, err := domain.Type()
 	if err != nil {
 		return nil, err
@@ -101,6 +105,7 @@ func SerializeArrayNonEmptyDomain(a *Array, serializationType SerializationType)
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error serializing array nonempty domain: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -111,10 +116,12 @@ func DeserializeArrayNonEmptyDomain(a *Array, buffer *Buffer, serializationType
 	if err != nil {
 		return nil, false, err
 	}
+	defer schema.Free()
 	domain, err := schema.Domain()
 	if err != nil {
 		return nil, false, err
 	}
+	defer domain.Free()
 	domainType, err := domain.Type()
 	if err != nil {
 		return nil, false, err
@@ -147,6 +154,7 @@ func DeserializeArrayNonEmptyDomain(a *Array, buffer *Buffer, serializationType
 		if err != nil {
 			return nil, false, err
 		}
+		defer dimension.Free()
 
 		var nonEmptyDomain *NonEmptyDomain
 
@@ -226,6 +234,7 @@ func SerializeArrayNonEmptyDomainAllDimensions(a *Array, serializationType Seria
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error serializing array nonempty domain: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -301,6 +310,7 @@ func SerializeArrayMaxBufferSizes(a *Array, subarray interface{}, serializationT
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("error serializing array max buffer sizes: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -324,6 +334,7 @@ func SerializeQuery(query *Query, serializationType SerializationType, clientSid
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error serializing query: %s", query.context.LastError())
 	}
+	trackHandle("tiledb_buffer_list_t", unsafe.Pointer(bufferList.tiledbBufferList))
 
 	return &bufferList, nil
 }
@@ -357,6 +368,7 @@ func SerializeArrayMetadata(a *Array, serializationType SerializationType) (*Buf
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error serializing array metadata: %s", a.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -389,6 +401,7 @@ func SerializeQueryEstResultSizes(q *Query, serializationType SerializationType,
 	if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Error serializing query est buffer sizes: %s", q.context.LastError())
 	}
+	trackHandle("tiledb_buffer_t", unsafe.Pointer(buffer.tiledbBuffer))
 
 	return &buffer, nil
 }
@@ -0,0 +1,105 @@
+/*
+Package tiledbarrow converts between TileDB query results and Apache Arrow
+records.
+
+Fixed sized fields map to Arrow primitive arrays (or fixed size lists when a
+cell holds more than one value), variable sized string fields map to Arrow
+strings and other variable sized fields map to Arrow lists. The uint64 byte
+offsets used by Query.SetBufferVar are translated to and from the int32
+element offsets used by Arrow.
+
+TileDB (in the version supported by this package) has no nullable
+attributes, so Arrow columns containing nulls can not be written.
+*/
+package tiledbarrow
+
+import (
+	"fmt"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/apache/arrow/go/arrow"
+)
+
+// DatatypeMetadataKey is the Arrow field metadata key holding the TileDB
+// datatype of a field
+const DatatypeMetadataKey = "tiledb.datatype"
+
+// elementType returns the Arrow type used for a single value of a TileDB
+// datatype
+func elementType(datatype tiledb.Datatype) (arrow.DataType, error) {
+	switch datatype {
+	case tiledb.TILEDB_INT8:
+		return arrow.PrimitiveTypes.Int8, nil
+	case tiledb.TILEDB_INT16:
+		return arrow.PrimitiveTypes.Int16, nil
+	case tiledb.TILEDB_INT32:
+		return arrow.PrimitiveTypes.Int32, nil
+	case tiledb.TILEDB_INT64:
+		return arrow.PrimitiveTypes.Int64, nil
+	case tiledb.TILEDB_UINT8, tiledb.TILEDB_CHAR, tiledb.TILEDB_STRING_ASCII, tiledb.TILEDB_STRING_UTF8:
+		return arrow.PrimitiveTypes.Uint8, nil
+	case tiledb.TILEDB_UINT16:
+		return arrow.PrimitiveTypes.Uint16, nil
+	case tiledb.TILEDB_UINT32:
+		return arrow.PrimitiveTypes.Uint32, nil
+	case tiledb.TILEDB_UINT64:
+		return arrow.PrimitiveTypes.Uint64, nil
+	case tiledb.TILEDB_FLOAT32:
+		return arrow.PrimitiveTypes.Float32, nil
+	case tiledb.TILEDB_FLOAT64:
+		return arrow.PrimitiveTypes.Float64, nil
+	case tiledb.TILEDB_DATETIME_DAY:
+		return arrow.FixedWidthTypes.Date32, nil
+	case tiledb.TILEDB_DATETIME_SEC:
+		return arrow.FixedWidthTypes.Timestamp_s, nil
+	case tiledb.TILEDB_DATETIME_MS:
+		return arrow.FixedWidthTypes.Timestamp_ms, nil
+	case tiledb.TILEDB_DATETIME_US:
+		return arrow.FixedWidthTypes.Timestamp_us, nil
+	case tiledb.TILEDB_DATETIME_NS:
+		return arrow.FixedWidthTypes.Timestamp_ns, nil
+	case tiledb.TILEDB_DATETIME_YEAR, tiledb.TILEDB_DATETIME_MONTH, tiledb.TILEDB_DATETIME_WEEK,
+		tiledb.TILEDB_DATETIME_HR, tiledb.TILEDB_DATETIME_MIN, tiledb.TILEDB_DATETIME_PS,
+		tiledb.TILEDB_DATETIME_FS, tiledb.TILEDB_DATETIME_AS:
+		// No matching Arrow unit, the raw int64 value is kept
+		return arrow.PrimitiveTypes.Int64, nil
+	default:
+		return nil, fmt.Errorf("Unsupported datatype for arrow conversion: %s", datatype)
+	}
+}
+
+// Field returns the Arrow field for a TileDB attribute or dimension
+func Field(field tiledb.BatchField) (arrow.Field, error) {
+	metadata := arrow.NewMetadata([]string{DatatypeMetadataKey}, []string{field.Datatype.String()})
+
+	if field.Datatype.IsString() && (field.IsVar() || field.CellValNum > 1) {
+		return arrow.Field{Name: field.Name, Type: arrow.BinaryTypes.String, Metadata: metadata}, nil
+	}
+
+	dtype, err := elementType(field.Datatype)
+	if err != nil {
+		return arrow.Field{}, fmt.Errorf("Error converting field %s: %s", field.Name, err)
+	}
+
+	if field.IsVar() {
+		dtype = arrow.ListOf(dtype)
+	} else if field.CellValNum > 1 {
+		dtype = arrow.FixedSizeListOf(int32(field.CellValNum), dtype)
+	}
+
+	return arrow.Field{Name: field.Name, Type: dtype, Metadata: metadata}, nil
+}
+
+// Schema returns the Arrow schema for a list of TileDB attributes and
+// dimensions, e.g. the fields of a BatchReader
+func Schema(fields []tiledb.BatchField) (*arrow.Schema, error) {
+	arrowFields := make([]arrow.Field, len(fields))
+	for i, field := range fields {
+		arrowField, err := Field(field)
+		if err != nil {
+			return nil, err
+		}
+		arrowFields[i] = arrowField
+	}
+	return arrow.NewSchema(arrowFields, nil), nil
+}
@@ -0,0 +1,40 @@
+package tiledbarrow
+
+import (
+	"testing"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	fields := []tiledb.BatchField{
+		{Name: "rows", Datatype: tiledb.TILEDB_INT32, CellValNum: 1, IsDimension: true},
+		{Name: "a1", Datatype: tiledb.TILEDB_FLOAT64, CellValNum: 1},
+		{Name: "a2", Datatype: tiledb.TILEDB_STRING_UTF8, CellValNum: tiledb.TILEDB_VAR_NUM},
+		{Name: "a3", Datatype: tiledb.TILEDB_INT16, CellValNum: tiledb.TILEDB_VAR_NUM},
+		{Name: "a4", Datatype: tiledb.TILEDB_UINT8, CellValNum: 3},
+		{Name: "a5", Datatype: tiledb.TILEDB_DATETIME_DAY, CellValNum: 1},
+		{Name: "a6", Datatype: tiledb.TILEDB_DATETIME_MS, CellValNum: 1},
+	}
+
+	schema, err := Schema(fields)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(schema.Fields()))
+
+	assert.Equal(t, arrow.PrimitiveTypes.Int32, schema.Field(0).Type)
+	assert.Equal(t, arrow.PrimitiveTypes.Float64, schema.Field(1).Type)
+	assert.Equal(t, arrow.BinaryTypes.String, schema.Field(2).Type)
+	assert.Equal(t, arrow.ListOf(arrow.PrimitiveTypes.Int16), schema.Field(3).Type)
+	assert.Equal(t, arrow.FixedSizeListOf(3, arrow.PrimitiveTypes.Uint8), schema.Field(4).Type)
+	assert.Equal(t, arrow.FixedWidthTypes.Date32, schema.Field(5).Type)
+	assert.Equal(t, arrow.FixedWidthTypes.Timestamp_ms, schema.Field(6).Type)
+
+	idx := schema.Field(1).Metadata.FindKey(DatatypeMetadataKey)
+	assert.NotEqual(t, -1, idx)
+	assert.Equal(t, tiledb.TILEDB_FLOAT64.String(), schema.Field(1).Metadata.Values()[idx])
+
+	_, err = Field(tiledb.BatchField{Name: "any", Datatype: tiledb.TILEDB_ANY, CellValNum: 1})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,11 @@
+module github.com/TileDB-Inc/TileDB-Go/tiledbarrow
+
+go 1.13
+
+require (
+	github.com/TileDB-Inc/TileDB-Go v0.0.0-00010101000000-000000000000
+	github.com/apache/arrow/go/arrow v0.0.0-20201229220542-30ce2eb5d4dc
+	github.com/stretchr/testify v1.5.1
+)
+
+replace github.com/TileDB-Inc/TileDB-Go => ../
@@ -0,0 +1,269 @@
+package tiledbarrow
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// appendValues appends a typed slice of TileDB values to an Arrow builder
+func appendValues(builder array.Builder, values interface{}) error {
+	switch b := builder.(type) {
+	case *array.Int8Builder:
+		b.AppendValues(values.([]int8), nil)
+	case *array.Int16Builder:
+		b.AppendValues(values.([]int16), nil)
+	case *array.Int32Builder:
+		b.AppendValues(values.([]int32), nil)
+	case *array.Int64Builder:
+		b.AppendValues(values.([]int64), nil)
+	case *array.Uint8Builder:
+		b.AppendValues(values.([]uint8), nil)
+	case *array.Uint16Builder:
+		b.AppendValues(values.([]uint16), nil)
+	case *array.Uint32Builder:
+		b.AppendValues(values.([]uint32), nil)
+	case *array.Uint64Builder:
+		b.AppendValues(values.([]uint64), nil)
+	case *array.Float32Builder:
+		b.AppendValues(values.([]float32), nil)
+	case *array.Float64Builder:
+		b.AppendValues(values.([]float64), nil)
+	case *array.TimestampBuilder:
+		for _, v := range values.([]int64) {
+			b.Append(arrow.Timestamp(v))
+		}
+	case *array.Date32Builder:
+		for _, v := range values.([]int64) {
+			b.Append(arrow.Date32(v))
+		}
+	default:
+		return fmt.Errorf("Unsupported arrow builder %T", builder)
+	}
+	return nil
+}
+
+// sliceValues returns values[start:end] for a typed slice
+func sliceValues(values interface{}, start, end uint64) interface{} {
+	switch v := values.(type) {
+	case []int8:
+		return v[start:end]
+	case []int16:
+		return v[start:end]
+	case []int32:
+		return v[start:end]
+	case []int64:
+		return v[start:end]
+	case []uint8:
+		return v[start:end]
+	case []uint16:
+		return v[start:end]
+	case []uint32:
+		return v[start:end]
+	case []uint64:
+		return v[start:end]
+	case []float32:
+		return v[start:end]
+	case []float64:
+		return v[start:end]
+	default:
+		return nil
+	}
+}
+
+// buildColumn converts one field of a batch to an Arrow array
+func buildColumn(mem memory.Allocator, batch *tiledb.Batch, field tiledb.BatchField, arrowField arrow.Field) (array.Interface, error) {
+	data, err := batch.Data(field.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := array.NewBuilder(mem, arrowField.Type)
+	defer builder.Release()
+
+	switch b := builder.(type) {
+	case *array.StringBuilder:
+		for i := uint64(0); i < batch.NumCells; i++ {
+			cell, err := batch.Cell(field.Name, i)
+			if err != nil {
+				return nil, err
+			}
+			b.Append(cell.(string))
+		}
+	case *array.ListBuilder:
+		// TileDB offsets are in bytes, Arrow offsets are in elements
+		offsets, err := batch.Offsets(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		typeSize := field.Datatype.Size()
+		numElements := uint64(len(offsets))
+		for i := uint64(0); i < batch.NumCells; i++ {
+			start := offsets[i] / typeSize
+			var end uint64
+			if i+1 < numElements {
+				end = offsets[i+1] / typeSize
+			} else {
+				end = arrayLen(data)
+			}
+			b.Append(true)
+			err = appendValues(b.ValueBuilder(), sliceValues(data, start, end))
+			if err != nil {
+				return nil, err
+			}
+		}
+	case *array.FixedSizeListBuilder:
+		cellValNum := uint64(field.CellValNum)
+		for i := uint64(0); i < batch.NumCells; i++ {
+			b.Append(true)
+			err = appendValues(b.ValueBuilder(), sliceValues(data, i*cellValNum, (i+1)*cellValNum))
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		err = appendValues(builder, sliceValues(data, 0, batch.NumCells))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return builder.NewArray(), nil
+}
+
+// arrayLen returns the length of a typed slice
+func arrayLen(values interface{}) uint64 {
+	switch v := values.(type) {
+	case []int8:
+		return uint64(len(v))
+	case []int16:
+		return uint64(len(v))
+	case []int32:
+		return uint64(len(v))
+	case []int64:
+		return uint64(len(v))
+	case []uint8:
+		return uint64(len(v))
+	case []uint16:
+		return uint64(len(v))
+	case []uint32:
+		return uint64(len(v))
+	case []uint64:
+		return uint64(len(v))
+	case []float32:
+		return uint64(len(v))
+	case []float64:
+		return uint64(len(v))
+	default:
+		return 0
+	}
+}
+
+// NewRecord converts a batch of TileDB results to an Arrow record.
+// The data is copied, so the record remains valid after the next call to
+// BatchReader.Next. The record must be released after use.
+func NewRecord(mem memory.Allocator, batch *tiledb.Batch) (array.Record, error) {
+	schema, err := Schema(batch.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]array.Interface, 0, len(batch.Fields))
+	release := func() {
+		for _, column := range columns {
+			column.Release()
+		}
+	}
+
+	for i, field := range batch.Fields {
+		column, err := buildColumn(mem, batch, field, schema.Field(i))
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("Error converting %s to arrow: %s", field.Name, err)
+		}
+		columns = append(columns, column)
+	}
+
+	record := array.NewRecord(schema, columns, int64(batch.NumCells))
+	// The record holds its own reference to the columns
+	release()
+	return record, nil
+}
+
+// RecordReader is an array.RecordReader streaming the results of a
+// tiledb.BatchReader as Arrow records, one record per batch
+type RecordReader struct {
+	refCount int64
+	mem      memory.Allocator
+	reader   *tiledb.BatchReader
+	schema   *arrow.Schema
+	record   array.Record
+	err      error
+}
+
+// NewRecordReader creates a RecordReader for a BatchReader. The BatchReader
+// query must be configured before the first call to Next.
+func NewRecordReader(mem memory.Allocator, reader *tiledb.BatchReader) (*RecordReader, error) {
+	schema, err := Schema(reader.Fields())
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecordReader{refCount: 1, mem: mem, reader: reader, schema: schema}, nil
+}
+
+// Retain increases the reference count by 1
+func (r *RecordReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+// Release decreases the reference count by 1, releasing the current record
+// when it reaches 0
+func (r *RecordReader) Release() {
+	if atomic.AddInt64(&r.refCount, -1) == 0 {
+		if r.record != nil {
+			r.record.Release()
+			r.record = nil
+		}
+	}
+}
+
+// Schema returns the Arrow schema of the records
+func (r *RecordReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next reads the next batch and converts it to a record. It returns false
+// when the query is complete or an error occurred, see Err
+func (r *RecordReader) Next() bool {
+	if r.record != nil {
+		r.record.Release()
+		r.record = nil
+	}
+
+	batch, err := r.reader.Next()
+	if err != nil {
+		if err != io.EOF {
+			r.err = err
+		}
+		return false
+	}
+
+	r.record, r.err = NewRecord(r.mem, batch)
+	return r.err == nil
+}
+
+// Record returns the current record, it is valid until the next call to Next
+func (r *RecordReader) Record() array.Record {
+	return r.record
+}
+
+// Err returns the error, if any, that stopped Next
+func (r *RecordReader) Err() error {
+	return r.err
+}
@@ -0,0 +1,236 @@
+package tiledbarrow
+
+import (
+	"fmt"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+)
+
+// primitiveValues returns the values of a primitive Arrow array as a typed
+// slice matching the TileDB datatype of the destination field
+func primitiveValues(column array.Interface, datatype tiledb.Datatype) (interface{}, error) {
+	var values interface{}
+	switch c := column.(type) {
+	case *array.Int8:
+		values = c.Int8Values()
+	case *array.Int16:
+		values = c.Int16Values()
+	case *array.Int32:
+		values = c.Int32Values()
+	case *array.Int64:
+		values = c.Int64Values()
+	case *array.Uint8:
+		values = c.Uint8Values()
+	case *array.Uint16:
+		values = c.Uint16Values()
+	case *array.Uint32:
+		values = c.Uint32Values()
+	case *array.Uint64:
+		values = c.Uint64Values()
+	case *array.Float32:
+		values = c.Float32Values()
+	case *array.Float64:
+		values = c.Float64Values()
+	case *array.Timestamp:
+		timestamps := c.TimestampValues()
+		int64Values := make([]int64, len(timestamps))
+		for i, v := range timestamps {
+			int64Values[i] = int64(v)
+		}
+		values = int64Values
+	case *array.Date32:
+		dates := c.Date32Values()
+		int64Values := make([]int64, len(dates))
+		for i, v := range dates {
+			int64Values[i] = int64(v)
+		}
+		values = int64Values
+	default:
+		return nil, fmt.Errorf("Unsupported arrow column type %s", column.DataType().Name())
+	}
+
+	expected, err := elementType(datatype)
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps and dates are stored as int64 in TileDB, so their unit must
+	// match the datatype as well
+	if !arrow.TypeEqual(expected, column.DataType()) {
+		return nil, fmt.Errorf("Arrow column type %s does not match TileDB datatype %s", column.DataType().Name(), datatype)
+	}
+
+	return values, nil
+}
+
+// varValues converts a String, Binary or List Arrow array to TileDB byte
+// offsets and a typed data slice
+func varValues(column array.Interface, datatype tiledb.Datatype) ([]uint64, interface{}, error) {
+	n := column.Len()
+	offsets := make([]uint64, n)
+
+	switch c := column.(type) {
+	case *array.String:
+		data := make([]byte, 0)
+		for i := 0; i < n; i++ {
+			offsets[i] = uint64(len(data))
+			data = append(data, c.Value(i)...)
+		}
+		return offsets, data, nil
+	case *array.Binary:
+		data := make([]byte, 0)
+		for i := 0; i < n; i++ {
+			offsets[i] = uint64(len(data))
+			data = append(data, c.Value(i)...)
+		}
+		return offsets, data, nil
+	case *array.List:
+		values, err := primitiveValues(c.ListValues(), datatype)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Arrow offsets are in elements and relative to the child array,
+		// TileDB offsets are in bytes and relative to the written data
+		arrowOffsets := c.Offsets()
+		first := uint64(arrowOffsets[c.Data().Offset()])
+		last := uint64(arrowOffsets[c.Data().Offset()+n])
+		typeSize := datatype.Size()
+		for i := 0; i < n; i++ {
+			offsets[i] = (uint64(arrowOffsets[c.Data().Offset()+i]) - first) * typeSize
+		}
+		return offsets, sliceValues(values, first, last), nil
+	default:
+		return nil, nil, fmt.Errorf("Unsupported arrow column type %s for variable sized field", column.DataType().Name())
+	}
+}
+
+// fixedValues converts a primitive or FixedSizeList Arrow array to a typed
+// data slice
+func fixedValues(column array.Interface, field tiledb.BatchField) (interface{}, error) {
+	if field.CellValNum == 1 {
+		return primitiveValues(column, field.Datatype)
+	}
+
+	switch c := column.(type) {
+	case *array.FixedSizeList:
+		values, err := primitiveValues(c.ListValues(), field.Datatype)
+		if err != nil {
+			return nil, err
+		}
+		cellValNum := uint64(field.CellValNum)
+		start := uint64(c.Data().Offset()) * cellValNum
+		return sliceValues(values, start, start+uint64(c.Len())*cellValNum), nil
+	case *array.String:
+		data := make([]byte, 0, c.Len()*int(field.CellValNum))
+		for i := 0; i < c.Len(); i++ {
+			value := c.Value(i)
+			if len(value) != int(field.CellValNum) {
+				return nil, fmt.Errorf("String %q does not have %d characters", value, field.CellValNum)
+			}
+			data = append(data, value...)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("Unsupported arrow column type %s for field with %d values per cell", column.DataType().Name(), field.CellValNum)
+	}
+}
+
+/*
+WriteRecord writes an Arrow record to an array opened in WRITE mode. Every
+attribute and dimension of the array must be present as a column of the
+record, matched by name. Sparse arrays are written with the given layout
+(usually TILEDB_UNORDERED). Dense arrays are written to subarray, in which
+case the dimension columns are not required and the record must hold the
+cells of the subarray in the given layout.
+*/
+func WriteRecord(ctx *tiledb.Context, tdbArray *tiledb.Array, record array.Record, layout tiledb.Layout, subarray interface{}) error {
+	schema, err := tdbArray.Schema()
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return err
+	}
+
+	query, err := tiledb.NewQuery(ctx, tdbArray)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	err = query.SetLayout(layout)
+	if err != nil {
+		return err
+	}
+
+	if subarray != nil {
+		err = query.SetSubArray(subarray)
+		if err != nil {
+			return err
+		}
+	}
+
+	columnByName := func(name string) (array.Interface, bool) {
+		for i := 0; i < int(record.NumCols()); i++ {
+			if record.ColumnName(i) == name {
+				return record.Column(i), true
+			}
+		}
+		return nil, false
+	}
+
+	fields, err := schema.Fields()
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		column, ok := columnByName(field.Name)
+		if !ok {
+			if field.IsDimension && arrayType == tiledb.TILEDB_DENSE {
+				continue
+			}
+			return fmt.Errorf("Record has no column for %s", field.Name)
+		}
+
+		if column.NullN() > 0 {
+			return fmt.Errorf("Column %s contains nulls which are not supported", field.Name)
+		}
+
+		if field.IsVar() {
+			offsets, data, err := varValues(column, field.Datatype)
+			if err != nil {
+				return fmt.Errorf("Error converting column %s: %s", field.Name, err)
+			}
+			_, _, err = query.SetBufferVar(field.Name, offsets, data)
+			if err != nil {
+				return err
+			}
+		} else {
+			data, err := fixedValues(column, field)
+			if err != nil {
+				return fmt.Errorf("Error converting column %s: %s", field.Name, err)
+			}
+			_, err = query.SetBuffer(field.Name, data)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = query.Submit()
+	if err != nil {
+		return err
+	}
+
+	if layout == tiledb.TILEDB_GLOBAL_ORDER {
+		return query.Finalize()
+	}
+	return nil
+}
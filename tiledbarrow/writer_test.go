@@ -0,0 +1,154 @@
+package tiledbarrow
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// createArrowTestArray creates a 1D sparse array with an int32 attribute
+// "a1", a variable sized string attribute "a2" and a variable sized int16
+// attribute "a3"
+func createArrowTestArray(t *testing.T, context *tiledb.Context, tmpArrayPath string) {
+	dimension, err := tiledb.NewDimension(context, "rows", []int32{1, 10}, int32(5))
+	assert.Nil(t, err)
+
+	domain, err := tiledb.NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(dimension))
+
+	arraySchema, err := tiledb.NewArraySchema(context, tiledb.TILEDB_SPARSE)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.SetDomain(domain))
+
+	a1, err := tiledb.NewAttribute(context, "a1", tiledb.TILEDB_INT32)
+	assert.Nil(t, err)
+	a2, err := tiledb.NewAttribute(context, "a2", tiledb.TILEDB_STRING_UTF8)
+	assert.Nil(t, err)
+	assert.Nil(t, a2.SetCellValNum(tiledb.TILEDB_VAR_NUM))
+	a3, err := tiledb.NewAttribute(context, "a3", tiledb.TILEDB_INT16)
+	assert.Nil(t, err)
+	assert.Nil(t, a3.SetCellValNum(tiledb.TILEDB_VAR_NUM))
+	assert.Nil(t, arraySchema.AddAttributes(a1, a2, a3))
+
+	array, err := tiledb.NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+}
+
+func TestWriteAndReadRecord(t *testing.T) {
+	context, err := tiledb.NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_arrow_record")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createArrowTestArray(t, context, tmpArrayPath)
+
+	mem := memory.NewGoAllocator()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "rows", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "a1", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "a2", Type: arrow.BinaryTypes.String},
+		{Name: "a3", Type: arrow.ListOf(arrow.PrimitiveTypes.Int16)},
+	}, nil)
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	builder.Field(0).(*array.Int32Builder).AppendValues([]int32{1, 4, 7}, nil)
+	builder.Field(1).(*array.Int32Builder).AppendValues([]int32{10, 40, 70}, nil)
+	builder.Field(2).(*array.StringBuilder).AppendValues([]string{"a", "bb", "ccc"}, nil)
+	listBuilder := builder.Field(3).(*array.ListBuilder)
+	valueBuilder := listBuilder.ValueBuilder().(*array.Int16Builder)
+	listBuilder.Append(true)
+	valueBuilder.AppendValues([]int16{1}, nil)
+	listBuilder.Append(true)
+	valueBuilder.AppendValues([]int16{2, 3}, nil)
+	listBuilder.Append(true)
+	valueBuilder.AppendValues([]int16{4, 5, 6}, nil)
+
+	record := builder.NewRecord()
+	defer record.Release()
+
+	tdbArray, err := tiledb.NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, tdbArray.Open(tiledb.TILEDB_WRITE))
+	assert.Nil(t, WriteRecord(context, tdbArray, record, tiledb.TILEDB_UNORDERED, nil))
+	assert.Nil(t, tdbArray.Close())
+
+	// Missing columns are rejected
+	assert.Nil(t, tdbArray.Open(tiledb.TILEDB_WRITE))
+	incomplete := array.NewRecord(arrow.NewSchema(schema.Fields()[:2], nil), record.Columns()[:2], record.NumRows())
+	defer incomplete.Release()
+	assert.NotNil(t, WriteRecord(context, tdbArray, incomplete, tiledb.TILEDB_UNORDERED, nil))
+	assert.Nil(t, tdbArray.Close())
+
+	assert.Nil(t, tdbArray.Open(tiledb.TILEDB_READ))
+	defer tdbArray.Close()
+
+	// A batch size of two splits the results over two records
+	reader, err := tiledb.NewBatchReader(context, tdbArray, nil, 2)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(tiledb.TILEDB_ROW_MAJOR))
+
+	recordReader, err := NewRecordReader(mem, reader)
+	assert.Nil(t, err)
+	defer recordReader.Release()
+	assert.Equal(t, 4, len(recordReader.Schema().Fields()))
+
+	rows := make([]int32, 0)
+	a1 := make([]int32, 0)
+	a2 := make([]string, 0)
+	a3 := make([][]int16, 0)
+	for recordReader.Next() {
+		rec := recordReader.Record()
+		rows = append(rows, rec.Column(0).(*array.Int32).Int32Values()...)
+		a1 = append(a1, rec.Column(1).(*array.Int32).Int32Values()...)
+
+		strings := rec.Column(2).(*array.String)
+		for i := 0; i < strings.Len(); i++ {
+			a2 = append(a2, strings.Value(i))
+		}
+
+		lists := rec.Column(3).(*array.List)
+		values := lists.ListValues().(*array.Int16).Int16Values()
+		offsets := lists.Offsets()
+		for i := 0; i < lists.Len(); i++ {
+			a3 = append(a3, values[offsets[i]:offsets[i+1]])
+		}
+	}
+	assert.Nil(t, recordReader.Err())
+
+	assert.Equal(t, []int32{1, 4, 7}, rows)
+	assert.Equal(t, []int32{10, 40, 70}, a1)
+	assert.Equal(t, []string{"a", "bb", "ccc"}, a2)
+	assert.Equal(t, [][]int16{{1}, {2, 3}, {4, 5, 6}}, a3)
+}
+
+func TestPrimitiveValuesTimestampUnit(t *testing.T) {
+	mem := memory.NewGoAllocator()
+	builder := array.NewTimestampBuilder(mem, arrow.FixedWidthTypes.Timestamp_ms.(*arrow.TimestampType))
+	defer builder.Release()
+	builder.AppendValues([]arrow.Timestamp{1000, 2000}, nil)
+	column := builder.NewArray()
+	defer column.Release()
+
+	values, err := primitiveValues(column, tiledb.TILEDB_DATETIME_MS)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1000, 2000}, values)
+
+	// Milliseconds are not stored as seconds or nanoseconds
+	_, err = primitiveValues(column, tiledb.TILEDB_DATETIME_SEC)
+	assert.NotNil(t, err)
+	_, err = primitiveValues(column, tiledb.TILEDB_DATETIME_NS)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,11 @@
+module github.com/TileDB-Inc/TileDB-Go/tiledbgonum
+
+go 1.13
+
+require (
+	github.com/TileDB-Inc/TileDB-Go v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.5.1
+	gonum.org/v1/gonum v0.7.0
+)
+
+replace github.com/TileDB-Inc/TileDB-Go => ../
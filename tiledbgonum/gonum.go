@@ -0,0 +1,73 @@
+/*
+Package tiledbgonum reads and writes 2-D dense TileDB arrays of float64 as
+gonum matrices.
+
+Matrices are exchanged in row major order, a mat.Dense is backed directly
+by the buffer of the read query.
+*/
+package tiledbgonum
+
+import (
+	"fmt"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadDense reads a float64 attribute of a 2-D dense subarray
+// [rowStart, rowEnd, colStart, colEnd] into a mat.Dense. The array must be
+// opened in READ mode.
+func ReadDense(ctx *tiledb.Context, array *tiledb.Array, attribute string, subarray interface{}) (*mat.Dense, error) {
+	ndArray, err := tiledb.ReadNDArray(ctx, array, attribute, subarray, tiledb.TILEDB_ROW_MAJOR)
+	if err != nil {
+		return nil, err
+	}
+
+	return NDArrayToDense(ndArray)
+}
+
+// WriteDense writes a matrix to a float64 attribute of a 2-D dense subarray
+// with the same dimensions. The array must be opened in WRITE mode.
+func WriteDense(ctx *tiledb.Context, array *tiledb.Array, attribute string, subarray interface{}, m mat.Matrix) error {
+	return tiledb.WriteNDArray(ctx, array, attribute, subarray, DenseToNDArray(m))
+}
+
+// NDArrayToDense converts a 2-D NDArray of float64 to a mat.Dense. Row major
+// arrays share their data with the matrix, column major arrays are copied.
+func NDArrayToDense(ndArray *tiledb.NDArray) (*mat.Dense, error) {
+	if len(ndArray.Shape) != 2 {
+		return nil, fmt.Errorf("NDArray must have 2 dimensions to be converted to a matrix, got %d", len(ndArray.Shape))
+	}
+
+	if _, ok := ndArray.Data.([]float64); !ok {
+		return nil, fmt.Errorf("NDArray must hold float64 values to be converted to a matrix, got %T", ndArray.Data)
+	}
+
+	rowMajor, err := ndArray.ToLayout(tiledb.TILEDB_ROW_MAJOR)
+	if err != nil {
+		return nil, err
+	}
+
+	return mat.NewDense(int(rowMajor.Shape[0]), int(rowMajor.Shape[1]), rowMajor.Data.([]float64)), nil
+}
+
+// DenseToNDArray converts a matrix to a row major NDArray. The data of a
+// mat.Dense without padding between rows is shared, other matrices are
+// copied.
+func DenseToNDArray(m mat.Matrix) *tiledb.NDArray {
+	rows, cols := m.Dims()
+
+	var data []float64
+	if dense, ok := m.(*mat.Dense); ok && dense.RawMatrix().Stride == cols {
+		data = dense.RawMatrix().Data[:rows*cols]
+	} else {
+		data = mat.DenseCopyOf(m).RawMatrix().Data
+	}
+
+	return &tiledb.NDArray{
+		Data:    data,
+		Shape:   []uint64{uint64(rows), uint64(cols)},
+		Strides: []uint64{uint64(cols), 1},
+		Layout:  tiledb.TILEDB_ROW_MAJOR,
+	}
+}
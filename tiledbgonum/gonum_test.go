@@ -0,0 +1,78 @@
+package tiledbgonum
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/stretchr/testify/assert"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestNDArrayToDense(t *testing.T) {
+	ndArray, err := tiledb.NewNDArray([]float64{1, 3, 2, 4}, []uint64{2, 2}, tiledb.TILEDB_COL_MAJOR)
+	assert.Nil(t, err)
+
+	m, err := NDArrayToDense(ndArray)
+	assert.Nil(t, err)
+	assert.Equal(t, 2.0, m.At(0, 1))
+	assert.Equal(t, 3.0, m.At(1, 0))
+
+	ndArray, err = tiledb.NewNDArray([]int32{1, 2}, []uint64{1, 2}, tiledb.TILEDB_ROW_MAJOR)
+	assert.Nil(t, err)
+	_, err = NDArrayToDense(ndArray)
+	assert.NotNil(t, err)
+
+	// A view with padding between rows is copied
+	view := mat.NewDense(3, 3, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9}).Slice(0, 2, 1, 3)
+	ndArray = DenseToNDArray(view)
+	assert.Equal(t, []float64{2, 3, 5, 6}, ndArray.Data)
+	assert.Equal(t, []uint64{2, 2}, ndArray.Shape)
+}
+
+func TestReadWriteDense(t *testing.T) {
+	context, err := tiledb.NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_gonum_dense")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	rows, err := tiledb.NewDimension(context, "rows", []uint64{0, 9}, uint64(5))
+	assert.Nil(t, err)
+	cols, err := tiledb.NewDimension(context, "cols", []uint64{0, 9}, uint64(5))
+	assert.Nil(t, err)
+	domain, err := tiledb.NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(rows, cols))
+
+	arraySchema, err := tiledb.NewArraySchema(context, tiledb.TILEDB_DENSE)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.SetDomain(domain))
+	value, err := tiledb.NewAttribute(context, "value", tiledb.TILEDB_FLOAT64)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.AddAttributes(value))
+
+	array, err := tiledb.NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+
+	m := mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	assert.Nil(t, array.Open(tiledb.TILEDB_WRITE))
+	assert.Nil(t, WriteDense(context, array, "value", []uint64{3, 4, 0, 2}, m))
+	assert.Nil(t, array.Close())
+
+	assert.Nil(t, array.Open(tiledb.TILEDB_READ))
+	defer array.Close()
+
+	result, err := ReadDense(context, array, "value", []uint64{3, 4, 0, 2})
+	assert.Nil(t, err)
+	assert.True(t, mat.Equal(m, result))
+
+	// Only 2-D subarrays can be read as a matrix
+	_, err = ReadDense(context, array, "value", []uint64{3, 4})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,100 @@
+package tiledbparquet
+
+import (
+	"fmt"
+	"io"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// parquetValue converts a cell returned by tiledb.Batch.Cell to the Go type
+// of its Parquet physical type
+func parquetValue(field tiledb.BatchField, cell interface{}) (interface{}, error) {
+	switch v := cell.(type) {
+	case string:
+		return v, nil
+	case int8:
+		return int32(v), nil
+	case int16:
+		return int32(v), nil
+	case int32:
+		return v, nil
+	case uint8:
+		return int32(v), nil
+	case uint16:
+		return int32(v), nil
+	case uint32:
+		// Unsigned values are stored in the bits of the signed physical type
+		return int32(v), nil
+	case uint64:
+		return int64(v), nil
+	case float32:
+		return v, nil
+	case float64:
+		return v, nil
+	case int64:
+		if field.Datatype == tiledb.TILEDB_DATETIME_DAY {
+			return int32(v), nil
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("Unsupported value of type %T for field %s", cell, field.Name)
+	}
+}
+
+/*
+Export writes the results of a BatchReader to w as a Parquet file, one
+column per field of the reader. The subarray, ranges and layout of the
+export are set on reader.Query() before calling Export. Fields with more
+than one value per cell are only supported for text datatypes.
+*/
+func Export(w io.Writer, reader *tiledb.BatchReader) error {
+	fields := reader.Fields()
+	metadata := make([]string, len(fields))
+	for i, field := range fields {
+		md, err := Metadata(field)
+		if err != nil {
+			return err
+		}
+		metadata[i] = md
+	}
+
+	parquetWriter, err := writer.NewCSVWriterFromWriter(metadata, w, 1)
+	if err != nil {
+		return fmt.Errorf("Error creating parquet writer: %s", err)
+	}
+	parquetWriter.Footer.KeyValueMetadata = DatatypeMetadata(fields)
+
+	err = reader.ReadAll(func(batch *tiledb.Batch) error {
+		for i := uint64(0); i < batch.NumCells; i++ {
+			row := make([]interface{}, len(fields))
+			for j, field := range fields {
+				cell, err := batch.Cell(field.Name, i)
+				if err != nil {
+					return err
+				}
+
+				row[j], err = parquetValue(field, cell)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = parquetWriter.Write(row)
+			if err != nil {
+				return fmt.Errorf("Error writing parquet row: %s", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = parquetWriter.WriteStop()
+	if err != nil {
+		return fmt.Errorf("Error writing parquet file: %s", err)
+	}
+	return nil
+}
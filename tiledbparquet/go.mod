@@ -0,0 +1,12 @@
+module github.com/TileDB-Inc/TileDB-Go/tiledbparquet
+
+go 1.13
+
+require (
+	github.com/TileDB-Inc/TileDB-Go v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.5.1
+	github.com/xitongsys/parquet-go v1.5.5-0.20201110004701-b09c49d6d457
+	github.com/xitongsys/parquet-go-source v0.0.0-20200817004010-026bad9b25d0
+)
+
+replace github.com/TileDB-Inc/TileDB-Go => ../
@@ -0,0 +1,610 @@
+package tiledbparquet
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// DefaultBatchSize is the number of rows read from Parquet and written to
+// TileDB per query when ImportOptions.BatchSize is not set
+const DefaultBatchSize = 65536
+
+// DefaultRowDimension is the name of the dimension holding the row index of
+// dense arrays when ImportOptions.RowDimension is not set
+const DefaultRowDimension = "__tiledb_rows"
+
+// ImportOptions configures Import
+type ImportOptions struct {
+	// ArrayType of the created array. Dense arrays have a single uint64
+	// dimension holding the row index, sparse arrays use the columns listed
+	// in Dimensions as dimensions.
+	ArrayType tiledb.ArrayType
+	// Dimensions are the columns used as dimensions of a sparse array, the
+	// remaining columns are stored as attributes. The domain of each
+	// dimension is the range of values of its column. Text columns become
+	// string dimensions.
+	Dimensions []string
+	// RowDimension is the name of the row index dimension of a dense array
+	RowDimension string
+	// BatchSize is the number of rows written per query
+	BatchSize int64
+}
+
+func (o *ImportOptions) batchSize() int64 {
+	if o == nil || o.BatchSize <= 0 {
+		return DefaultBatchSize
+	}
+	return o.BatchSize
+}
+
+func (o *ImportOptions) rowDimension() string {
+	if o == nil || o.RowDimension == "" {
+		return DefaultRowDimension
+	}
+	return o.RowDimension
+}
+
+func (o *ImportOptions) isDimension(name string) bool {
+	if o == nil || o.ArrayType != tiledb.TILEDB_SPARSE {
+		return false
+	}
+	for _, dimension := range o.Dimensions {
+		if dimension == name {
+			return true
+		}
+	}
+	return false
+}
+
+// int64Value returns a Parquet INT32, INT64 or BOOLEAN value of column name
+// as int64
+func int64Value(name string, value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("Unexpected value of type %T in integer column %s", value, name)
+	}
+}
+
+// int64Values returns the values of an integer column as int64
+func int64Values(name string, values []interface{}) ([]int64, error) {
+	ints := make([]int64, len(values))
+	for i, value := range values {
+		v, err := int64Value(name, value)
+		if err != nil {
+			return nil, err
+		}
+		ints[i] = v
+	}
+	return ints, nil
+}
+
+// columnValues converts the values of a Parquet column to TileDB buffers.
+// Offsets are only returned for variable sized fields.
+func columnValues(name string, values []interface{}, datatype tiledb.Datatype, cellValNum uint) ([]uint64, interface{}, error) {
+	for _, value := range values {
+		if value == nil {
+			return nil, nil, fmt.Errorf("Column %s contains nulls which are not supported", name)
+		}
+	}
+
+	n := len(values)
+	if datatype.IsString() {
+		offsets := make([]uint64, n)
+		data := make([]byte, 0)
+		for i, value := range values {
+			str, ok := value.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("Unexpected value of type %T in text column %s", value, name)
+			}
+			if cellValNum != tiledb.TILEDB_VAR_NUM && uint(len(str)) != cellValNum {
+				return nil, nil, fmt.Errorf("Value %q of column %s does not have %d bytes", str, name, cellValNum)
+			}
+			offsets[i] = uint64(len(data))
+			data = append(data, str...)
+		}
+		if cellValNum == tiledb.TILEDB_VAR_NUM {
+			return offsets, data, nil
+		}
+		return nil, data, nil
+	}
+
+	switch datatype {
+	case tiledb.TILEDB_INT8:
+		ints, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := make([]int8, n)
+		for i, v := range ints {
+			data[i] = int8(v)
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_INT16:
+		ints, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := make([]int16, n)
+		for i, v := range ints {
+			data[i] = int16(v)
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_INT32:
+		ints, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := make([]int32, n)
+		for i, v := range ints {
+			data[i] = int32(v)
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_UINT8:
+		ints, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := make([]uint8, n)
+		for i, v := range ints {
+			data[i] = uint8(v)
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_UINT16:
+		ints, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := make([]uint16, n)
+		for i, v := range ints {
+			data[i] = uint16(v)
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_UINT32:
+		// Unsigned values are stored in the bits of the signed physical type
+		ints, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := make([]uint32, n)
+		for i, v := range ints {
+			data[i] = uint32(v)
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_UINT64:
+		ints, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := make([]uint64, n)
+		for i, v := range ints {
+			data[i] = uint64(v)
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_FLOAT32:
+		data := make([]float32, n)
+		for i, value := range values {
+			v, ok := value.(float32)
+			if !ok {
+				return nil, nil, fmt.Errorf("Unexpected value of type %T in float column %s", value, name)
+			}
+			data[i] = v
+		}
+		return nil, data, nil
+	case tiledb.TILEDB_FLOAT64:
+		data := make([]float64, n)
+		for i, value := range values {
+			v, ok := value.(float64)
+			if !ok {
+				return nil, nil, fmt.Errorf("Unexpected value of type %T in double column %s", value, name)
+			}
+			data[i] = v
+		}
+		return nil, data, nil
+	default:
+		if datatype != tiledb.TILEDB_INT64 && !datatype.IsDatetime() {
+			return nil, nil, fmt.Errorf("Unsupported datatype %s for column %s", datatype, name)
+		}
+		data, err := int64Values(name, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, data, nil
+	}
+}
+
+// columnBounds tracks the range of values of a numeric column
+type columnBounds struct {
+	min reflect.Value
+	max reflect.Value
+}
+
+// update extends the bounds with the values of a typed slice. NaN values
+// are rejected since they can not be coordinates.
+func (b *columnBounds) update(data interface{}) error {
+	values := reflect.ValueOf(data)
+	for i := 0; i < values.Len(); i++ {
+		value := values.Index(i)
+		if (value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64) && math.IsNaN(value.Float()) {
+			return fmt.Errorf("NaN values can not be coordinates")
+		}
+		if !b.min.IsValid() {
+			b.min, b.max = value, value
+			continue
+		}
+		if c, _ := tiledb.CompareValues(value.Interface(), b.min.Interface()); c < 0 {
+			b.min = value
+		}
+		if c, _ := tiledb.CompareValues(value.Interface(), b.max.Interface()); c > 0 {
+			b.max = value
+		}
+	}
+	return nil
+}
+
+// domain returns the domain and tile extent of a dimension covering the
+// bounds, as expected by tiledb.NewDimension
+func (b *columnBounds) domain(batchSize int64) (interface{}, interface{}) {
+	elemType := b.min.Type()
+	domain := reflect.MakeSlice(reflect.SliceOf(elemType), 2, 2)
+	domain.Index(0).Set(b.min)
+	domain.Index(1).Set(b.max)
+
+	extent := reflect.New(elemType).Elem()
+	switch b.min.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// The extent is the number of values in the domain capped to the
+		// batch size, and must be representable by the dimension type
+		span := uint64(b.max.Int()-b.min.Int()) + 1
+		if span == 0 || span > uint64(batchSize) {
+			span = uint64(batchSize)
+		}
+		for extent.OverflowInt(int64(span)) {
+			span /= 2
+		}
+		extent.SetInt(int64(span))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		span := b.max.Uint() - b.min.Uint() + 1
+		if span == 0 || span > uint64(batchSize) {
+			span = uint64(batchSize)
+		}
+		for extent.OverflowUint(span) {
+			span /= 2
+		}
+		extent.SetUint(span)
+	default:
+		// The extent can not exceed the width of the domain, a single value
+		// is widened to the next representable value
+		if b.max.Float() == b.min.Float() {
+			if b.min.Kind() == reflect.Float32 {
+				domain.Index(1).SetFloat(float64(math.Nextafter32(float32(b.max.Float()), float32(math.Inf(1)))))
+			} else {
+				domain.Index(1).SetFloat(math.Nextafter(b.max.Float(), math.Inf(1)))
+			}
+		}
+		extent.SetFloat(domain.Index(1).Float() - domain.Index(0).Float())
+	}
+
+	return domain.Interface(), extent.Interface()
+}
+
+// flatColumns returns the leaf columns of a flat Parquet schema
+func flatColumns(parquetReader *reader.ParquetReader) ([]*parquet.SchemaElement, error) {
+	elements := parquetReader.Footer.GetSchema()
+	if len(elements) < 2 {
+		return nil, fmt.Errorf("Parquet file has no columns")
+	}
+
+	columns := elements[1:]
+	if int(elements[0].GetNumChildren()) != len(columns) {
+		return nil, fmt.Errorf("Nested parquet schemas are not supported")
+	}
+	return columns, nil
+}
+
+// dimensionBounds scans the dimension columns of a Parquet file and returns
+// their bounds by column index
+func dimensionBounds(file source.ParquetFile, options *ImportOptions) (map[int]*columnBounds, error) {
+	parquetReader, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading parquet file: %s", err)
+	}
+	defer parquetReader.ReadStop()
+
+	columns, err := flatColumns(parquetReader)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := make(map[int]*columnBounds)
+	numRows := parquetReader.GetNumRows()
+	batchSize := options.batchSize()
+	for i, column := range columns {
+		if !options.isDimension(column.Name) {
+			continue
+		}
+
+		datatype, cellValNum, err := Datatype(column)
+		if err != nil {
+			return nil, err
+		}
+		if datatype.IsString() {
+			continue
+		}
+
+		bounds[i] = &columnBounds{}
+		for offset := int64(0); offset < numRows; offset += batchSize {
+			values, _, _, err := parquetReader.ReadColumnByIndex(int64(i), batchSize)
+			if err != nil {
+				return nil, fmt.Errorf("Error reading parquet column %s: %s", column.Name, err)
+			}
+
+			_, data, err := columnValues(column.Name, values, datatype, cellValNum)
+			if err != nil {
+				return nil, err
+			}
+			err = bounds[i].update(data)
+			if err != nil {
+				return nil, fmt.Errorf("Error in dimension column %s: %s", column.Name, err)
+			}
+		}
+	}
+
+	return bounds, nil
+}
+
+// arraySchema creates the schema of the array a Parquet file is imported to
+func arraySchema(ctx *tiledb.Context, file source.ParquetFile, footer *parquet.FileMetaData, columns []*parquet.SchemaElement, numRows int64, options *ImportOptions) (*tiledb.ArraySchema, error) {
+	arrayType := tiledb.TILEDB_DENSE
+	if options != nil {
+		arrayType = options.ArrayType
+	}
+
+	domain, err := tiledb.NewDomain(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if arrayType == tiledb.TILEDB_DENSE {
+		extent := options.batchSize()
+		if extent > numRows {
+			extent = numRows
+		}
+		dimension, err := tiledb.NewDimension(ctx, options.rowDimension(), []uint64{0, uint64(numRows - 1)}, uint64(extent))
+		if err != nil {
+			return nil, err
+		}
+		err = domain.AddDimensions(dimension)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if len(options.Dimensions) == 0 {
+			return nil, fmt.Errorf("Sparse arrays need at least one dimension column")
+		}
+
+		bounds, err := dimensionBounds(file, options)
+		if err != nil {
+			return nil, err
+		}
+
+		// Dimensions are added in the order they were requested
+		for _, name := range options.Dimensions {
+			index := -1
+			for i, column := range columns {
+				if column.Name == name {
+					index = i
+				}
+			}
+			if index < 0 {
+				return nil, fmt.Errorf("Dimension column %s not found in parquet file", name)
+			}
+
+			var dimension *tiledb.Dimension
+			if columnBound, ok := bounds[index]; ok {
+				dimensionDomain, extent := columnBound.domain(options.batchSize())
+				dimension, err = tiledb.NewDimension(ctx, name, dimensionDomain, extent)
+			} else {
+				dimension, err = tiledb.NewStringDimension(ctx, name)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			err = domain.AddDimensions(dimension)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	schema, err := tiledb.NewArraySchema(ctx, arrayType)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.SetDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.SetCellOrder(tiledb.TILEDB_ROW_MAJOR)
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.SetTileOrder(tiledb.TILEDB_ROW_MAJOR)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, column := range columns {
+		if options.isDimension(column.Name) {
+			continue
+		}
+
+		datatype, cellValNum, err := columnDatatype(footer, column)
+		if err != nil {
+			return nil, err
+		}
+
+		attribute, err := tiledb.NewAttribute(ctx, column.Name, datatype)
+		if err != nil {
+			return nil, err
+		}
+
+		err = attribute.SetCellValNum(cellValNum)
+		if err != nil {
+			return nil, err
+		}
+
+		err = schema.AddAttributes(attribute)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = schema.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+/*
+Import creates an array at uri from the schema of a Parquet file and writes
+all rows of the file to it, options.BatchSize rows per query. A nil options
+imports to a dense array indexed by row number.
+*/
+func Import(ctx *tiledb.Context, file source.ParquetFile, uri string, options *ImportOptions) error {
+	parquetReader, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		return fmt.Errorf("Error reading parquet file: %s", err)
+	}
+	defer parquetReader.ReadStop()
+
+	columns, err := flatColumns(parquetReader)
+	if err != nil {
+		return err
+	}
+
+	numRows := parquetReader.GetNumRows()
+	if numRows == 0 {
+		return fmt.Errorf("Parquet file has no rows")
+	}
+
+	schema, err := arraySchema(ctx, file, parquetReader.Footer, columns, numRows, options)
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+
+	array, err := tiledb.NewArray(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer array.Free()
+
+	err = array.Create(schema)
+	if err != nil {
+		return err
+	}
+
+	err = array.Open(tiledb.TILEDB_WRITE)
+	if err != nil {
+		return err
+	}
+	defer array.Close()
+
+	arrayType, err := schema.Type()
+	if err != nil {
+		return err
+	}
+
+	// Dimensions may have been created with a different datatype than the
+	// one of their column, the buffers follow the array schema
+	fields, err := schema.Fields()
+	if err != nil {
+		return err
+	}
+	fieldByName := make(map[string]tiledb.BatchField)
+	for _, field := range fields {
+		fieldByName[field.Name] = field
+	}
+
+	batchSize := options.batchSize()
+	for offset := int64(0); offset < numRows; offset += batchSize {
+		err = importBatch(ctx, array, arrayType, parquetReader, columns, fieldByName, offset, batchSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importBatch writes up to batchSize rows starting at offset in one query
+func importBatch(ctx *tiledb.Context, array *tiledb.Array, arrayType tiledb.ArrayType, parquetReader *reader.ParquetReader, columns []*parquet.SchemaElement, fields map[string]tiledb.BatchField, offset int64, batchSize int64) error {
+	query, err := tiledb.NewQuery(ctx, array)
+	if err != nil {
+		return err
+	}
+	defer query.Free()
+
+	var numRows int
+	for i, column := range columns {
+		values, _, _, err := parquetReader.ReadColumnByIndex(int64(i), batchSize)
+		if err != nil {
+			return fmt.Errorf("Error reading parquet column %s: %s", column.Name, err)
+		}
+		numRows = len(values)
+
+		field := fields[column.Name]
+		offsets, data, err := columnValues(column.Name, values, field.Datatype, field.CellValNum)
+		if err != nil {
+			return err
+		}
+
+		if field.IsVar() {
+			_, _, err = query.SetBufferVar(field.Name, offsets, data)
+		} else {
+			_, err = query.SetBuffer(field.Name, data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if arrayType == tiledb.TILEDB_DENSE {
+		err = query.SetLayout(tiledb.TILEDB_ROW_MAJOR)
+		if err != nil {
+			return err
+		}
+		err = query.SetSubArray([]uint64{uint64(offset), uint64(offset) + uint64(numRows) - 1})
+		if err != nil {
+			return err
+		}
+	} else {
+		err = query.SetLayout(tiledb.TILEDB_UNORDERED)
+		if err != nil {
+			return err
+		}
+	}
+
+	return query.Submit()
+}
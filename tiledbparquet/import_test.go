@@ -0,0 +1,215 @@
+package tiledbparquet
+
+import (
+	"io"
+	"math"
+	"os"
+	"path"
+	"testing"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// writeTestParquet writes a parquet file with an int32 column "x", a double
+// column "value" and a string column "name"
+func writeTestParquet(t *testing.T, tmpParquetPath string) {
+	file, err := local.NewLocalFileWriter(tmpParquetPath)
+	assert.Nil(t, err)
+
+	parquetWriter, err := writer.NewCSVWriter([]string{
+		"name=x, type=INT32",
+		"name=value, type=DOUBLE",
+		"name=name, type=UTF8",
+	}, file, 1)
+	assert.Nil(t, err)
+
+	assert.Nil(t, parquetWriter.Write([]interface{}{int32(3), float64(0.5), "c"}))
+	assert.Nil(t, parquetWriter.Write([]interface{}{int32(1), float64(1.5), "a"}))
+	assert.Nil(t, parquetWriter.Write([]interface{}{int32(2), float64(2.5), "bb"}))
+	assert.Nil(t, parquetWriter.WriteStop())
+	assert.Nil(t, file.Close())
+}
+
+func TestImportSparse(t *testing.T) {
+	context, err := tiledb.NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpParquetPath := path.Join(os.TempDir(), "tiledb_test_parquet_sparse.parquet")
+	defer os.Remove(tmpParquetPath)
+	writeTestParquet(t, tmpParquetPath)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_parquet_sparse")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	file, err := local.NewLocalFileReader(tmpParquetPath)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	// A batch size of two writes two fragments
+	err = Import(context, file, tmpArrayPath, &ImportOptions{
+		ArrayType:  tiledb.TILEDB_SPARSE,
+		Dimensions: []string{"x"},
+		BatchSize:  2,
+	})
+	assert.Nil(t, err)
+
+	array, err := tiledb.NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(tiledb.TILEDB_READ))
+	defer array.Close()
+
+	schema, err := array.Schema()
+	assert.Nil(t, err)
+	domain, err := schema.Domain()
+	assert.Nil(t, err)
+	dimension, err := domain.DimensionFromName("x")
+	assert.Nil(t, err)
+	dimensionDomain, err := dimension.Domain()
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 3}, dimensionDomain)
+
+	reader, err := tiledb.NewBatchReader(context, array, []string{"x", "value", "name"}, 16)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(tiledb.TILEDB_ROW_MAJOR))
+
+	batch, err := reader.Next()
+	assert.Nil(t, err)
+	x, err := batch.Data("x")
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, x)
+	value, err := batch.Data("value")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1.5, 2.5, 0.5}, value)
+	name, err := batch.Cell("name", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "bb", name)
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestImportDenseAndExport(t *testing.T) {
+	context, err := tiledb.NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpParquetPath := path.Join(os.TempDir(), "tiledb_test_parquet_dense.parquet")
+	defer os.Remove(tmpParquetPath)
+	writeTestParquet(t, tmpParquetPath)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_parquet_dense")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+
+	file, err := local.NewLocalFileReader(tmpParquetPath)
+	assert.Nil(t, err)
+	defer file.Close()
+	assert.Nil(t, Import(context, file, tmpArrayPath, nil))
+
+	array, err := tiledb.NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(tiledb.TILEDB_READ))
+	defer array.Close()
+
+	// Export the last two rows
+	reader, err := tiledb.NewBatchReader(context, array, []string{"x", "name"}, 16)
+	assert.Nil(t, err)
+	defer reader.Free()
+	assert.Nil(t, reader.Query().SetLayout(tiledb.TILEDB_ROW_MAJOR))
+	assert.Nil(t, reader.Query().SetSubArray([]uint64{1, 2}))
+
+	tmpExportPath := path.Join(os.TempDir(), "tiledb_test_parquet_export.parquet")
+	defer os.Remove(tmpExportPath)
+	out, err := os.Create(tmpExportPath)
+	assert.Nil(t, err)
+	assert.Nil(t, Export(out, reader))
+	assert.Nil(t, out.Close())
+
+	// Import the export again and check the values
+	tmpReimportPath := path.Join(os.TempDir(), "tiledb_test_parquet_reimport")
+	defer os.RemoveAll(tmpReimportPath)
+	if _, err = os.Stat(tmpReimportPath); err == nil {
+		os.RemoveAll(tmpReimportPath)
+	}
+
+	exported, err := local.NewLocalFileReader(tmpExportPath)
+	assert.Nil(t, err)
+	defer exported.Close()
+	assert.Nil(t, Import(context, exported, tmpReimportPath, nil))
+
+	reimported, err := tiledb.NewArray(context, tmpReimportPath)
+	assert.Nil(t, err)
+	assert.Nil(t, reimported.Open(tiledb.TILEDB_READ))
+	defer reimported.Close()
+
+	reimportReader, err := tiledb.NewBatchReader(context, reimported, nil, 16)
+	assert.Nil(t, err)
+	defer reimportReader.Free()
+	assert.Equal(t, 3, len(reimportReader.Fields()))
+
+	batch, err := reimportReader.Next()
+	assert.Nil(t, err)
+	x, err := batch.Data("x")
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 2}, x)
+	name, err := batch.Cell("name", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "a", name)
+}
+
+func TestColumnValues(t *testing.T) {
+	_, data, err := columnValues("value", []interface{}{float64(0.5), float64(1.5)}, tiledb.TILEDB_FLOAT64, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.5, 1.5}, data)
+
+	// Values of another type are rejected instead of read as zero
+	_, _, err = columnValues("value", []interface{}{float64(0.5), int32(1)}, tiledb.TILEDB_FLOAT64, 1)
+	assert.NotNil(t, err)
+	_, _, err = columnValues("value", []interface{}{float64(0.5)}, tiledb.TILEDB_FLOAT32, 1)
+	assert.NotNil(t, err)
+
+	_, data, err = columnValues("count", []interface{}{int32(1), true}, tiledb.TILEDB_INT16, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int16{1, 1}, data)
+	_, _, err = columnValues("count", []interface{}{int32(1), "2"}, tiledb.TILEDB_INT16, 1)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "count")
+	assert.Contains(t, err.Error(), "string")
+	_, _, err = columnValues("time", []interface{}{float32(1)}, tiledb.TILEDB_DATETIME_MS, 1)
+	assert.NotNil(t, err)
+}
+
+func TestColumnBoundsDomain(t *testing.T) {
+	bounds := &columnBounds{}
+	assert.Nil(t, bounds.update([]int32{3, 1, 2}))
+	domain, extent := bounds.domain(2)
+	assert.Equal(t, []int32{1, 3}, domain)
+	assert.Equal(t, int32(2), extent)
+
+	// A single float value is widened so the extent fits the domain
+	bounds = &columnBounds{}
+	assert.Nil(t, bounds.update([]float64{2.5, 2.5}))
+	domain, extent = bounds.domain(2)
+	floatDomain := domain.([]float64)
+	assert.Equal(t, 2.5, floatDomain[0])
+	assert.True(t, floatDomain[1] > 2.5)
+	assert.True(t, extent.(float64) > 0)
+	assert.Equal(t, floatDomain[1]-floatDomain[0], extent)
+
+	bounds = &columnBounds{}
+	assert.Nil(t, bounds.update([]float32{1}))
+	domain, extent = bounds.domain(2)
+	assert.True(t, domain.([]float32)[1] > 1)
+	assert.True(t, extent.(float32) > 0)
+
+	bounds = &columnBounds{}
+	assert.NotNil(t, bounds.update([]float64{1, math.NaN()}))
+}
@@ -0,0 +1,248 @@
+/*
+Package tiledbparquet imports Parquet files into TileDB arrays and exports
+TileDB arrays back to Parquet.
+
+Only flat Parquet schemas are supported, nested and repeated columns are
+rejected. Parquet logical types are mapped to the closest TileDB datatype:
+
+	BOOLEAN                           TILEDB_UINT8
+	INT32 (INT_8, INT_16, ...)        TILEDB_INT8, TILEDB_INT16, ...
+	INT32 (DATE)                      TILEDB_DATETIME_DAY
+	INT64 (TIMESTAMP MILLIS/MICROS)   TILEDB_DATETIME_MS, TILEDB_DATETIME_US
+	INT64 (TIMESTAMP NANOS)           TILEDB_DATETIME_NS
+	FLOAT, DOUBLE                     TILEDB_FLOAT32, TILEDB_FLOAT64
+	BYTE_ARRAY (UTF8, JSON, ENUM)     TILEDB_STRING_UTF8, variable sized
+	BYTE_ARRAY                        TILEDB_CHAR, variable sized
+	FIXED_LEN_BYTE_ARRAY              TILEDB_CHAR, one value per byte
+
+Datetime attributes without a matching Parquet type (e.g. TILEDB_DATETIME_SEC)
+are exported as INT64 columns, their datatype is kept in the key-value
+metadata of the file under DatatypeMetadataPrefix and restored on import.
+Dimensions of sparse arrays have the datatype of their column and the range
+of its values as domain, text columns become string dimensions. Datetime
+columns are imported as TILEDB_INT64 dimensions, which the Go API can not
+create with a datetime datatype.
+
+INT96 and DECIMAL columns are not supported. TileDB (in the version supported
+by this package) has no nullable attributes, so columns containing nulls can
+not be imported.
+*/
+package tiledbparquet
+
+import (
+	"fmt"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+// DatatypeMetadataPrefix prefixes the keys of the Parquet key-value metadata
+// holding the TileDB datatype of exported datetime columns, e.g.
+// "tiledb.datatype.a1" = "DATETIME_SEC"
+const DatatypeMetadataPrefix = "tiledb.datatype."
+
+// Datatype returns the TileDB datatype and the number of values per cell
+// used to store a Parquet column
+func Datatype(element *parquet.SchemaElement) (tiledb.Datatype, uint, error) {
+	if element.GetNumChildren() > 0 || element.GetRepetitionType() == parquet.FieldRepetitionType_REPEATED {
+		return 0, 0, fmt.Errorf("Nested or repeated column %s is not supported", element.Name)
+	}
+
+	logicalType := element.GetLogicalType()
+	if element.IsSetConvertedType() && element.GetConvertedType() == parquet.ConvertedType_DECIMAL ||
+		logicalType != nil && logicalType.IsSetDECIMAL() {
+		return 0, 0, fmt.Errorf("Decimal column %s is not supported", element.Name)
+	}
+
+	switch element.GetType() {
+	case parquet.Type_BOOLEAN:
+		return tiledb.TILEDB_UINT8, 1, nil
+	case parquet.Type_INT32:
+		if logicalType != nil && logicalType.IsSetDATE() {
+			return tiledb.TILEDB_DATETIME_DAY, 1, nil
+		}
+		if logicalType != nil && logicalType.IsSetINTEGER() {
+			return integerDatatype(logicalType.GetINTEGER()), 1, nil
+		}
+		if element.IsSetConvertedType() {
+			switch element.GetConvertedType() {
+			case parquet.ConvertedType_INT_8:
+				return tiledb.TILEDB_INT8, 1, nil
+			case parquet.ConvertedType_INT_16:
+				return tiledb.TILEDB_INT16, 1, nil
+			case parquet.ConvertedType_UINT_8:
+				return tiledb.TILEDB_UINT8, 1, nil
+			case parquet.ConvertedType_UINT_16:
+				return tiledb.TILEDB_UINT16, 1, nil
+			case parquet.ConvertedType_UINT_32:
+				return tiledb.TILEDB_UINT32, 1, nil
+			case parquet.ConvertedType_DATE:
+				return tiledb.TILEDB_DATETIME_DAY, 1, nil
+			}
+		}
+		return tiledb.TILEDB_INT32, 1, nil
+	case parquet.Type_INT64:
+		if logicalType != nil && logicalType.IsSetTIMESTAMP() {
+			unit := logicalType.GetTIMESTAMP().GetUnit()
+			switch {
+			case unit.IsSetMILLIS():
+				return tiledb.TILEDB_DATETIME_MS, 1, nil
+			case unit.IsSetMICROS():
+				return tiledb.TILEDB_DATETIME_US, 1, nil
+			case unit.IsSetNANOS():
+				return tiledb.TILEDB_DATETIME_NS, 1, nil
+			}
+		}
+		if logicalType != nil && logicalType.IsSetINTEGER() {
+			return integerDatatype(logicalType.GetINTEGER()), 1, nil
+		}
+		if element.IsSetConvertedType() {
+			switch element.GetConvertedType() {
+			case parquet.ConvertedType_UINT_64:
+				return tiledb.TILEDB_UINT64, 1, nil
+			case parquet.ConvertedType_TIMESTAMP_MILLIS:
+				return tiledb.TILEDB_DATETIME_MS, 1, nil
+			case parquet.ConvertedType_TIMESTAMP_MICROS:
+				return tiledb.TILEDB_DATETIME_US, 1, nil
+			}
+		}
+		return tiledb.TILEDB_INT64, 1, nil
+	case parquet.Type_FLOAT:
+		return tiledb.TILEDB_FLOAT32, 1, nil
+	case parquet.Type_DOUBLE:
+		return tiledb.TILEDB_FLOAT64, 1, nil
+	case parquet.Type_BYTE_ARRAY:
+		if logicalType != nil && (logicalType.IsSetSTRING() || logicalType.IsSetJSON() || logicalType.IsSetENUM()) {
+			return tiledb.TILEDB_STRING_UTF8, tiledb.TILEDB_VAR_NUM, nil
+		}
+		if element.IsSetConvertedType() {
+			switch element.GetConvertedType() {
+			case parquet.ConvertedType_UTF8, parquet.ConvertedType_JSON, parquet.ConvertedType_ENUM:
+				return tiledb.TILEDB_STRING_UTF8, tiledb.TILEDB_VAR_NUM, nil
+			}
+		}
+		return tiledb.TILEDB_CHAR, tiledb.TILEDB_VAR_NUM, nil
+	case parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		if element.GetTypeLength() <= 0 {
+			return 0, 0, fmt.Errorf("Invalid type length %d for column %s", element.GetTypeLength(), element.Name)
+		}
+		return tiledb.TILEDB_CHAR, uint(element.GetTypeLength()), nil
+	default:
+		return 0, 0, fmt.Errorf("Unsupported parquet type %s for column %s", element.GetType(), element.Name)
+	}
+}
+
+// integerDatatype returns the TileDB datatype for a Parquet INTEGER logical
+// type
+func integerDatatype(intType *parquet.IntType) tiledb.Datatype {
+	switch intType.GetBitWidth() {
+	case 8:
+		if intType.GetIsSigned() {
+			return tiledb.TILEDB_INT8
+		}
+		return tiledb.TILEDB_UINT8
+	case 16:
+		if intType.GetIsSigned() {
+			return tiledb.TILEDB_INT16
+		}
+		return tiledb.TILEDB_UINT16
+	case 32:
+		if intType.GetIsSigned() {
+			return tiledb.TILEDB_INT32
+		}
+		return tiledb.TILEDB_UINT32
+	default:
+		if intType.GetIsSigned() {
+			return tiledb.TILEDB_INT64
+		}
+		return tiledb.TILEDB_UINT64
+	}
+}
+
+// Metadata returns the parquet-go CSV writer metadata describing the Parquet
+// column used to export a TileDB attribute or dimension, e.g.
+// "name=a1, type=INT_8"
+func Metadata(field tiledb.BatchField) (string, error) {
+	var parquetType string
+	switch {
+	case field.Datatype == tiledb.TILEDB_CHAR && field.IsVar():
+		parquetType = "BYTE_ARRAY"
+	case field.Datatype == tiledb.TILEDB_CHAR && field.CellValNum > 1:
+		parquetType = fmt.Sprintf("FIXED_LEN_BYTE_ARRAY, length=%d", field.CellValNum)
+	case field.Datatype.IsString():
+		parquetType = "UTF8"
+	case field.IsVar() || field.CellValNum > 1:
+		return "", fmt.Errorf("Field %s with more than one value per cell can not be exported to parquet", field.Name)
+	default:
+		switch field.Datatype {
+		case tiledb.TILEDB_INT8:
+			parquetType = "INT_8"
+		case tiledb.TILEDB_INT16:
+			parquetType = "INT_16"
+		case tiledb.TILEDB_INT32:
+			parquetType = "INT32"
+		case tiledb.TILEDB_INT64:
+			parquetType = "INT64"
+		case tiledb.TILEDB_UINT8:
+			parquetType = "UINT_8"
+		case tiledb.TILEDB_UINT16:
+			parquetType = "UINT_16"
+		case tiledb.TILEDB_UINT32:
+			parquetType = "UINT_32"
+		case tiledb.TILEDB_UINT64:
+			parquetType = "UINT_64"
+		case tiledb.TILEDB_FLOAT32:
+			parquetType = "FLOAT"
+		case tiledb.TILEDB_FLOAT64:
+			parquetType = "DOUBLE"
+		case tiledb.TILEDB_DATETIME_DAY:
+			parquetType = "DATE"
+		case tiledb.TILEDB_DATETIME_MS:
+			parquetType = "TIMESTAMP_MILLIS"
+		case tiledb.TILEDB_DATETIME_US:
+			parquetType = "TIMESTAMP_MICROS"
+		default:
+			if !field.Datatype.IsDatetime() {
+				return "", fmt.Errorf("Unsupported datatype %s of field %s for parquet export", field.Datatype, field.Name)
+			}
+			// No matching parquet converted type, the raw int64 value is kept
+			parquetType = "INT64"
+		}
+	}
+
+	return fmt.Sprintf("name=%s, type=%s", field.Name, parquetType), nil
+}
+
+// DatatypeMetadata returns the Parquet key-value metadata recording the
+// datatype of the datetime fields exported to a Parquet file
+func DatatypeMetadata(fields []tiledb.BatchField) []*parquet.KeyValue {
+	metadata := make([]*parquet.KeyValue, 0)
+	for _, field := range fields {
+		if !field.Datatype.IsDatetime() {
+			continue
+		}
+		value := field.Datatype.String()
+		metadata = append(metadata, &parquet.KeyValue{Key: DatatypeMetadataPrefix + field.Name, Value: &value})
+	}
+	return metadata
+}
+
+// columnDatatype returns the datatype of a column like Datatype, restoring
+// the datetime datatype recorded by Export for INT64 columns
+func columnDatatype(footer *parquet.FileMetaData, element *parquet.SchemaElement) (tiledb.Datatype, uint, error) {
+	datatype, cellValNum, err := Datatype(element)
+	if err != nil || datatype != tiledb.TILEDB_INT64 {
+		return datatype, cellValNum, err
+	}
+
+	for _, kv := range footer.GetKeyValueMetadata() {
+		if kv.Key != DatatypeMetadataPrefix+element.Name || kv.Value == nil {
+			continue
+		}
+		var recorded tiledb.Datatype
+		if recorded.FromString(*kv.Value) == nil && recorded.IsDatetime() {
+			return recorded, cellValNum, nil
+		}
+	}
+	return datatype, cellValNum, nil
+}
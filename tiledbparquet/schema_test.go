@@ -0,0 +1,102 @@
+package tiledbparquet
+
+import (
+	"testing"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+func TestDatatype(t *testing.T) {
+	element := parquet.NewSchemaElement()
+	element.Name = "a"
+	element.Type = parquet.TypePtr(parquet.Type_INT32)
+	datatype, cellValNum, err := Datatype(element)
+	assert.Nil(t, err)
+	assert.Equal(t, tiledb.TILEDB_INT32, datatype)
+	assert.Equal(t, uint(1), cellValNum)
+
+	convertedType := parquet.ConvertedType_DATE
+	element.ConvertedType = &convertedType
+	datatype, _, err = Datatype(element)
+	assert.Nil(t, err)
+	assert.Equal(t, tiledb.TILEDB_DATETIME_DAY, datatype)
+
+	element = parquet.NewSchemaElement()
+	element.Name = "b"
+	element.Type = parquet.TypePtr(parquet.Type_INT64)
+	element.LogicalType = &parquet.LogicalType{TIMESTAMP: &parquet.TimestampType{
+		Unit: &parquet.TimeUnit{NANOS: parquet.NewNanoSeconds()},
+	}}
+	datatype, _, err = Datatype(element)
+	assert.Nil(t, err)
+	assert.Equal(t, tiledb.TILEDB_DATETIME_NS, datatype)
+
+	element = parquet.NewSchemaElement()
+	element.Name = "c"
+	element.Type = parquet.TypePtr(parquet.Type_FIXED_LEN_BYTE_ARRAY)
+	typeLength := int32(4)
+	element.TypeLength = &typeLength
+	datatype, cellValNum, err = Datatype(element)
+	assert.Nil(t, err)
+	assert.Equal(t, tiledb.TILEDB_CHAR, datatype)
+	assert.Equal(t, uint(4), cellValNum)
+
+	element = parquet.NewSchemaElement()
+	element.Name = "d"
+	element.Type = parquet.TypePtr(parquet.Type_INT96)
+	_, _, err = Datatype(element)
+	assert.NotNil(t, err)
+}
+
+func TestMetadata(t *testing.T) {
+	md, err := Metadata(tiledb.BatchField{Name: "a1", Datatype: tiledb.TILEDB_UINT16, CellValNum: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, "name=a1, type=UINT_16", md)
+
+	md, err = Metadata(tiledb.BatchField{Name: "a2", Datatype: tiledb.TILEDB_STRING_UTF8, CellValNum: tiledb.TILEDB_VAR_NUM})
+	assert.Nil(t, err)
+	assert.Equal(t, "name=a2, type=UTF8", md)
+
+	md, err = Metadata(tiledb.BatchField{Name: "a3", Datatype: tiledb.TILEDB_CHAR, CellValNum: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, "name=a3, type=FIXED_LEN_BYTE_ARRAY, length=4", md)
+
+	_, err = Metadata(tiledb.BatchField{Name: "a4", Datatype: tiledb.TILEDB_INT32, CellValNum: 2})
+	assert.NotNil(t, err)
+}
+
+func TestDatatypeMetadata(t *testing.T) {
+	fields := []tiledb.BatchField{
+		{Name: "a1", Datatype: tiledb.TILEDB_INT64, CellValNum: 1},
+		{Name: "a2", Datatype: tiledb.TILEDB_DATETIME_SEC, CellValNum: 1},
+		{Name: "a3", Datatype: tiledb.TILEDB_DATETIME_NS, CellValNum: 1},
+	}
+	footer := parquet.NewFileMetaData()
+	footer.KeyValueMetadata = DatatypeMetadata(fields)
+	assert.Equal(t, 2, len(footer.KeyValueMetadata))
+
+	// Datetime datatypes exported as INT64 are restored
+	for _, field := range fields {
+		md, err := Metadata(field)
+		assert.Nil(t, err)
+		assert.Equal(t, "name="+field.Name+", type=INT64", md)
+
+		element := parquet.NewSchemaElement()
+		element.Name = field.Name
+		element.Type = parquet.TypePtr(parquet.Type_INT64)
+		datatype, cellValNum, err := columnDatatype(footer, element)
+		assert.Nil(t, err)
+		assert.Equal(t, field.Datatype, datatype)
+		assert.Equal(t, uint(1), cellValNum)
+	}
+
+	// Other columns keep the datatype of their parquet type
+	element := parquet.NewSchemaElement()
+	element.Name = "a2"
+	element.Type = parquet.TypePtr(parquet.Type_DOUBLE)
+	datatype, _, err := columnDatatype(footer, element)
+	assert.Nil(t, err)
+	assert.Equal(t, tiledb.TILEDB_FLOAT64, datatype)
+}
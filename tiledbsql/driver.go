@@ -0,0 +1,161 @@
+/*
+Package tiledbsql is a database/sql driver querying TileDB arrays.
+
+	import _ "github.com/TileDB-Inc/TileDB-Go/tiledbsql"
+
+	db, err := sql.Open("tiledb", "")
+	rows, err := db.Query("SELECT a1, a2 FROM my_array WHERE rows BETWEEN ? AND ? AND a1 > 10", 1, 4)
+
+Each query reads a single array, the table name being the array URI. Only
+SELECT statements are supported, with an optional WHERE clause joining
+comparisons of a dimension or attribute with a value using AND, and an
+optional LIMIT. Comparisons on numeric dimensions are pushed down to the
+query as ranges, all comparisons are also applied to each cell read.
+Results are streamed in batches from incomplete reads.
+
+The data source name is a URL encoded list of TileDB config parameters, e.g.
+"vfs.s3.region=us-east-1&sm.tile_cache_size=0". The parameter batch_size
+sets the number of cells read per query.
+
+Integer values are returned as int64, floats as float64, strings as string,
+datetimes as time.Time and cells with more than one value as their JSON
+encoding.
+*/
+package tiledbsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// DefaultBatchSize is the number of cells read per query when the data
+// source name does not set batch_size
+const DefaultBatchSize = 10000
+
+func init() {
+	sql.Register("tiledb", &Driver{})
+}
+
+// Driver is the database/sql driver registered as "tiledb"
+type Driver struct{}
+
+// Open returns a new connection, see the package documentation for the
+// format of the data source name
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	params, err := url.ParseQuery(name)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing data source name: %s", err)
+	}
+
+	config, err := tiledb.NewConfig()
+	if err != nil {
+		return nil, err
+	}
+	defer config.Free()
+
+	batchSize := uint64(DefaultBatchSize)
+	for key, values := range params {
+		value := values[len(values)-1]
+		if key == "batch_size" {
+			batchSize, err = strconv.ParseUint(value, 10, 64)
+			if err != nil || batchSize == 0 {
+				return nil, fmt.Errorf("Invalid batch_size %q", value)
+			}
+			continue
+		}
+
+		err = config.Set(key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	context, err := tiledb.NewContext(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{context: context, batchSize: batchSize}, nil
+}
+
+// conn is a driver.Conn holding a TileDB context
+type conn struct {
+	context   *tiledb.Context
+	batchSize uint64
+}
+
+// Prepare parses a SELECT statement
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	statement, err := parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing query: %s", err)
+	}
+	return &stmt{conn: c, statement: statement}, nil
+}
+
+// Close frees the context of the connection
+func (c *conn) Close() error {
+	c.context.Free()
+	return nil
+}
+
+// Begin is not supported, TileDB has no transactions
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("Transactions are not supported by the tiledb driver")
+}
+
+// stmt is a prepared SELECT statement
+type stmt struct {
+	conn      *conn
+	statement *selectStatement
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return s.statement.numInput
+}
+
+// Exec is not supported, only SELECT statements can be executed
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, fmt.Errorf("Only SELECT statements are supported by the tiledb driver")
+}
+
+// Query opens the array and starts streaming the results of the statement
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	predicates := make([]predicate, len(s.statement.predicates))
+	for i, p := range s.statement.predicates {
+		if p.placeholder >= 0 {
+			if p.placeholder >= len(args) {
+				return nil, fmt.Errorf("Missing argument %d", p.placeholder+1)
+			}
+			p.value = args[p.placeholder]
+		}
+		predicates[i] = p
+	}
+
+	array, err := tiledb.NewArray(s.conn.context, s.statement.uri)
+	if err != nil {
+		return nil, err
+	}
+
+	err = array.Open(tiledb.TILEDB_READ)
+	if err != nil {
+		array.Free()
+		return nil, err
+	}
+
+	r, err := newRows(s.conn.context, array, s.statement.columns, predicates, s.statement.limit, s.conn.batchSize)
+	if err != nil {
+		array.Free()
+		return nil, err
+	}
+	return r, nil
+}
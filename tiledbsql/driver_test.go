@@ -0,0 +1,127 @@
+package tiledbsql
+
+import (
+	"database/sql"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+	"github.com/stretchr/testify/assert"
+)
+
+// createSQLTestArray creates a 1D sparse array with an int32 attribute "a1",
+// a string attribute "a2" and a datetime attribute "a3" holding 10 cells
+func createSQLTestArray(t *testing.T, context *tiledb.Context, tmpArrayPath string) {
+	dimension, err := tiledb.NewDimension(context, "rows", []int32{1, 10}, int32(5))
+	assert.Nil(t, err)
+	domain, err := tiledb.NewDomain(context)
+	assert.Nil(t, err)
+	assert.Nil(t, domain.AddDimensions(dimension))
+
+	arraySchema, err := tiledb.NewArraySchema(context, tiledb.TILEDB_SPARSE)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.SetDomain(domain))
+
+	a1, err := tiledb.NewAttribute(context, "a1", tiledb.TILEDB_INT32)
+	assert.Nil(t, err)
+	a2, err := tiledb.NewAttribute(context, "a2", tiledb.TILEDB_STRING_ASCII)
+	assert.Nil(t, err)
+	assert.Nil(t, a2.SetCellValNum(tiledb.TILEDB_VAR_NUM))
+	a3, err := tiledb.NewAttribute(context, "a3", tiledb.TILEDB_DATETIME_DAY)
+	assert.Nil(t, err)
+	assert.Nil(t, arraySchema.AddAttributes(a1, a2, a3))
+
+	array, err := tiledb.NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Create(arraySchema))
+	assert.Nil(t, array.Open(tiledb.TILEDB_WRITE))
+	defer array.Close()
+
+	query, err := tiledb.NewQuery(context, array)
+	assert.Nil(t, err)
+	assert.Nil(t, query.SetLayout(tiledb.TILEDB_UNORDERED))
+
+	rows := make([]int32, 10)
+	a1Values := make([]int32, 10)
+	a2Offsets := make([]uint64, 10)
+	a2Values := make([]byte, 0)
+	a3Values := make([]int64, 10)
+	for i := 0; i < 10; i++ {
+		rows[i] = int32(i + 1)
+		a1Values[i] = int32(10 * (i + 1))
+		a2Offsets[i] = uint64(len(a2Values))
+		a2Values = append(a2Values, byte('a'+i))
+		// Days since 2020-01-01
+		a3Values[i] = int64(18262 + i)
+	}
+
+	_, err = query.SetBuffer("rows", rows)
+	assert.Nil(t, err)
+	_, err = query.SetBuffer("a1", a1Values)
+	assert.Nil(t, err)
+	_, _, err = query.SetBufferVar("a2", a2Offsets, a2Values)
+	assert.Nil(t, err)
+	_, err = query.SetBuffer("a3", a3Values)
+	assert.Nil(t, err)
+	assert.Nil(t, query.Submit())
+}
+
+func TestDriver(t *testing.T) {
+	context, err := tiledb.NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_sql")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createSQLTestArray(t, context, tmpArrayPath)
+
+	// A batch size of 2 forces incomplete reads
+	db, err := sql.Open("tiledb", "batch_size=2")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	rows, err := db.Query("SELECT rows, a2 FROM `"+tmpArrayPath+"` WHERE rows BETWEEN ? AND 8 AND a1 != 50", 3)
+	assert.Nil(t, err)
+	columns, err := rows.Columns()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"rows", "a2"}, columns)
+
+	ids := make([]int64, 0)
+	names := make([]string, 0)
+	for rows.Next() {
+		var id int64
+		var name string
+		assert.Nil(t, rows.Scan(&id, &name))
+		ids = append(ids, id)
+		names = append(names, name)
+	}
+	assert.Nil(t, rows.Err())
+	assert.Nil(t, rows.Close())
+	assert.Equal(t, []int64{3, 4, 6, 7, 8}, ids)
+	assert.Equal(t, []string{"c", "d", "f", "g", "h"}, names)
+
+	// Datetimes are returned as time.Time and compared with strings
+	var day time.Time
+	err = db.QueryRow("SELECT a3 FROM `" + tmpArrayPath + "` WHERE a3 >= '2020-01-05' LIMIT 1").Scan(&day)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC), day.UTC())
+
+	// Empty ranges return no rows
+	var count int
+	rows, err = db.Query("SELECT a1 FROM `" + tmpArrayPath + "` WHERE rows > 5 AND rows < 3")
+	assert.Nil(t, err)
+	for rows.Next() {
+		count++
+	}
+	assert.Nil(t, rows.Err())
+	assert.Equal(t, 0, count)
+
+	_, err = db.Exec("SELECT a1 FROM `" + tmpArrayPath + "`")
+	assert.NotNil(t, err)
+	_, err = db.Query("SELECT nope FROM `" + tmpArrayPath + "`")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,326 @@
+package tiledbsql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenSymbol
+	tokenPlaceholder
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// wordDelimiters end a bare word, they are either symbols or quotes
+const wordDelimiters = ",*=<>!()'\"`?"
+
+// tokenize splits a statement into tokens. Bare words may contain slashes,
+// dots and colons so that array URIs do not need to be quoted.
+func tokenize(statement string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(statement)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("Unterminated quote at position %d", i)
+			}
+			kind := tokenIdentifier
+			if r == '\'' {
+				kind = tokenString
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[i+1 : end])})
+			i = end + 1
+		case r == '?':
+			tokens = append(tokens, token{kind: tokenPlaceholder, text: "?"})
+			i++
+		case strings.ContainsRune("<>!", r):
+			if i+1 < len(runes) && (runes[i+1] == '=' || r == '<' && runes[i+1] == '>') {
+				tokens = append(tokens, token{kind: tokenSymbol, text: string(runes[i : i+2])})
+				i += 2
+			} else if r == '!' {
+				return nil, fmt.Errorf("Unexpected character ! at position %d", i)
+			} else {
+				tokens = append(tokens, token{kind: tokenSymbol, text: string(r)})
+				i++
+			}
+		case strings.ContainsRune(wordDelimiters, r):
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r)})
+			i++
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(wordDelimiters, runes[end]) {
+				end++
+			}
+			text := string(runes[i:end])
+			kind := tokenWord
+			if isNumber(text) {
+				kind = tokenNumber
+			}
+			tokens = append(tokens, token{kind: kind, text: text})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// isNumber returns true for numeric literals. Words like inf or nan are
+// parsed by strconv but are column names, so numbers must start with a
+// digit or a dot, after an optional sign.
+func isNumber(text string) bool {
+	digits := strings.TrimLeft(text, "+-")
+	if len(digits) == 0 || len(text)-len(digits) > 1 {
+		return false
+	}
+	if digits[0] != '.' && (digits[0] < '0' || digits[0] > '9') {
+		return false
+	}
+	_, err := strconv.ParseFloat(text, 64)
+	return err == nil
+}
+
+// predicate is a comparison of a column with a literal or a placeholder
+type predicate struct {
+	column string
+	op     string
+	value  interface{}
+	// placeholder is the index of the statement argument holding the value,
+	// or -1 for literals
+	placeholder int
+}
+
+// selectStatement is a parsed SELECT statement
+type selectStatement struct {
+	// columns are the selected dimensions and attributes, nil for *
+	columns    []string
+	uri        string
+	predicates []predicate
+	// limit is the maximum number of rows returned, -1 for no limit
+	limit    int64
+	numInput int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (token, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return token{}, fmt.Errorf("Unexpected end of statement")
+	}
+	p.pos++
+	return tok, nil
+}
+
+// keyword consumes the next token if it is the given keyword
+func (p *parser) keyword(keyword string) bool {
+	tok, ok := p.peek()
+	if ok && tok.kind == tokenWord && strings.EqualFold(tok.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.keyword(keyword) {
+		tok, _ := p.peek()
+		return fmt.Errorf("Expected %s, got %q", keyword, tok.text)
+	}
+	return nil
+}
+
+func (p *parser) identifier() (string, error) {
+	tok, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if tok.kind != tokenWord && tok.kind != tokenIdentifier {
+		return "", fmt.Errorf("Expected a column name, got %q", tok.text)
+	}
+	return tok.text, nil
+}
+
+// value parses a literal or a placeholder, returning the placeholder index
+func (p *parser) value(statement *selectStatement) (interface{}, int, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, -1, err
+	}
+
+	switch tok.kind {
+	case tokenPlaceholder:
+		statement.numInput++
+		return nil, statement.numInput - 1, nil
+	case tokenString:
+		return tok.text, -1, nil
+	case tokenNumber:
+		if i, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return i, -1, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		return f, -1, err
+	default:
+		return nil, -1, fmt.Errorf("Expected a value, got %q", tok.text)
+	}
+}
+
+func (p *parser) predicate(statement *selectStatement) error {
+	column, err := p.identifier()
+	if err != nil {
+		return err
+	}
+
+	if p.keyword("BETWEEN") {
+		lower, lowerPlaceholder, err := p.value(statement)
+		if err != nil {
+			return err
+		}
+		err = p.expectKeyword("AND")
+		if err != nil {
+			return err
+		}
+		upper, upperPlaceholder, err := p.value(statement)
+		if err != nil {
+			return err
+		}
+		statement.predicates = append(statement.predicates,
+			predicate{column: column, op: ">=", value: lower, placeholder: lowerPlaceholder},
+			predicate{column: column, op: "<=", value: upper, placeholder: upperPlaceholder})
+		return nil
+	}
+
+	op, err := p.next()
+	if err != nil {
+		return err
+	}
+	switch op.text {
+	case "=", "!=", "<>", "<", "<=", ">", ">=":
+	default:
+		return fmt.Errorf("Unsupported operator %q", op.text)
+	}
+	if op.text == "<>" {
+		op.text = "!="
+	}
+
+	value, placeholder, err := p.value(statement)
+	if err != nil {
+		return err
+	}
+	statement.predicates = append(statement.predicates, predicate{column: column, op: op.text, value: value, placeholder: placeholder})
+	return nil
+}
+
+/*
+parse parses the supported subset of SELECT:
+
+	SELECT * | column [, column ...] FROM uri
+		[WHERE column op value [AND ...]] [LIMIT n]
+
+op is one of =, !=, <>, <, <=, >, >= or BETWEEN value AND value and value is
+a number, a 'string' or a ? placeholder. Column names and URIs may be quoted
+with double quotes or backticks.
+*/
+func parse(query string) (*selectStatement, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	statement := &selectStatement{limit: -1}
+
+	err = p.expectKeyword("SELECT")
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, ok := p.peek(); ok && tok.kind == tokenSymbol && tok.text == "*" {
+		p.pos++
+	} else {
+		for {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			statement.columns = append(statement.columns, column)
+
+			tok, ok := p.peek()
+			if !ok || tok.kind != tokenSymbol || tok.text != "," {
+				break
+			}
+			p.pos++
+		}
+	}
+
+	err = p.expectKeyword("FROM")
+	if err != nil {
+		return nil, err
+	}
+
+	uri, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if uri.kind != tokenWord && uri.kind != tokenIdentifier && uri.kind != tokenString {
+		return nil, fmt.Errorf("Expected an array URI, got %q", uri.text)
+	}
+	statement.uri = uri.text
+
+	if p.keyword("WHERE") {
+		for {
+			err = p.predicate(statement)
+			if err != nil {
+				return nil, err
+			}
+			if !p.keyword("AND") {
+				break
+			}
+		}
+	}
+
+	if p.keyword("LIMIT") {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		statement.limit, err = strconv.ParseInt(tok.text, 10, 64)
+		if err != nil || statement.limit < 0 {
+			return nil, fmt.Errorf("Invalid LIMIT %q", tok.text)
+		}
+	}
+
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("Unexpected %q after end of statement", tok.text)
+	}
+
+	return statement, nil
+}
@@ -0,0 +1,56 @@
+package tiledbsql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	statement, err := parse("SELECT * FROM /tmp/my_array")
+	assert.Nil(t, err)
+	assert.Nil(t, statement.columns)
+	assert.Equal(t, "/tmp/my_array", statement.uri)
+	assert.Equal(t, int64(-1), statement.limit)
+
+	statement, err = parse("select a1, \"a 2\" from 's3://bucket/array' " +
+		"where rows between 1 and ? and a1 <> -2.5 and a2 = 'x' limit 10")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a1", "a 2"}, statement.columns)
+	assert.Equal(t, "s3://bucket/array", statement.uri)
+	assert.Equal(t, int64(10), statement.limit)
+	assert.Equal(t, 1, statement.numInput)
+	assert.Equal(t, []predicate{
+		{column: "rows", op: ">=", value: int64(1), placeholder: -1},
+		{column: "rows", op: "<=", placeholder: 0},
+		{column: "a1", op: "!=", value: -2.5, placeholder: -1},
+		{column: "a2", op: "=", value: "x", placeholder: -1},
+	}, statement.predicates)
+
+	for _, query := range []string{
+		"DELETE FROM a",
+		"SELECT FROM a",
+		"SELECT a1 FROM a WHERE a1 LIKE 'x'",
+		"SELECT a1 FROM a WHERE a1 = 'x",
+		"SELECT a1 FROM a LIMIT -1",
+		"SELECT a1 FROM a ORDER BY a1",
+	} {
+		_, err = parse(query)
+		assert.NotNil(t, err, query)
+	}
+}
+
+func TestParseNumberLikeColumns(t *testing.T) {
+	// inf, nan and infinity are column names, not numbers
+	statement, err := parse("SELECT inf, nan FROM a WHERE infinity > 1 AND nan = .5 AND inf < -3")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"inf", "nan"}, statement.columns)
+	assert.Equal(t, []predicate{
+		{column: "infinity", op: ">", value: int64(1), placeholder: -1},
+		{column: "nan", op: "=", value: 0.5, placeholder: -1},
+		{column: "inf", op: "<", value: int64(-3), placeholder: -1},
+	}, statement.predicates)
+
+	_, err = parse("SELECT a1 FROM a WHERE a1 = -inf")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,406 @@
+package tiledbsql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+
+	tiledb "github.com/TileDB-Inc/TileDB-Go"
+)
+
+// cellValue converts a cell of a batch to a driver.Value
+func cellValue(batch *tiledb.Batch, field tiledb.BatchField, i uint64) (driver.Value, error) {
+	cell, err := batch.Cell(field.Name, i)
+	if err != nil {
+		return nil, err
+	}
+
+	if field.Datatype.IsDatetime() && !field.IsVar() && field.CellValNum == 1 {
+		return tiledb.GetTimeFromTimestamp(field.Datatype, cell.(int64)), nil
+	}
+
+	switch v := cell.(type) {
+	case string:
+		return v, nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return float64(v), nil
+		}
+		return int64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	default:
+		// Cells with more than one value
+		encoded, err := json.Marshal(cell)
+		if err != nil {
+			return nil, err
+		}
+		return string(encoded), nil
+	}
+}
+
+// parseTime parses a string compared with a datetime value
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// compareValues compares a cell value with a predicate value, returning
+// -1, 0 or 1
+func compareValues(a, b driver.Value) (int, error) {
+	if b == nil {
+		return 0, fmt.Errorf("Can not compare with NULL")
+	}
+
+	switch av := a.(type) {
+	case string:
+		if bv, ok := b.([]byte); ok {
+			return tiledb.CompareValues(av, string(bv))
+		}
+	case time.Time:
+		var t time.Time
+		switch bv := b.(type) {
+		case time.Time:
+			t = bv
+		case string:
+			var err error
+			t, err = parseTime(bv)
+			if err != nil {
+				return 0, fmt.Errorf("Invalid time %q: %s", bv, err)
+			}
+		default:
+			return 0, fmt.Errorf("Can not compare %T with %T", a, b)
+		}
+		if av.Before(t) {
+			return -1, nil
+		}
+		if av.After(t) {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	return tiledb.CompareValues(a, b)
+}
+
+// matches evaluates a predicate for a cell value
+func (p predicate) matches(value driver.Value) (bool, error) {
+	c, err := compareValues(value, p.value)
+	if err != nil {
+		return false, fmt.Errorf("Error evaluating %s %s: %s", p.column, p.op, err)
+	}
+
+	// NaN cells only match !=, as in IEEE 754
+	if f, ok := value.(float64); ok && math.IsNaN(f) {
+		return p.op == "!=", nil
+	}
+
+	switch p.op {
+	case "=":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+// less compares two values of the same numeric kind
+func less(a, b reflect.Value) bool {
+	c, _ := tiledb.CompareValues(a.Interface(), b.Interface())
+	return c < 0
+}
+
+// boundValue converts a predicate value to the type of a dimension. It
+// returns false when the value can not be represented exactly, in which case
+// the predicate is only applied to the cells read.
+func boundValue(elemType reflect.Type, value driver.Value) (reflect.Value, bool) {
+	bound := reflect.New(elemType).Elem()
+	switch bound.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, ok := value.(int64)
+		if !ok || bound.OverflowInt(v) {
+			return bound, false
+		}
+		bound.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, ok := value.(int64)
+		if !ok || v < 0 || bound.OverflowUint(uint64(v)) {
+			return bound, false
+		}
+		bound.SetUint(uint64(v))
+	case reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case int64:
+			bound.SetFloat(float64(v))
+		case float64:
+			bound.SetFloat(v)
+		default:
+			return bound, false
+		}
+	default:
+		return bound, false
+	}
+	return bound, true
+}
+
+// addRanges pushes the predicates on numeric dimensions down to the query
+// as one range per dimension. It returns false if the ranges are empty.
+func addRanges(array *tiledb.Array, query *tiledb.Query, predicates []predicate) (bool, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return false, err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return false, err
+	}
+	defer domain.Free()
+
+	nDim, err := domain.NDim()
+	if err != nil {
+		return false, err
+	}
+
+	for dimIdx := uint(0); dimIdx < nDim; dimIdx++ {
+		dimension, err := domain.DimensionFromIndex(dimIdx)
+		if err != nil {
+			return false, err
+		}
+		defer dimension.Free()
+
+		name, err := dimension.Name()
+		if err != nil {
+			return false, err
+		}
+
+		datatype, err := dimension.Type()
+		if err != nil {
+			return false, err
+		}
+		if datatype.IsString() || datatype.IsDatetime() {
+			continue
+		}
+
+		dimensionDomain, err := dimension.Domain()
+		if err != nil {
+			return false, err
+		}
+		bounds := reflect.ValueOf(dimensionDomain)
+		lower, upper := bounds.Index(0), bounds.Index(1)
+
+		restricted := false
+		for _, p := range predicates {
+			if p.column != name || p.op == "!=" {
+				continue
+			}
+
+			value, ok := boundValue(lower.Type(), p.value)
+			if !ok {
+				continue
+			}
+
+			if (p.op == "=" || p.op == ">" || p.op == ">=") && less(lower, value) {
+				lower = value
+				restricted = true
+			}
+			if (p.op == "=" || p.op == "<" || p.op == "<=") && less(value, upper) {
+				upper = value
+				restricted = true
+			}
+		}
+
+		if less(upper, lower) {
+			return false, nil
+		}
+
+		if restricted {
+			err = query.AddRange(uint32(dimIdx), lower.Interface(), upper.Interface())
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// rows is a driver.Rows streaming the cells of a BatchReader
+type rows struct {
+	array      *tiledb.Array
+	reader     *tiledb.BatchReader
+	columns    []string
+	predicates []predicate
+	limit      int64
+	returned   int64
+	batch      *tiledb.Batch
+	cell       uint64
+	done       bool
+}
+
+func newRows(context *tiledb.Context, array *tiledb.Array, columns []string, predicates []predicate, limit int64, batchSize uint64) (*rows, error) {
+	schema, err := array.Schema()
+	if err != nil {
+		return nil, err
+	}
+	defer schema.Free()
+
+	fields, err := schema.Fields()
+	if err != nil {
+		return nil, err
+	}
+
+	if columns == nil {
+		for _, field := range fields {
+			columns = append(columns, field.Name)
+		}
+	}
+
+	// The reader also reads the columns only used in predicates
+	names := append([]string{}, columns...)
+	for _, p := range predicates {
+		found := false
+		for _, name := range names {
+			found = found || name == p.column
+		}
+		if !found {
+			names = append(names, p.column)
+		}
+	}
+
+	reader, err := tiledb.NewBatchReader(context, array, names, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	err = reader.Query().SetLayout(tiledb.TILEDB_ROW_MAJOR)
+	if err != nil {
+		reader.Free()
+		return nil, err
+	}
+
+	nonEmpty, err := addRanges(array, reader.Query(), predicates)
+	if err != nil {
+		reader.Free()
+		return nil, err
+	}
+
+	return &rows{
+		array:      array,
+		reader:     reader,
+		columns:    columns,
+		predicates: predicates,
+		limit:      limit,
+		done:       !nonEmpty || limit == 0,
+	}, nil
+}
+
+// Columns returns the names of the selected columns
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+// Close frees the reader and closes the array
+func (r *rows) Close() error {
+	r.reader.Free()
+	r.array.Free()
+	return nil
+}
+
+// Next reads the next cell matching all predicates into dest
+func (r *rows) Next(dest []driver.Value) error {
+	for {
+		if r.done {
+			return io.EOF
+		}
+
+		if r.batch == nil || r.cell >= r.batch.NumCells {
+			batch, err := r.reader.Next()
+			if err != nil {
+				if err == io.EOF {
+					r.done = true
+				}
+				return err
+			}
+			r.batch = batch
+			r.cell = 0
+			continue
+		}
+
+		i := r.cell
+		r.cell++
+
+		match := true
+		for _, p := range r.predicates {
+			field, err := r.batch.Field(p.column)
+			if err != nil {
+				return err
+			}
+
+			value, err := cellValue(r.batch, field, i)
+			if err != nil {
+				return err
+			}
+
+			match, err = p.matches(value)
+			if err != nil {
+				return err
+			}
+			if !match {
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+
+		for j, column := range r.columns {
+			field, err := r.batch.Field(column)
+			if err != nil {
+				return err
+			}
+
+			dest[j], err = cellValue(r.batch, field, i)
+			if err != nil {
+				return err
+			}
+		}
+
+		r.returned++
+		if r.limit >= 0 && r.returned >= r.limit {
+			r.done = true
+		}
+		return nil
+	}
+}
@@ -0,0 +1,225 @@
+package tiledb
+
+/*
+#cgo LDFLAGS: -ltiledb
+#cgo linux LDFLAGS: -ldl
+#include <tiledb/tiledb.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// The typed wrappers of typed_generated.go (SetBufferInt32, BufferFloat64,
+// AddRangeInt64, DomainUint64, ...) are generated from gen_typed.go
+//go:generate go run gen_typed.go
+
+// checkKind returns an error if values of datatype are not stored as kind
+func checkKind(name string, datatype Datatype, kind reflect.Kind) error {
+	if datatype.ReflectKind() != kind {
+		return fmt.Errorf("%s has datatype %s, which is not stored as %s", name, datatype.String(), kind.String())
+	}
+	return nil
+}
+
+// fieldDatatype returns the datatype of an attribute or dimension of the
+// array of the query
+func (q *Query) fieldDatatype(attributeOrDimension string) (Datatype, error) {
+	schema, err := q.array.Schema()
+	if err != nil {
+		return 0, err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return 0, err
+	}
+	defer domain.Free()
+
+	if attributeOrDimension == TILEDB_COORDS {
+		return domain.Type()
+	}
+
+	hasDim, err := domain.HasDimension(attributeOrDimension)
+	if err != nil {
+		return 0, err
+	}
+	if hasDim {
+		dimension, err := domain.DimensionFromName(attributeOrDimension)
+		if err != nil {
+			return 0, err
+		}
+		defer dimension.Free()
+		return dimension.Type()
+	}
+
+	attribute, err := schema.AttributeFromName(attributeOrDimension)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not an attribute or dimension of the array", attributeOrDimension)
+	}
+	defer attribute.Free()
+	return attribute.Type()
+}
+
+// setTypedBuffer sets the buffer of a fixed sized attribute or dimension
+// after checking its datatype against kind. buffer is kept referenced by the
+// query so the underlying array is not gc'ed.
+func (q *Query) setTypedBuffer(attributeOrDimension string, kind reflect.Kind, buffer interface{}, cbuffer unsafe.Pointer, bufferSize uint64) (*uint64, error) {
+	if bufferSize == 0 {
+		return nil, fmt.Errorf("Buffer has no length, buffers are required to be initialized before reading or writing")
+	}
+
+	datatype, err := q.fieldDatatype(attributeOrDimension)
+	if err != nil {
+		return nil, fmt.Errorf("Error setting query buffer: %s", err)
+	}
+	err = checkKind(attributeOrDimension, datatype, kind)
+	if err != nil {
+		return nil, fmt.Errorf("Error setting query buffer: %s", err)
+	}
+
+	q.bufferMutex.Lock()
+	defer q.bufferMutex.Unlock()
+	q.buffers = append(q.buffers, buffer)
+
+	return q.SetBufferUnsafe(attributeOrDimension, cbuffer, bufferSize)
+}
+
+// typedBuffer returns the pointer to the buffer of an attribute or
+// dimension and its size in bytes, after checking its datatype against kind
+func (q *Query) typedBuffer(attributeOrDimension string, kind reflect.Kind) (unsafe.Pointer, uint64, error) {
+	datatype, err := q.fieldDatatype(attributeOrDimension)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Error getting tiledb query buffer for %s: %s", attributeOrDimension, err)
+	}
+	err = checkKind(attributeOrDimension, datatype, kind)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Error getting tiledb query buffer for %s: %s", attributeOrDimension, err)
+	}
+
+	cAttributeOrDimension := C.CString(attributeOrDimension)
+	defer C.free(unsafe.Pointer(cAttributeOrDimension))
+
+	var cbuffer unsafe.Pointer
+	var cbufferSize *C.uint64_t
+	ret := C.tiledb_query_get_buffer(q.context.tiledbContext, q.tiledbQuery, cAttributeOrDimension, &cbuffer, &cbufferSize)
+	if ret != C.TILEDB_OK {
+		return nil, 0, fmt.Errorf("Error getting tiledb query buffer for %s: %s", attributeOrDimension, q.context.LastError())
+	}
+	if cbuffer == nil || cbufferSize == nil {
+		return nil, 0, nil
+	}
+	return cbuffer, uint64(*cbufferSize), nil
+}
+
+// addTypedRange adds a range on a dimension after checking its datatype
+// against kind
+func (q *Query) addTypedRange(dimIdx uint32, kind reflect.Kind, start unsafe.Pointer, end unsafe.Pointer) error {
+	schema, err := q.array.Schema()
+	if err != nil {
+		return err
+	}
+	defer schema.Free()
+
+	domain, err := schema.Domain()
+	if err != nil {
+		return err
+	}
+	defer domain.Free()
+
+	dimension, err := domain.DimensionFromIndex(uint(dimIdx))
+	if err != nil {
+		return err
+	}
+	defer dimension.Free()
+
+	datatype, err := dimension.Type()
+	if err != nil {
+		return err
+	}
+	err = checkKind(fmt.Sprintf("Dimension %d", dimIdx), datatype, kind)
+	if err != nil {
+		return fmt.Errorf("Error adding query range: %s", err)
+	}
+
+	ret := C.tiledb_query_add_range(
+		q.context.tiledbContext, q.tiledbQuery,
+		(C.uint32_t)(dimIdx), start, end, nil)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("Error adding query range: %s", q.context.LastError())
+	}
+	return nil
+}
+
+// typedDomain returns the pointer to the [lower, upper] domain of the
+// dimension after checking its datatype against kind
+func (d *Dimension) typedDomain(kind reflect.Kind) (unsafe.Pointer, error) {
+	datatype, err := d.Type()
+	if err != nil {
+		return nil, err
+	}
+	err = checkKind("Dimension", datatype, kind)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting tiledb dimension's domain: %s", err)
+	}
+
+	var cdomain unsafe.Pointer
+	ret := C.tiledb_dimension_get_domain(d.context.tiledbContext, d.tiledbDimension, &cdomain)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("Error getting tiledb dimension's domain: %s", d.context.LastError())
+	}
+	return cdomain, nil
+}
+
+// typedExtent returns the pointer to the tile extent of the dimension after
+// checking its datatype against kind
+func (d *Dimension) typedExtent(kind reflect.Kind) (unsafe.Pointer, error) {
+	datatype, err := d.Type()
+	if err != nil {
+		return nil, err
+	}
+	err = checkKind("Dimension", datatype, kind)
+	if err != nil {
+		return nil, fmt.Errorf("Error getting tiledb dimension's extent: %s", err)
+	}
+
+	var cextent unsafe.Pointer
+	ret := C.tiledb_dimension_get_tile_extent(d.context.tiledbContext, d.tiledbDimension, &cextent)
+	if ret != C.TILEDB_OK {
+		return nil, fmt.Errorf("Error getting tiledb dimension's extent: %s", d.context.LastError())
+	}
+	if cextent == nil {
+		return nil, fmt.Errorf("Error getting tiledb dimension's extent: the dimension has no extent")
+	}
+	return cextent, nil
+}
+
+// typedMetadata returns the pointer to the values of a metadata item and
+// their number, after checking its datatype against kind
+func (a *Array) typedMetadata(key string, kind reflect.Kind) (unsafe.Pointer, uint, error) {
+	ckey := C.CString(key)
+	defer C.free(unsafe.Pointer(ckey))
+
+	var cType C.tiledb_datatype_t
+	var cValueNum C.uint
+	var cvalue unsafe.Pointer
+
+	ret := C.tiledb_array_get_metadata(a.context.tiledbContext, a.tiledbArray, ckey, &cType, &cValueNum, &cvalue)
+	if ret != C.TILEDB_OK {
+		return nil, 0, fmt.Errorf("Error getting metadata from array: %s, key: %s", a.context.LastError(), key)
+	}
+	if cValueNum == 0 {
+		return nil, 0, fmt.Errorf("Error getting metadata from array, key: %s does not exist", key)
+	}
+
+	err := checkKind("Metadata "+key, Datatype(cType), kind)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Error getting metadata from array: %s", err)
+	}
+	return cvalue, uint(cValueNum), nil
+}
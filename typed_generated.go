@@ -0,0 +1,608 @@
+// Code generated by gen_typed.go; DO NOT EDIT.
+
+package tiledb
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// SetBufferInt8 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_INT8
+func (q *Query) SetBufferInt8(attributeOrDimension string, buffer []int8) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Int8, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Int8, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferInt8 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_INT8
+func (q *Query) BufferInt8(attributeOrDimension string) ([]int8, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Int8)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(int8(0)))
+	return (*[1 << 46]int8)(cbuffer)[:length:length], nil
+}
+
+// AddRangeInt8 adds the range [start, end] on a dimension of datatype
+// TILEDB_INT8
+func (q *Query) AddRangeInt8(dimIdx uint32, start int8, end int8) error {
+	return q.addTypedRange(dimIdx, reflect.Int8, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainInt8 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_INT8
+func (d *Dimension) DomainInt8() ([]int8, error) {
+	cdomain, err := d.typedDomain(reflect.Int8)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]int8)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentInt8 returns the tile extent of a dimension of datatype
+// TILEDB_INT8
+func (d *Dimension) ExtentInt8() (int8, error) {
+	cextent, err := d.typedExtent(reflect.Int8)
+	if err != nil {
+		return 0, err
+	}
+	return *(*int8)(cextent), nil
+}
+
+// GetMetadataInt8 returns the values of a metadata item of datatype
+// TILEDB_INT8
+func (a *Array) GetMetadataInt8(key string) ([]int8, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Int8)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]int8, valueNum)
+	copy(value, (*[1 << 46]int8)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferInt16 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_INT16
+func (q *Query) SetBufferInt16(attributeOrDimension string, buffer []int16) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Int16, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Int16, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferInt16 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_INT16
+func (q *Query) BufferInt16(attributeOrDimension string) ([]int16, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Int16)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(int16(0)))
+	return (*[1 << 46]int16)(cbuffer)[:length:length], nil
+}
+
+// AddRangeInt16 adds the range [start, end] on a dimension of datatype
+// TILEDB_INT16
+func (q *Query) AddRangeInt16(dimIdx uint32, start int16, end int16) error {
+	return q.addTypedRange(dimIdx, reflect.Int16, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainInt16 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_INT16
+func (d *Dimension) DomainInt16() ([]int16, error) {
+	cdomain, err := d.typedDomain(reflect.Int16)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]int16)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentInt16 returns the tile extent of a dimension of datatype
+// TILEDB_INT16
+func (d *Dimension) ExtentInt16() (int16, error) {
+	cextent, err := d.typedExtent(reflect.Int16)
+	if err != nil {
+		return 0, err
+	}
+	return *(*int16)(cextent), nil
+}
+
+// GetMetadataInt16 returns the values of a metadata item of datatype
+// TILEDB_INT16
+func (a *Array) GetMetadataInt16(key string) ([]int16, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Int16)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]int16, valueNum)
+	copy(value, (*[1 << 46]int16)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferInt32 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_INT32
+func (q *Query) SetBufferInt32(attributeOrDimension string, buffer []int32) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Int32, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Int32, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferInt32 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_INT32
+func (q *Query) BufferInt32(attributeOrDimension string) ([]int32, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Int32)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(int32(0)))
+	return (*[1 << 46]int32)(cbuffer)[:length:length], nil
+}
+
+// AddRangeInt32 adds the range [start, end] on a dimension of datatype
+// TILEDB_INT32
+func (q *Query) AddRangeInt32(dimIdx uint32, start int32, end int32) error {
+	return q.addTypedRange(dimIdx, reflect.Int32, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainInt32 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_INT32
+func (d *Dimension) DomainInt32() ([]int32, error) {
+	cdomain, err := d.typedDomain(reflect.Int32)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]int32)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentInt32 returns the tile extent of a dimension of datatype
+// TILEDB_INT32
+func (d *Dimension) ExtentInt32() (int32, error) {
+	cextent, err := d.typedExtent(reflect.Int32)
+	if err != nil {
+		return 0, err
+	}
+	return *(*int32)(cextent), nil
+}
+
+// GetMetadataInt32 returns the values of a metadata item of datatype
+// TILEDB_INT32
+func (a *Array) GetMetadataInt32(key string) ([]int32, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Int32)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]int32, valueNum)
+	copy(value, (*[1 << 46]int32)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferInt64 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_INT64 and TILEDB_DATETIME_*
+func (q *Query) SetBufferInt64(attributeOrDimension string, buffer []int64) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Int64, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Int64, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferInt64 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_INT64 and TILEDB_DATETIME_*
+func (q *Query) BufferInt64(attributeOrDimension string) ([]int64, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Int64)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(int64(0)))
+	return (*[1 << 46]int64)(cbuffer)[:length:length], nil
+}
+
+// AddRangeInt64 adds the range [start, end] on a dimension of datatype
+// TILEDB_INT64 and TILEDB_DATETIME_*
+func (q *Query) AddRangeInt64(dimIdx uint32, start int64, end int64) error {
+	return q.addTypedRange(dimIdx, reflect.Int64, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainInt64 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_INT64 and TILEDB_DATETIME_*
+func (d *Dimension) DomainInt64() ([]int64, error) {
+	cdomain, err := d.typedDomain(reflect.Int64)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]int64)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentInt64 returns the tile extent of a dimension of datatype
+// TILEDB_INT64 and TILEDB_DATETIME_*
+func (d *Dimension) ExtentInt64() (int64, error) {
+	cextent, err := d.typedExtent(reflect.Int64)
+	if err != nil {
+		return 0, err
+	}
+	return *(*int64)(cextent), nil
+}
+
+// GetMetadataInt64 returns the values of a metadata item of datatype
+// TILEDB_INT64 and TILEDB_DATETIME_*
+func (a *Array) GetMetadataInt64(key string) ([]int64, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Int64)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]int64, valueNum)
+	copy(value, (*[1 << 46]int64)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferUint8 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_UINT8, TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8
+func (q *Query) SetBufferUint8(attributeOrDimension string, buffer []uint8) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Uint8, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Uint8, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferUint8 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_UINT8, TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8
+func (q *Query) BufferUint8(attributeOrDimension string) ([]uint8, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Uint8)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(uint8(0)))
+	return (*[1 << 46]uint8)(cbuffer)[:length:length], nil
+}
+
+// AddRangeUint8 adds the range [start, end] on a dimension of datatype
+// TILEDB_UINT8, TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8
+func (q *Query) AddRangeUint8(dimIdx uint32, start uint8, end uint8) error {
+	return q.addTypedRange(dimIdx, reflect.Uint8, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainUint8 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_UINT8, TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8
+func (d *Dimension) DomainUint8() ([]uint8, error) {
+	cdomain, err := d.typedDomain(reflect.Uint8)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]uint8)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentUint8 returns the tile extent of a dimension of datatype
+// TILEDB_UINT8, TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8
+func (d *Dimension) ExtentUint8() (uint8, error) {
+	cextent, err := d.typedExtent(reflect.Uint8)
+	if err != nil {
+		return 0, err
+	}
+	return *(*uint8)(cextent), nil
+}
+
+// GetMetadataUint8 returns the values of a metadata item of datatype
+// TILEDB_UINT8, TILEDB_CHAR, TILEDB_STRING_ASCII and TILEDB_STRING_UTF8
+func (a *Array) GetMetadataUint8(key string) ([]uint8, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Uint8)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]uint8, valueNum)
+	copy(value, (*[1 << 46]uint8)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferUint16 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_UINT16, TILEDB_STRING_UTF16 and TILEDB_STRING_UCS2
+func (q *Query) SetBufferUint16(attributeOrDimension string, buffer []uint16) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Uint16, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Uint16, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferUint16 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_UINT16, TILEDB_STRING_UTF16 and TILEDB_STRING_UCS2
+func (q *Query) BufferUint16(attributeOrDimension string) ([]uint16, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Uint16)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(uint16(0)))
+	return (*[1 << 46]uint16)(cbuffer)[:length:length], nil
+}
+
+// AddRangeUint16 adds the range [start, end] on a dimension of datatype
+// TILEDB_UINT16, TILEDB_STRING_UTF16 and TILEDB_STRING_UCS2
+func (q *Query) AddRangeUint16(dimIdx uint32, start uint16, end uint16) error {
+	return q.addTypedRange(dimIdx, reflect.Uint16, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainUint16 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_UINT16, TILEDB_STRING_UTF16 and TILEDB_STRING_UCS2
+func (d *Dimension) DomainUint16() ([]uint16, error) {
+	cdomain, err := d.typedDomain(reflect.Uint16)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]uint16)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentUint16 returns the tile extent of a dimension of datatype
+// TILEDB_UINT16, TILEDB_STRING_UTF16 and TILEDB_STRING_UCS2
+func (d *Dimension) ExtentUint16() (uint16, error) {
+	cextent, err := d.typedExtent(reflect.Uint16)
+	if err != nil {
+		return 0, err
+	}
+	return *(*uint16)(cextent), nil
+}
+
+// GetMetadataUint16 returns the values of a metadata item of datatype
+// TILEDB_UINT16, TILEDB_STRING_UTF16 and TILEDB_STRING_UCS2
+func (a *Array) GetMetadataUint16(key string) ([]uint16, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Uint16)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]uint16, valueNum)
+	copy(value, (*[1 << 46]uint16)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferUint32 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_UINT32, TILEDB_STRING_UTF32 and TILEDB_STRING_UCS4
+func (q *Query) SetBufferUint32(attributeOrDimension string, buffer []uint32) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Uint32, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Uint32, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferUint32 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_UINT32, TILEDB_STRING_UTF32 and TILEDB_STRING_UCS4
+func (q *Query) BufferUint32(attributeOrDimension string) ([]uint32, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Uint32)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(uint32(0)))
+	return (*[1 << 46]uint32)(cbuffer)[:length:length], nil
+}
+
+// AddRangeUint32 adds the range [start, end] on a dimension of datatype
+// TILEDB_UINT32, TILEDB_STRING_UTF32 and TILEDB_STRING_UCS4
+func (q *Query) AddRangeUint32(dimIdx uint32, start uint32, end uint32) error {
+	return q.addTypedRange(dimIdx, reflect.Uint32, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainUint32 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_UINT32, TILEDB_STRING_UTF32 and TILEDB_STRING_UCS4
+func (d *Dimension) DomainUint32() ([]uint32, error) {
+	cdomain, err := d.typedDomain(reflect.Uint32)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]uint32)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentUint32 returns the tile extent of a dimension of datatype
+// TILEDB_UINT32, TILEDB_STRING_UTF32 and TILEDB_STRING_UCS4
+func (d *Dimension) ExtentUint32() (uint32, error) {
+	cextent, err := d.typedExtent(reflect.Uint32)
+	if err != nil {
+		return 0, err
+	}
+	return *(*uint32)(cextent), nil
+}
+
+// GetMetadataUint32 returns the values of a metadata item of datatype
+// TILEDB_UINT32, TILEDB_STRING_UTF32 and TILEDB_STRING_UCS4
+func (a *Array) GetMetadataUint32(key string) ([]uint32, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Uint32)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]uint32, valueNum)
+	copy(value, (*[1 << 46]uint32)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferUint64 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_UINT64
+func (q *Query) SetBufferUint64(attributeOrDimension string, buffer []uint64) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Uint64, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Uint64, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferUint64 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_UINT64
+func (q *Query) BufferUint64(attributeOrDimension string) ([]uint64, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Uint64)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(uint64(0)))
+	return (*[1 << 46]uint64)(cbuffer)[:length:length], nil
+}
+
+// AddRangeUint64 adds the range [start, end] on a dimension of datatype
+// TILEDB_UINT64
+func (q *Query) AddRangeUint64(dimIdx uint32, start uint64, end uint64) error {
+	return q.addTypedRange(dimIdx, reflect.Uint64, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainUint64 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_UINT64
+func (d *Dimension) DomainUint64() ([]uint64, error) {
+	cdomain, err := d.typedDomain(reflect.Uint64)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]uint64)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentUint64 returns the tile extent of a dimension of datatype
+// TILEDB_UINT64
+func (d *Dimension) ExtentUint64() (uint64, error) {
+	cextent, err := d.typedExtent(reflect.Uint64)
+	if err != nil {
+		return 0, err
+	}
+	return *(*uint64)(cextent), nil
+}
+
+// GetMetadataUint64 returns the values of a metadata item of datatype
+// TILEDB_UINT64
+func (a *Array) GetMetadataUint64(key string) ([]uint64, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Uint64)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]uint64, valueNum)
+	copy(value, (*[1 << 46]uint64)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferFloat32 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_FLOAT32
+func (q *Query) SetBufferFloat32(attributeOrDimension string, buffer []float32) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Float32, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Float32, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferFloat32 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_FLOAT32
+func (q *Query) BufferFloat32(attributeOrDimension string) ([]float32, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Float32)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(float32(0)))
+	return (*[1 << 46]float32)(cbuffer)[:length:length], nil
+}
+
+// AddRangeFloat32 adds the range [start, end] on a dimension of datatype
+// TILEDB_FLOAT32
+func (q *Query) AddRangeFloat32(dimIdx uint32, start float32, end float32) error {
+	return q.addTypedRange(dimIdx, reflect.Float32, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainFloat32 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_FLOAT32
+func (d *Dimension) DomainFloat32() ([]float32, error) {
+	cdomain, err := d.typedDomain(reflect.Float32)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]float32)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentFloat32 returns the tile extent of a dimension of datatype
+// TILEDB_FLOAT32
+func (d *Dimension) ExtentFloat32() (float32, error) {
+	cextent, err := d.typedExtent(reflect.Float32)
+	if err != nil {
+		return 0, err
+	}
+	return *(*float32)(cextent), nil
+}
+
+// GetMetadataFloat32 returns the values of a metadata item of datatype
+// TILEDB_FLOAT32
+func (a *Array) GetMetadataFloat32(key string) ([]float32, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Float32)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]float32, valueNum)
+	copy(value, (*[1 << 46]float32)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
+
+// SetBufferFloat64 sets the buffer of a fixed sized attribute or dimension
+// of datatype TILEDB_FLOAT64
+func (q *Query) SetBufferFloat64(attributeOrDimension string, buffer []float64) (*uint64, error) {
+	if len(buffer) == 0 {
+		return q.setTypedBuffer(attributeOrDimension, reflect.Float64, buffer, nil, 0)
+	}
+	return q.setTypedBuffer(attributeOrDimension, reflect.Float64, buffer,
+		unsafe.Pointer(&buffer[0]), uint64(len(buffer))*uint64(unsafe.Sizeof(buffer[0])))
+}
+
+// BufferFloat64 returns a slice backed by the underlying c buffer of an
+// attribute or dimension of datatype TILEDB_FLOAT64
+func (q *Query) BufferFloat64(attributeOrDimension string) ([]float64, error) {
+	cbuffer, size, err := q.typedBuffer(attributeOrDimension, reflect.Float64)
+	if err != nil || cbuffer == nil {
+		return nil, err
+	}
+	length := size / uint64(unsafe.Sizeof(float64(0)))
+	return (*[1 << 46]float64)(cbuffer)[:length:length], nil
+}
+
+// AddRangeFloat64 adds the range [start, end] on a dimension of datatype
+// TILEDB_FLOAT64
+func (q *Query) AddRangeFloat64(dimIdx uint32, start float64, end float64) error {
+	return q.addTypedRange(dimIdx, reflect.Float64, unsafe.Pointer(&start), unsafe.Pointer(&end))
+}
+
+// DomainFloat64 returns the [lower, upper] domain of a dimension of datatype
+// TILEDB_FLOAT64
+func (d *Dimension) DomainFloat64() ([]float64, error) {
+	cdomain, err := d.typedDomain(reflect.Float64)
+	if err != nil {
+		return nil, err
+	}
+	domain := *(*[2]float64)(cdomain)
+	return domain[:], nil
+}
+
+// ExtentFloat64 returns the tile extent of a dimension of datatype
+// TILEDB_FLOAT64
+func (d *Dimension) ExtentFloat64() (float64, error) {
+	cextent, err := d.typedExtent(reflect.Float64)
+	if err != nil {
+		return 0, err
+	}
+	return *(*float64)(cextent), nil
+}
+
+// GetMetadataFloat64 returns the values of a metadata item of datatype
+// TILEDB_FLOAT64
+func (a *Array) GetMetadataFloat64(key string) ([]float64, error) {
+	cvalue, valueNum, err := a.typedMetadata(key, reflect.Float64)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]float64, valueNum)
+	copy(value, (*[1 << 46]float64)(cvalue)[:valueNum:valueNum])
+	return value, nil
+}
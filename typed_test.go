@@ -0,0 +1,82 @@
+package tiledb
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedWrappers(t *testing.T) {
+	context, err := NewContext(nil)
+	assert.Nil(t, err)
+
+	tmpArrayPath := path.Join(os.TempDir(), "tiledb_test_typed_wrappers")
+	defer os.RemoveAll(tmpArrayPath)
+	if _, err = os.Stat(tmpArrayPath); err == nil {
+		os.RemoveAll(tmpArrayPath)
+	}
+	createBatchTestArray(t, context, tmpArrayPath)
+
+	array, err := NewArray(context, tmpArrayPath)
+	assert.Nil(t, err)
+	assert.Nil(t, array.Open(TILEDB_WRITE))
+	assert.Nil(t, array.PutMetadata("scale", []float64{0.5, 2}))
+	assert.Nil(t, array.Close())
+
+	assert.Nil(t, array.Open(TILEDB_READ))
+	defer array.Close()
+
+	scale, err := array.GetMetadataFloat64("scale")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.5, 2}, scale)
+	_, err = array.GetMetadataInt32("scale")
+	assert.NotNil(t, err)
+
+	schema, err := array.Schema()
+	assert.Nil(t, err)
+	domain, err := schema.Domain()
+	assert.Nil(t, err)
+	rows, err := domain.DimensionFromName("rows")
+	assert.Nil(t, err)
+
+	rowsDomain, err := rows.DomainInt32()
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{1, 4}, rowsDomain)
+	extent, err := rows.ExtentInt32()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), extent)
+	_, err = rows.DomainInt64()
+	assert.NotNil(t, err)
+
+	query, err := NewQuery(context, array)
+	assert.Nil(t, err)
+	defer query.Free()
+	assert.Nil(t, query.SetLayout(TILEDB_ROW_MAJOR))
+	assert.Nil(t, query.AddRangeInt32(0, 2, 2))
+	assert.Nil(t, query.AddRangeInt32(1, 1, 4))
+	assert.NotNil(t, query.AddRangeInt64(1, 1, 4))
+
+	_, err = query.SetBufferInt32("cols", make([]int32, 4))
+	assert.Nil(t, err)
+	_, err = query.SetBufferInt32("a1", make([]int32, 4))
+	assert.Nil(t, err)
+	// Datetimes are stored as int64
+	_, err = query.SetBufferInt64("a3", make([]int64, 4))
+	assert.Nil(t, err)
+	_, err = query.SetBufferFloat64("a1", make([]float64, 4))
+	assert.NotNil(t, err)
+	assert.Nil(t, query.Submit())
+
+	elements, err := query.ResultBufferElements()
+	assert.Nil(t, err)
+	a1, err := query.BufferInt32("a1")
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{2, 3}, a1[:elements["a1"][1]])
+	a3, err := query.BufferInt64("a3")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{18263, 18264}, a3[:elements["a3"][1]])
+	_, err = query.BufferUint8("a1")
+	assert.NotNil(t, err)
+}
@@ -4,12 +4,20 @@ package tiledb
 #cgo LDFLAGS: -ltiledb
 #cgo linux LDFLAGS: -ldl
 #include <tiledb/tiledb.h>
+#include <stdint.h>
 #include <stdlib.h>
+
+extern int32_t vfsLsCallback(char* path, void* data);
+
+static int32_t _tiledb_vfs_ls(tiledb_ctx_t* ctx, tiledb_vfs_t* vfs, const char* path, void* data) {
+	return tiledb_vfs_ls(ctx, vfs, path, (int32_t (*)(const char*, void*))vfsLsCallback, data);
+}
 */
 import "C"
 
 import (
 	"fmt"
+	"io"
 	"runtime"
 	"unsafe"
 )
@@ -23,6 +31,7 @@ type VFSfh struct {
 // Free a tiledb c vfs file handler
 func (v *VFSfh) Free() {
 	if v.tiledbVFSfh != nil {
+		untrackHandle(unsafe.Pointer(v.tiledbVFSfh))
 		C.tiledb_vfs_fh_free(&v.tiledbVFSfh)
 	}
 }
@@ -45,6 +54,22 @@ func (v *VFSfh) IsClosed() (bool, error) {
 	return false, nil
 }
 
+// Close closes and frees the file handler, flushing the data written to the
+// file, implementing io.Closer. Closing twice is a no-op.
+func (v *VFSfh) Close() error {
+	if v.tiledbVFSfh == nil {
+		return nil
+	}
+
+	ret := C.tiledb_vfs_close(v.context.tiledbContext, v.tiledbVFSfh)
+	if ret != C.TILEDB_OK {
+		return fmt.Errorf("Error closing vfs file handler: %s", v.context.LastError())
+	}
+
+	v.Free()
+	return nil
+}
+
 // VFS Implements a virtual filesystem that enables performing directory/file
 // operations with a unified API on different filesystems, such as local
 // posix/windows, HDFS, AWS S3, etc.
@@ -67,6 +92,7 @@ func NewVFS(context *Context, config *Config) (*VFS, error) {
 		defer C.tiledb_error_free(&err)
 		return nil, fmt.Errorf("Error creating tiledb context: %s", C.GoString(msg))
 	}
+	trackHandle("tiledb_vfs_t", unsafe.Pointer(vfs.tiledbVFS))
 
 	// Set finalizer for free C pointer on gc
 	runtime.SetFinalizer(&vfs, func(vfs *VFS) {
@@ -79,10 +105,22 @@ func NewVFS(context *Context, config *Config) (*VFS, error) {
 // Free tiledb_vfs_t c structure that was allocated on the heap
 func (v *VFS) Free() {
 	if v.tiledbVFS != nil {
+		untrackHandle(unsafe.Pointer(v.tiledbVFS))
 		C.tiledb_vfs_free(&v.tiledbVFS)
 	}
 }
 
+// Closer returns an io.Closer freeing the VFS, as VFS.Close closes files.
+// Closing twice is a no-op.
+//
+//	defer vfs.Closer().Close()
+func (v *VFS) Closer() io.Closer {
+	return closerFunc(func() error {
+		v.Free()
+		return nil
+	})
+}
+
 // Config retrieves a copy of the config from vfs
 func (v *VFS) Config() (*Config, error) {
 	config := &Config{}
@@ -94,6 +132,7 @@ func (v *VFS) Config() (*Config, error) {
 	} else if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Unknown error in GetConfig")
 	}
+	trackHandle("tiledb_config_t", unsafe.Pointer(config.tiledbConfig))
 
 	return config, nil
 }
@@ -186,6 +225,41 @@ func (v *VFS) CreateDir(uri string) error {
 	return nil
 }
 
+// List returns the URIs of the directories and files directly contained in
+// the directory with the input URI
+func (v *VFS) List(uri string) ([]string, []string, error) {
+	paths := make([]string, 0)
+	data := registerCallback(func(path string) bool {
+		paths = append(paths, path)
+		return true
+	})
+	defer unregisterCallback(data)
+
+	curi := C.CString(uri)
+	defer C.free(unsafe.Pointer(curi))
+	ret := C._tiledb_vfs_ls(v.context.tiledbContext, v.tiledbVFS, curi, data)
+
+	if ret != C.TILEDB_OK {
+		return nil, nil, fmt.Errorf("Error in listing directory %s: %s", uri, v.context.LastError())
+	}
+
+	dirs := make([]string, 0)
+	files := make([]string, 0)
+	for _, path := range paths {
+		isDir, err := v.IsDir(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if isDir {
+			dirs = append(dirs, path)
+		} else {
+			files = append(files, path)
+		}
+	}
+
+	return dirs, files, nil
+}
+
 // IsDir checks if a directory with the input URI exists.
 func (v *VFS) IsDir(uri string) (bool, error) {
 	curi := C.CString(uri)
@@ -311,6 +385,7 @@ func (v *VFS) Open(uri string, mode VFSMode) (*VFSfh, error) {
 	} else if ret != C.TILEDB_OK {
 		return nil, fmt.Errorf("Unknown error in VFS.Open: %s", v.context.LastError())
 	}
+	trackHandle("tiledb_vfs_fh_t", unsafe.Pointer(fh.tiledbVFSfh))
 
 	return fh, nil
 }
@@ -319,6 +394,9 @@ func (v *VFS) Open(uri string, mode VFSMode) (*VFSfh, error) {
 // was opened in write (or append) mode. It is particularly important to be
 // called after S3 writes, as otherwise the writes will not take effect.
 func (v *VFS) Close(fh *VFSfh) error {
+	if fh.tiledbVFSfh == nil {
+		return nil
+	}
 
 	ret := C.tiledb_vfs_close(v.context.tiledbContext, fh.tiledbVFSfh)
 
@@ -3,6 +3,7 @@ package tiledb
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,6 +67,12 @@ func TestVFS(t *testing.T) {
 	assert.Nil(t, err)
 	assert.EqualValues(t, 3, dirSize)
 
+	dirs, files, err := vfs.List(tmpPath)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(dirs))
+	assert.Equal(t, 1, len(files))
+	assert.True(t, strings.HasSuffix(files[0], "file_test"))
+
 	// Remove File
 	err = vfs.RemoveFile(tmpFilePath)
 	assert.Nil(t, err)